@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/dmoose/tokenctl/pkg/colors"
 	"github.com/dmoose/tokenctl/pkg/generators"
 	"github.com/dmoose/tokenctl/pkg/tokens"
 	"github.com/spf13/cobra"
@@ -37,11 +38,45 @@ Flags:
                         tokenctl does not consume (misnamed component
                         sub-blocks, unread $metadata, variants/sizes/states
                         entries with no $class). These warn by default.
+  --dimension-unit      Rewrite length tokens to px or rem in the output,
+                        converting rem at the root $rootFontSize (16px default)
+  --references          inline (default) writes every resolved value;
+                        preserve emits aliases as var(--target) so runtime
+                        overrides cascade (tailwind and css formats)
+  --gamut-map           Map colors outside srgb or display-p3 into it by
+                        reducing OKLCH chroma, keeping lightness and hue,
+                        instead of leaving browsers to clip them
+  --color-format        Write every color (tokens, themes, keyframes and
+                        component literals) as hex, rgb, hsl, oklch or p3;
+                        a group's $colorFormat overrides it for its tokens
+  --color-precision     Decimal places for --color-format channels
+                        (default: each format's own)
+  --color-fallback      supports writes oklch(), lab() and color() values
+                        as sRGB hex and restates them under
+                        @supports (color: oklch(0 0 0)); gamut does so
+                        only for colors outside sRGB, under
+                        @media (color-gamut: p3) (tailwind and css formats)
+  --color-scheme        attribute (default) selects themes by data-theme only;
+                        media also applies the $colorScheme dark (or light)
+                        theme under prefers-color-scheme when no data-theme
+                        is set; light-dark writes colors that differ between
+                        the light and dark themes as light-dark() on :root
+                        (tailwind and css formats)
+  --theme-selector      Selector template for themes, {name} standing for
+                        the theme name: .theme-{name}, [data-mode={name}].
+                        The default theme keeps :root; a theme file's
+                        $selector and $media override it (tailwind and css
+                        formats)
+  --report              Write diagnostics as json, sarif or junit, to
+                        --report-file or stdout; progress and summary
+                        messages then go to stderr, while generated files
+                        are still written to --output
 
 Examples:
   tokenctl build ./my-tokens --format=tailwind
   tokenctl build ./base-tokens ./dashboard-tokens
-  tokenctl build ./my-tokens --format=manifest:color --customizable-only`,
+  tokenctl build ./my-tokens --format=manifest:color --customizable-only
+  tokenctl build ./my-tokens --format=css --references=preserve`,
 	Args: cobra.ArbitraryArgs,
 	RunE: runBuild,
 }
@@ -52,6 +87,14 @@ var (
 	customizableOnly  bool
 	strictUnknownKeys bool
 	generatedAt       string
+	dimensionUnit     string
+	references        string
+	gamutMap          string
+	colorFormat       string
+	colorPrecision    int
+	colorFallback     string
+	colorScheme       string
+	themeSelector     string
 )
 
 func init() {
@@ -60,6 +103,15 @@ func init() {
 	buildCmd.Flags().BoolVar(&customizableOnly, "customizable-only", false, "Only include tokens marked $customizable: true (manifest/catalog only)")
 	buildCmd.Flags().BoolVar(&strictUnknownKeys, "strict-unknown-keys", false, "Fail the build on input tokenctl does not consume (default: warn)")
 	buildCmd.Flags().StringVar(&generatedAt, "generated-at", "", "Stamp meta.generated_at in catalog/manifest output: `now` for the current UTC time, or a literal string. Off by default so the same tokens produce the same bytes.")
+	buildCmd.Flags().StringVar(&dimensionUnit, "dimension-unit", "", "Normalize dimension tokens to one unit (px or rem)")
+	buildCmd.Flags().StringVar(&references, "references", tokens.ReferencesInline, "How references are emitted in CSS output (inline, preserve)")
+	buildCmd.Flags().StringVar(&gamutMap, "gamut-map", "", "Map out-of-gamut colors into a gamut (srgb, display-p3)")
+	buildCmd.Flags().StringVar(&colorFormat, "color-format", "", "Write all colors in one format (hex, rgb, hsl, oklch, p3, ...)")
+	buildCmd.Flags().IntVar(&colorPrecision, "color-precision", -1, "Decimal places for --color-format channels (-1 keeps each format's default)")
+	buildCmd.Flags().StringVar(&colorFallback, "color-fallback", "", "Give wide-gamut colors an sRGB fallback (supports, gamut)")
+	buildCmd.Flags().StringVar(&colorScheme, "color-scheme", generators.ColorSchemeAttribute, "How themes follow the OS color scheme (attribute, media, light-dark)")
+	buildCmd.Flags().StringVar(&themeSelector, "theme-selector", "", "Selector template for themes, {name} standing for the theme name (default [data-theme=\"{name}\"])")
+	addReportFlags(buildCmd)
 	rootCmd.AddCommand(buildCmd)
 }
 
@@ -82,33 +134,107 @@ func parseFormat(format string) (formatType string, category string, err error)
 }
 
 func runBuild(cmd *cobra.Command, args []string) error {
+	if err := checkReportFlags(); err != nil {
+		return err
+	}
+	if gamutMap != "" {
+		gamut, err := colors.ParseGamut(gamutMap)
+		if err != nil {
+			return fmt.Errorf("--gamut-map: %w", err)
+		}
+		gamutMap = gamut
+	}
+	if colorFormat != "" {
+		f, err := colors.ParseFormat(colorFormat)
+		if err != nil {
+			return fmt.Errorf("--color-format: %w", err)
+		}
+		colorFormat = f
+	}
 	dirs := args
 	if len(dirs) == 0 {
 		dirs = []string{"."}
 	}
 
-	fmt.Printf("Building tokens from %s...\n", strings.Join(dirs, ", "))
+	diags, err := buildDirs(dirs)
+	if reportErr := writeReport("build", diags, err); reportErr != nil {
+		return reportErr
+	}
+	return err
+}
+
+// buildDirs loads, resolves and generates, returning the warnings it
+// collected along the way for --report
+func buildDirs(dirs []string) ([]tokens.Diagnostic, error) {
+	fmt.Fprintf(progress(), "Building tokens from %s...\n", strings.Join(dirs, ", "))
 
 	baseDict, themes, findings, err := loadTokens(dirs...)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	diags := findingDiagnostics(findings, strictUnknownKeys)
+	diags = append(diags, conflictDiagnostics(baseDict, themes)...)
 
 	// Name anything the generators will not read before generating, so a
 	// misnamed block is visible in the build log rather than as missing
 	// styling on a shipped page.
 	if err := auditLoaded(strictUnknownKeys, findings); err != nil {
-		return err
+		return diags, err
 	}
 
 	resolvedBase, err := resolveTokens(baseDict)
 	if err != nil {
-		return err
+		return diags, err
 	}
+	resolvedBase, err = normalizeDimensions(baseDict, resolvedBase)
+	if err != nil {
+		return diags, err
+	}
+	resolvedBase = mapGamut(baseDict, resolvedBase)
+	resolvedBase = formatColors(baseDict, resolvedBase)
 
 	formatType, category, err := parseFormat(format)
 	if err != nil {
-		return err
+		return diags, err
+	}
+
+	switch references {
+	case tokens.ReferencesInline:
+	case tokens.ReferencesPreserve:
+		if formatType != "tailwind" && formatType != "css" {
+			return diags, fmt.Errorf("--references=%s applies to tailwind and css output only", references)
+		}
+	default:
+		return diags, fmt.Errorf("unknown --references mode: %s (valid: %s, %s)", references, tokens.ReferencesInline, tokens.ReferencesPreserve)
+	}
+
+	switch colorFallback {
+	case "":
+	case generators.ColorFallbackSupports, generators.ColorFallbackGamut:
+		if formatType != "tailwind" && formatType != "css" {
+			return diags, fmt.Errorf("--color-fallback applies to tailwind and css output only")
+		}
+	default:
+		return diags, fmt.Errorf("unknown --color-fallback mode: %s (valid: %s, %s)", colorFallback, generators.ColorFallbackSupports, generators.ColorFallbackGamut)
+	}
+
+	switch colorScheme {
+	case generators.ColorSchemeAttribute:
+	case generators.ColorSchemeMedia, generators.ColorSchemeLightDark:
+		if formatType != "tailwind" && formatType != "css" {
+			return diags, fmt.Errorf("--color-scheme=%s applies to tailwind and css output only", colorScheme)
+		}
+	default:
+		return diags, fmt.Errorf("unknown --color-scheme mode: %s (valid: %s, %s, %s)", colorScheme, generators.ColorSchemeAttribute, generators.ColorSchemeMedia, generators.ColorSchemeLightDark)
+	}
+
+	if themeSelector != "" {
+		if formatType != "tailwind" && formatType != "css" {
+			return diags, fmt.Errorf("--theme-selector applies to tailwind and css output only")
+		}
+		if !strings.Contains(themeSelector, "{name}") {
+			return diags, fmt.Errorf("--theme-selector %q must contain {name}", themeSelector)
+		}
 	}
 
 	var content string
@@ -118,17 +244,22 @@ func runBuild(cmd *cobra.Command, args []string) error {
 	case "catalog", "manifest":
 		content, err = buildCatalogOutput(category, baseDict, resolvedBase, themes)
 	default:
-		return fmt.Errorf("unknown format: %s (valid: tailwind, css, catalog, manifest:CATEGORY)", format)
+		return diags, fmt.Errorf("unknown format: %s (valid: tailwind, css, catalog, manifest:CATEGORY)", format)
 	}
 	if err != nil {
-		return err
+		return diags, err
 	}
 
-	return writeOutput(formatType, category, content)
+	return diags, writeOutput(formatType, category, content)
 }
 
 // buildCSSOutput generates Tailwind or pure CSS from resolved tokens and themes.
 func buildCSSOutput(formatType string, baseDict *tokens.Dictionary, resolvedBase map[string]any, themes map[string]*tokens.Dictionary) (string, error) {
+	outputBase, err := applyReferenceMode(baseDict, resolvedBase)
+	if err != nil {
+		return "", err
+	}
+
 	inheritedThemes, err := tokens.ResolveThemeInheritance(baseDict, themes)
 	if err != nil {
 		return "", fmt.Errorf("failed to resolve theme inheritance: %w", err)
@@ -152,11 +283,55 @@ func buildCSSOutput(formatType string, baseDict *tokens.Dictionary, resolvedBase
 		if err != nil {
 			return "", fmt.Errorf("resolution failed for theme %s: %w", name, err)
 		}
+		resolvedTheme, err = normalizeDimensions(mergedDict, resolvedTheme)
+		if err != nil {
+			return "", fmt.Errorf("theme %s: %w", name, err)
+		}
+		resolvedTheme = mapGamut(mergedDict, resolvedTheme)
+		resolvedTheme = formatColors(mergedDict, resolvedTheme)
+		resolvedTheme, err = applyReferenceMode(mergedDict, resolvedTheme)
+		if err != nil {
+			return "", fmt.Errorf("theme %s: %w", name, err)
+		}
+		scheme, err := mergedDict.ColorScheme()
+		if err != nil {
+			return "", fmt.Errorf("theme %s: %w", name, err)
+		}
+		forced, err := mergedDict.ForcedColors()
+		if err != nil {
+			return "", fmt.Errorf("theme %s: %w", name, err)
+		}
+
+		themeComponents, err := mergedDict.ExtractComponents()
+		if err != nil {
+			return "", fmt.Errorf("theme %s: %w", name, err)
+		}
+		themeComponents = tokens.NewColorFormatter(mergedDict, colorFormat, colorPrecision).Components(themeComponents)
+
+		// $selector and $media belong to the theme's own file, so a
+		// theme that $extends another does not inherit them
+		var selectors []string
+		var media string
+		if own, ok := themes[name]; ok {
+			if selectors, err = own.ThemeSelectors(); err != nil {
+				return "", fmt.Errorf("theme %s: %w", name, err)
+			}
+			if media, err = own.ThemeMedia(); err != nil {
+				return "", fmt.Errorf("theme %s: %w", name, err)
+			}
+		}
 
+		// With preserved references an alias whose target changed
+		// compares equal to the base, so only the target is re-emitted
 		themeContexts[name] = generators.ThemeContext{
 			Dict:           mergedDict,
 			ResolvedTokens: resolvedTheme,
-			DiffTokens:     tokens.Diff(resolvedTheme, resolvedBase),
+			DiffTokens:     tokens.Diff(resolvedTheme, outputBase),
+			ColorScheme:    scheme,
+			ForcedColors:   forced,
+			Selectors:      selectors,
+			Media:          media,
+			Components:     themeComponents,
 		}
 	}
 
@@ -164,25 +339,34 @@ func buildCSSOutput(formatType string, baseDict *tokens.Dictionary, resolvedBase
 	if err != nil {
 		return "", fmt.Errorf("failed to extract components: %w", err)
 	}
+	formatter := tokens.NewColorFormatter(baseDict, colorFormat, colorPrecision)
+	components = formatter.Components(components)
+	forcedColors, err := baseDict.ForcedColors()
+	if err != nil {
+		return "", err
+	}
 
 	ctx := &generators.GenerationContext{
 		BaseDict:           baseDict,
-		ResolvedTokens:     resolvedBase,
+		ResolvedTokens:     outputBase,
 		Components:         components,
 		Themes:             themeContexts,
 		DefaultTheme:       tokens.DetectDefaultTheme(themes),
 		PropertyTokens:     tokens.ExtractPropertyTokens(baseDict, resolvedBase),
-		Keyframes:          tokens.ExtractKeyframes(baseDict),
+		Keyframes:          formatter.Keyframes(tokens.ExtractKeyframes(baseDict)),
 		Breakpoints:        tokens.ExtractBreakpoints(baseDict),
 		ResponsiveTokens:   tokens.ExtractResponsiveTokens(baseDict),
+		ReducedMotion:      tokens.ExtractReducedMotionTokens(baseDict),
 		ContainerOverrides: tokens.ExtractContainerOverrides(components),
+		ForcedColors:       forcedColors,
 	}
 
+	opts := generators.CSSOptions{ColorFallback: colorFallback, ColorScheme: colorScheme, ThemeSelector: themeSelector}
 	if formatType == "css" {
-		gen := generators.NewCSSGenerator()
+		gen := generators.NewCSSGeneratorWithOptions(opts)
 		return gen.Generate(ctx)
 	}
-	gen := generators.NewTailwindGenerator()
+	gen := generators.NewTailwindGeneratorWithOptions(opts)
 	return gen.Generate(ctx)
 }
 
@@ -202,6 +386,7 @@ func buildCatalogOutput(category string, baseDict *tokens.Dictionary, resolvedBa
 	if err != nil {
 		return "", fmt.Errorf("failed to extract components: %w", err)
 	}
+	components = tokens.NewColorFormatter(baseDict, colorFormat, colorPrecision).Components(components)
 
 	metadata := tokens.ExtractMetadata(baseDict)
 
@@ -229,6 +414,21 @@ func buildCatalogOutput(category string, baseDict *tokens.Dictionary, resolvedBa
 			if err != nil {
 				return "", fmt.Errorf("resolution failed for theme %s: %w", name, err)
 			}
+			resolvedTheme, err = normalizeDimensions(mergedDict, resolvedTheme)
+			if err != nil {
+				return "", fmt.Errorf("theme %s: %w", name, err)
+			}
+			resolvedTheme = mapGamut(mergedDict, resolvedTheme)
+			resolvedTheme = formatColors(mergedDict, resolvedTheme)
+			scheme, err := mergedDict.ColorScheme()
+			if err != nil {
+				return "", fmt.Errorf("theme %s: %w", name, err)
+			}
+			themeComponents, err := mergedDict.ExtractComponents()
+			if err != nil {
+				return "", fmt.Errorf("theme %s: %w", name, err)
+			}
+			themeComponents = tokens.NewColorFormatter(mergedDict, colorFormat, colorPrecision).Components(themeComponents)
 
 			var extends *string
 			var description string
@@ -244,8 +444,10 @@ func buildCatalogOutput(category string, baseDict *tokens.Dictionary, resolvedBa
 			catalogThemes[name] = generators.CatalogThemeInput{
 				Extends:        extends,
 				Description:    description,
+				ColorScheme:    scheme,
 				ResolvedTokens: resolvedTheme,
 				DiffTokens:     tokens.Diff(resolvedTheme, resolvedBase),
+				Components:     themeComponents,
 			}
 		}
 	}
@@ -253,6 +455,49 @@ func buildCatalogOutput(category string, baseDict *tokens.Dictionary, resolvedBa
 	return gen.GenerateWithMetadata(resolvedBase, components, catalogThemes, metadata)
 }
 
+// normalizeDimensions applies --dimension-unit to resolved tokens, using
+// the dictionary's $rootFontSize for rem conversion. It is a no-op when
+// the flag is unset.
+func normalizeDimensions(dict *tokens.Dictionary, resolved map[string]any) (map[string]any, error) {
+	if dimensionUnit == "" {
+		return resolved, nil
+	}
+	rootFontSize, err := dict.RootFontSize()
+	if err != nil {
+		return nil, err
+	}
+	return tokens.NormalizeDimensions(resolved, tokens.ExtractTypes(dict), dimensionUnit, rootFontSize)
+}
+
+// mapGamut applies --gamut-map to resolved tokens. It is a no-op when
+// the flag is unset.
+func mapGamut(dict *tokens.Dictionary, resolved map[string]any) map[string]any {
+	if gamutMap == "" {
+		return resolved
+	}
+	return tokens.MapGamut(resolved, tokens.ExtractTypes(dict), gamutMap)
+}
+
+// formatColors applies --color-format and any $colorFormat overrides to
+// resolved tokens. It is a no-op when neither is set.
+func formatColors(dict *tokens.Dictionary, resolved map[string]any) map[string]any {
+	formatter := tokens.NewColorFormatter(dict, colorFormat, colorPrecision)
+	if !formatter.Active() {
+		return resolved
+	}
+	return formatter.Tokens(resolved, tokens.ExtractTypes(dict))
+}
+
+// applyReferenceMode applies --references to resolved tokens. Under
+// preserve, aliases become var() chains; @property initial values still
+// come from the fully resolved map since they cannot reference variables.
+func applyReferenceMode(dict *tokens.Dictionary, resolved map[string]any) (map[string]any, error) {
+	if references != tokens.ReferencesPreserve {
+		return resolved, nil
+	}
+	return tokens.PreserveReferences(dict, resolved)
+}
+
 // writeOutput writes generated content to the appropriate output file.
 func writeOutput(formatType, category, content string) error {
 	if err := os.MkdirAll(outputDir, 0755); err != nil {
@@ -279,6 +524,6 @@ func writeOutput(formatType, category, content string) error {
 		return fmt.Errorf("failed to write output: %w", err)
 	}
 
-	fmt.Printf("Generated %s\n", outfile)
+	fmt.Fprintf(progress(), "Generated %s\n", outfile)
 	return nil
 }
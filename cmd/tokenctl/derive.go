@@ -3,6 +3,7 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -97,6 +98,7 @@ func runDerive(cmd *cobra.Command, _ []string) error {
 	}
 
 	theme := derive.Generate(params)
+	warnChromaClamp(cmd.ErrOrStderr(), params)
 
 	var content []byte
 	switch deriveFormat {
@@ -174,6 +176,16 @@ func resolveDeriveParams(cmd *cobra.Command) (derive.Params, error) {
 	return params, nil
 }
 
+// warnChromaClamp says when the primary chroma asked for exceeds the
+// engine's ceiling, which it otherwise clamps silently. Run tokenctl
+// validate on the output to see which colours fall outside sRGB.
+func warnChromaClamp(w io.Writer, params derive.Params) {
+	if params.PrimaryChroma() > derive.MaxChroma {
+		_, _ = fmt.Fprintf(w, "Warning: chroma %g at saturation %g exceeds %g; derived at %g\n",
+			params.Chroma, params.Saturation, derive.MaxChroma, derive.MaxChroma)
+	}
+}
+
 func printDeriveCatalog(w interface{ Write([]byte) (int, error) }) {
 	_, _ = fmt.Fprintln(w, "Presets:")
 	for _, p := range derive.Presets {
@@ -0,0 +1,220 @@
+// tokenctl/cmd/tokenctl/explain.go
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/dmoose/tokenctl/pkg/tokens"
+	"github.com/spf13/cobra"
+)
+
+var explainCmd = &cobra.Command{
+	Use:   "explain <token.path | .class> [directory...]",
+	Short: "Show how a token or component class gets its value",
+	Long: `Explain one token: its authored value, each hop of reference and
+expression resolution, the file (and directory, in a multi-directory
+merge) that defined it and the earlier definitions it replaced, its layer,
+constraints and metadata, and its resolved value in every theme.
+
+Given a component class instead of a token path, list every property of
+that class and the token references it consumes.
+
+Examples:
+  tokenctl explain color.primary ./tokens
+  tokenctl explain btn-primary ./base ./brand
+  tokenctl explain spacing.md --format=json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runExplain,
+}
+
+var explainFormat string
+
+func init() {
+	explainCmd.Flags().StringVarP(&explainFormat, "format", "f", "text", "Output format (text, json)")
+	rootCmd.AddCommand(explainCmd)
+}
+
+func runExplain(cmd *cobra.Command, args []string) error {
+	target := args[0]
+	dirs := args[1:]
+	if len(dirs) == 0 {
+		dirs = []string{"."}
+	}
+	if explainFormat != "text" && explainFormat != "json" {
+		return fmt.Errorf("unknown format: %s (valid: text, json)", explainFormat)
+	}
+
+	baseDict, themes, _, err := loadTokens(dirs...)
+	if err != nil {
+		return err
+	}
+
+	var result any
+	if !strings.HasPrefix(target, ".") {
+		ex, err := tokens.Explain(baseDict, themes, target)
+		if err != nil {
+			return err
+		}
+		if ex != nil {
+			result = ex
+		}
+	}
+	if result == nil {
+		ex, err := tokens.ExplainClass(baseDict, target)
+		if err != nil {
+			return err
+		}
+		if ex == nil {
+			return fmt.Errorf("no token or component class named %s", target)
+		}
+		result = ex
+	}
+
+	if explainFormat == "json" {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode explanation: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	switch ex := result.(type) {
+	case *tokens.Explanation:
+		printTokenExplanation(ex, dirs)
+	case *tokens.ClassExplanation:
+		printClassExplanation(ex)
+	}
+	return nil
+}
+
+func printTokenExplanation(ex *tokens.Explanation, dirs []string) {
+	fmt.Println(ex.Path)
+	if ex.Type != "" {
+		fmt.Printf("  Type:        %s\n", ex.Type)
+	}
+	fmt.Printf("  Authored:    %s\n", displayValue(ex.Authored))
+	if ex.Trace.Error != "" {
+		fmt.Printf("  Resolved:    error: %s\n", ex.Trace.Error)
+	} else {
+		fmt.Printf("  Resolved:    %s\n", displayValue(ex.Resolved))
+	}
+	if ex.SourceFile != "" {
+		fmt.Printf("  Defined in:  %s\n", describeSource(ex.SourceFile, dirs))
+	}
+	for _, earlier := range ex.Overrides {
+		fmt.Printf("  Overrides:   %s\n", describeSource(earlier, dirs))
+	}
+	if ex.Layer != "" {
+		fmt.Printf("  Layer:       %s\n", ex.Layer)
+	}
+	for _, key := range []string{"$min", "$max"} {
+		if v, ok := ex.Constraints[key]; ok {
+			fmt.Printf("  %-12s %v\n", key+":", v)
+		}
+	}
+	if meta := ex.Metadata; meta != nil {
+		if meta.Description != "" {
+			fmt.Printf("  Description: %s\n", meta.Description)
+		}
+		if len(meta.Usage) > 0 {
+			fmt.Printf("  Usage:       %s\n", strings.Join(meta.Usage, ", "))
+		}
+		if meta.Avoid != "" {
+			fmt.Printf("  Avoid:       %s\n", meta.Avoid)
+		}
+		if meta.Deprecated != nil && meta.Deprecated != false {
+			fmt.Printf("  Deprecated:  %v\n", meta.Deprecated)
+		}
+	}
+
+	fmt.Println("\nResolution:")
+	printTrace(ex.Trace, 1)
+
+	if len(ex.Themes) > 0 {
+		fmt.Println("\nThemes:")
+		width := 0
+		for _, tv := range ex.Themes {
+			width = max(width, len(tv.Theme))
+		}
+		for _, tv := range ex.Themes {
+			note := "same as base"
+			switch {
+			case tv.SourceFile != "":
+				note = "overridden in " + tv.SourceFile
+			case tv.Changed:
+				note = "changed through references"
+			}
+			fmt.Printf("  %-*s  %s  (%s)\n", width, tv.Theme, displayValue(tv.Value), note)
+		}
+	}
+}
+
+func printTrace(node *tokens.TraceNode, depth int) {
+	indent := strings.Repeat("  ", depth)
+	line := fmt.Sprintf("%s%s = %s", indent, node.Path, displayValue(node.Authored))
+	switch {
+	case node.Error != "":
+		line += "  ✗ " + node.Error
+	case node.Kind != tokens.StepLiteral:
+		line += fmt.Sprintf(" → %s  (%s)", displayValue(node.Resolved), node.Kind)
+	}
+	fmt.Println(line)
+	for _, ref := range node.Refs {
+		printTrace(ref, depth+1)
+	}
+}
+
+func printClassExplanation(ex *tokens.ClassExplanation) {
+	fmt.Printf(".%s (%s of %s)\n", ex.Class, ex.Role, ex.Component)
+	for _, prop := range ex.Properties {
+		name := prop.Property
+		if prop.Selector != "" {
+			name = prop.Selector + " " + name
+		}
+		fmt.Printf("  %s: %s\n", name, displayValue(prop.Value))
+		for _, ref := range prop.References {
+			if ref.Error != "" {
+				fmt.Printf("    {%s}  ✗ %s\n", ref.Path, ref.Error)
+				continue
+			}
+			fmt.Printf("    {%s} → %s\n", ref.Path, displayValue(ref.Value))
+		}
+	}
+}
+
+// describeSource names the merge directory a file came from, so a
+// multi-directory build shows which layer of the merge defined a token
+func describeSource(file string, dirs []string) string {
+	if len(dirs) < 2 {
+		return file
+	}
+	for _, dir := range dirs {
+		rel, err := filepath.Rel(dir, file)
+		if err == nil && !strings.HasPrefix(rel, "..") {
+			return fmt.Sprintf("%s (from %s)", file, dir)
+		}
+	}
+	return file
+}
+
+// displayValue renders strings as-is and structured values as JSON
+func displayValue(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return "-"
+	case string:
+		return val
+	case map[string]any, []any:
+		data, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprintf("%v", val)
+		}
+		return string(data)
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
@@ -0,0 +1,97 @@
+// tokenctl/cmd/tokenctl/graph.go
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dmoose/tokenctl/pkg/tokens"
+	"github.com/spf13/cobra"
+)
+
+var graphCmd = &cobra.Command{
+	Use:   "graph [directory...]",
+	Short: "Show the token dependency graph",
+	Long: `Print the dependency graph of a token system: token references,
+component properties that use tokens, and theme overrides.
+
+Edges point from a node to what it depends on. Components appear as
+component:<class> and themes as theme:<name>. References to tokens that do
+not exist are shown as missing nodes.
+
+Formats:
+  dot       Graphviz (default) — pipe to: dot -Tsvg -o graph.svg
+  mermaid   Mermaid flowchart for Markdown
+  json      Nodes, edges and cycles for tooling
+
+Use --focus to see the blast radius of one token: everything it depends on
+and everything that depends on it, limited to --depth hops.
+
+Examples:
+  tokenctl graph ./tokens | dot -Tsvg -o tokens.svg
+  tokenctl graph ./tokens --format=mermaid
+  tokenctl graph ./tokens --focus=color.primary --depth=2`,
+	Args: cobra.ArbitraryArgs,
+	RunE: runGraph,
+}
+
+var (
+	graphFormat string
+	graphFocus  string
+	graphDepth  int
+)
+
+func init() {
+	graphCmd.Flags().StringVarP(&graphFormat, "format", "f", "dot", "Output format (dot, mermaid, json)")
+	graphCmd.Flags().StringVar(&graphFocus, "focus", "", "Only show nodes connected to this token, component:<class> or theme:<name>")
+	graphCmd.Flags().IntVar(&graphDepth, "depth", 0, "Hops to follow from --focus in each direction (0 = unlimited)")
+	rootCmd.AddCommand(graphCmd)
+}
+
+func runGraph(cmd *cobra.Command, args []string) error {
+	dirs := args
+	if len(dirs) == 0 {
+		dirs = []string{"."}
+	}
+
+	baseDict, themes, _, err := loadTokens(dirs...)
+	if err != nil {
+		return err
+	}
+
+	graph, err := tokens.NewGraph(baseDict, themes)
+	if err != nil {
+		return err
+	}
+
+	if graphFocus != "" {
+		graph, err = graph.Focus(graphFocus, graphDepth)
+		if err != nil {
+			return err
+		}
+	}
+
+	var out string
+	switch graphFormat {
+	case "dot":
+		out = graph.DOT()
+	case "mermaid":
+		out = graph.Mermaid()
+	case "json":
+		data, err := json.MarshalIndent(graph, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode graph: %w", err)
+		}
+		out = string(data) + "\n"
+	default:
+		return fmt.Errorf("unknown format: %s (valid: dot, mermaid, json)", graphFormat)
+	}
+	fmt.Print(out)
+
+	for _, cycle := range graph.Cycles() {
+		fmt.Fprintf(os.Stderr, "Warning: dependency cycle: %s\n", strings.Join(append(cycle, cycle[0]), " -> "))
+	}
+	return nil
+}
@@ -2,6 +2,7 @@
 package main
 
 import (
+	"encoding/json"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -857,3 +858,814 @@ func TestIntegration_Derive_DarkModeUsesThemeSelector(t *testing.T) {
 		t.Errorf("dark mode should default to the theme attribute selector:\n%s", out)
 	}
 }
+
+func TestIntegration_Build_DimensionUnit(t *testing.T) {
+	t.Parallel()
+	tokensDir := t.TempDir()
+	outputDir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(tokensDir, "tokens.json"), []byte(`{
+      "$rootFontSize": "10px",
+      "spacing": {
+        "$type": "dimension",
+        "sm": { "$value": "8px" },
+        "md": { "$value": "calc({spacing.sm} + 1rem)" },
+        "half": { "$value": "50%" }
+      }
+    }`), 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+
+	cmd := exec.Command(getTokenctlPath(), "build", tokensDir,
+		"--format=css", "--dimension-unit=rem", "--output", outputDir)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("build failed: %v\n%s", err, out)
+	}
+	css, err := os.ReadFile(filepath.Join(outputDir, "tokens.css"))
+	if err != nil {
+		t.Fatalf("read built css: %v", err)
+	}
+
+	got := declarations(string(css))
+	want := map[string]string{
+		"--spacing-sm":   "0.8rem",
+		"--spacing-md":   "1.8rem",
+		"--spacing-half": "50%",
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("%s = %q, want %q", name, got[name], value)
+		}
+	}
+
+	cmd = exec.Command(getTokenctlPath(), "build", tokensDir,
+		"--format=css", "--dimension-unit=em", "--output", t.TempDir())
+	if out, err := cmd.CombinedOutput(); err == nil {
+		t.Errorf("expected --dimension-unit=em to be rejected:\n%s", out)
+	}
+}
+
+func TestIntegration_Build_PreserveReferences(t *testing.T) {
+	t.Parallel()
+	tokensDir := t.TempDir()
+	outputDir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(tokensDir, "tokens.json"), []byte(`{
+      "color": {
+        "primary": { "$value": "#3b82f6" },
+        "surface": { "$value": "#ffffff" }
+      },
+      "button": {
+        "bg": { "$value": "{color.primary}" }
+      }
+    }`), 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(tokensDir, "themes"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tokensDir, "themes", "dark.json"), []byte(`{
+      "color": {
+        "primary": { "$value": "#60a5fa" }
+      }
+    }`), 0o644); err != nil {
+		t.Fatalf("write theme: %v", err)
+	}
+
+	cmd := exec.Command(getTokenctlPath(), "build", tokensDir,
+		"--format=css", "--references=preserve", "--output", outputDir)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("build failed: %v\n%s", err, out)
+	}
+	data, err := os.ReadFile(filepath.Join(outputDir, "tokens.css"))
+	if err != nil {
+		t.Fatalf("read built css: %v", err)
+	}
+	css := string(data)
+
+	if !strings.Contains(css, "--button-bg: var(--color-primary);") {
+		t.Errorf("expected alias as var() chain:\n%s", css)
+	}
+	if !strings.Contains(css, "--color-primary: #60a5fa;") {
+		t.Errorf("expected dark theme to override the target:\n%s", css)
+	}
+	if strings.Count(css, "--button-bg:") != 1 {
+		t.Errorf("alias should not be re-emitted in the theme block:\n%s", css)
+	}
+
+	cmd = exec.Command(getTokenctlPath(), "build", tokensDir,
+		"--format=catalog", "--references=preserve", "--output", t.TempDir())
+	if out, err := cmd.CombinedOutput(); err == nil {
+		t.Errorf("expected --references=preserve to be rejected for catalog:\n%s", out)
+	}
+}
+
+func TestIntegration_Graph(t *testing.T) {
+	t.Parallel()
+	tokensDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tokensDir, "tokens.json"), []byte(`{
+      "color": {
+        "blue": { "$value": "#3b82f6" },
+        "primary": { "$value": "{color.blue}" },
+        "unrelated": { "$value": "#000000" }
+      },
+      "button": {
+        "bg": { "$value": "{color.primary}" }
+      }
+    }`), 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+
+	cmd := exec.Command(getTokenctlPath(), "graph", tokensDir, "--focus=color.blue", "--depth=1")
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("graph failed: %v", err)
+	}
+	dot := string(output)
+	if !strings.Contains(dot, `"color.primary" -> "color.blue";`) {
+		t.Errorf("expected direct dependent in focus:\n%s", dot)
+	}
+	if strings.Contains(dot, "button.bg") || strings.Contains(dot, "color.unrelated") {
+		t.Errorf("focus should stop at depth 1:\n%s", dot)
+	}
+
+	cmd = exec.Command(getTokenctlPath(), "graph", tokensDir, "--format=json")
+	output, err = cmd.Output()
+	if err != nil {
+		t.Fatalf("graph --format=json failed: %v", err)
+	}
+	if !strings.Contains(string(output), `"kind": "reference"`) {
+		t.Errorf("expected JSON edges:\n%s", output)
+	}
+
+	cmd = exec.Command(getTokenctlPath(), "graph", tokensDir, "--focus=color.nope")
+	if out, err := cmd.CombinedOutput(); err == nil {
+		t.Errorf("expected unknown focus to fail:\n%s", out)
+	}
+}
+
+func TestIntegration_Explain(t *testing.T) {
+	t.Parallel()
+	baseDir := t.TempDir()
+	siteDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(baseDir, "colors.json"), []byte(`{
+      "color": {
+        "blue": { "$value": "#3b82f6" },
+        "primary": { "$value": "{color.blue}" }
+      },
+      "components": {
+        "button": { "$type": "component", "$class": "btn", "base": { "background": "{color.primary}" } }
+      }
+    }`), 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(siteDir, "colors.json"), []byte(`{
+      "color": { "primary": { "$value": "{color.blue}", "$description": "Site action color" } }
+    }`), 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+
+	cmd := exec.Command(getTokenctlPath(), "explain", "color.primary", baseDir, siteDir)
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("explain failed: %v", err)
+	}
+	out := string(output)
+	for _, want := range []string{
+		"Resolved:    #3b82f6",
+		"Defined in:  " + filepath.Join(siteDir, "colors.json") + " (from " + siteDir + ")",
+		"Overrides:   " + filepath.Join(baseDir, "colors.json") + " (from " + baseDir + ")",
+		"Site action color",
+		"color.primary = {color.blue} → #3b82f6  (alias)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("explain output missing %q:\n%s", want, out)
+		}
+	}
+
+	cmd = exec.Command(getTokenctlPath(), "explain", ".btn", baseDir)
+	output, err = cmd.Output()
+	if err != nil {
+		t.Fatalf("explain .btn failed: %v", err)
+	}
+	if !strings.Contains(string(output), "{color.primary} → #3b82f6") {
+		t.Errorf("expected class references:\n%s", output)
+	}
+
+	cmd = exec.Command(getTokenctlPath(), "explain", "color.nope", baseDir)
+	if out, err := cmd.CombinedOutput(); err == nil {
+		t.Errorf("expected unknown token to fail:\n%s", out)
+	}
+}
+
+func TestIntegration_Report(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	tokenFile := filepath.Join(tmpDir, "tokens.json")
+	if err := os.WriteFile(tokenFile, []byte(`{
+  "color": {
+    "primary": { "$value": "#3b82f6" },
+    "accent": { "$value": "{color.missing}" }
+  }
+}`), 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+
+	// Report on stdout: the document must parse on its own
+	cmd := exec.Command(getTokenctlPath(), "validate", tmpDir, "--report=json")
+	output, err := cmd.Output()
+	if err == nil {
+		t.Fatal("expected validation to fail")
+	}
+	var doc struct {
+		Errors      int `json:"errors"`
+		Diagnostics []struct {
+			Rule   string `json:"rule"`
+			Path   string `json:"path"`
+			File   string `json:"file"`
+			Line   int    `json:"line"`
+			Column int    `json:"column"`
+		} `json:"diagnostics"`
+	}
+	if err := json.Unmarshal(output, &doc); err != nil {
+		t.Fatalf("stdout is not a JSON report: %v\n%s", err, output)
+	}
+	if doc.Errors != 1 || len(doc.Diagnostics) != 1 {
+		t.Fatalf("unexpected report: %s", output)
+	}
+	if d := doc.Diagnostics[0]; d.Rule != "reference-not-found" || d.Path != "color.accent" ||
+		d.File != tokenFile || d.Line != 4 || d.Column != 17 {
+		t.Errorf("unexpected diagnostic: %+v", d)
+	}
+
+	// A build that stops on a resolution failure still writes its report
+	reportPath := filepath.Join(tmpDir, "build.sarif")
+	cmd = exec.Command(getTokenctlPath(), "build", tmpDir, "--output", filepath.Join(tmpDir, "dist"),
+		"--report=sarif", "--report-file", reportPath)
+	if out, err := cmd.CombinedOutput(); err == nil {
+		t.Fatalf("expected build to fail:\n%s", out)
+	}
+	sarif, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("read report: %v", err)
+	}
+	for _, want := range []string{`"version": "2.1.0"`, `"ruleId": "reference-not-found"`, `"startLine": 4`} {
+		if !strings.Contains(string(sarif), want) {
+			t.Errorf("SARIF missing %q:\n%s", want, sarif)
+		}
+	}
+
+	cmd = exec.Command(getTokenctlPath(), "validate", tmpDir, "--report=yaml")
+	if out, err := cmd.CombinedOutput(); err == nil || !strings.Contains(string(out), "unknown report format") {
+		t.Errorf("expected unknown format error, got: %s", out)
+	}
+}
+
+func TestIntegration_Validate_Contrast(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "themes"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "colors.json"), []byte(`{
+  "color": {
+    "$type": "color",
+    "primary": { "$value": "#1d4ed8" },
+    "primary-content": { "$value": "#ffffff" },
+    "info": { "$value": "#0ea5e9" }
+  }
+}`), 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "themes", "dark.json"), []byte(`{
+  "dark": { "color": { "primary": { "$value": "#93c5fd" } } }
+}`), 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+
+	// Without --contrast only declared pairs are checked
+	cmd := exec.Command(getTokenctlPath(), "validate", tmpDir)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("validate without --contrast failed: %v\n%s", err, out)
+	}
+
+	cmd = exec.Command(getTokenctlPath(), "validate", tmpDir, "--contrast=AA", "--require-content")
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected contrast failure in the dark theme:\n%s", output)
+	}
+	out := string(output)
+	for _, want := range []string{
+		"[Error] color.primary-content",
+		"against color.primary is below 4.5:1 (AA) in theme dark",
+		"[Warning] color.info",
+		"no content color color.info-content",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("validate output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "4.5:1 (AA)\n") {
+		t.Errorf("base pair passes and should not be reported:\n%s", out)
+	}
+}
+
+func TestIntegration_Gamut(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "themes"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "colors.json"), []byte(`{
+  "color": {
+    "$type": "color",
+    "primary": { "$value": "oklch(49.12% 0.309 275.75)" },
+    "accent": { "$value": "#f59e0b" }
+  }
+}`), 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "themes", "dark.json"), []byte(`{
+  "dark": { "color": { "accent": { "$value": "oklch(70% 0.35 150)" } } }
+}`), 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+
+	// Out-of-gamut colors warn; they do not fail validation
+	cmd := exec.Command(getTokenctlPath(), "validate", tmpDir)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("validate failed: %v\n%s", err, output)
+	}
+	out := string(output)
+	for _, want := range []string{
+		"[Warning] color.primary",
+		"is outside sRGB (within Display-P3)\n",
+		"[Warning] color.accent",
+		"oklch(70% 0.35 150) is outside sRGB and Display-P3 in theme dark",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("validate output missing %q:\n%s", want, out)
+		}
+	}
+
+	cmd = exec.Command(getTokenctlPath(), "validate", tmpDir, "--gamut=display-p3")
+	output, err = cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("validate --gamut=display-p3 failed: %v\n%s", err, output)
+	}
+	if strings.Contains(string(output), "color.primary") {
+		t.Errorf("P3 color reported against display-p3:\n%s", output)
+	}
+
+	outputDir := t.TempDir()
+	cmd = exec.Command(getTokenctlPath(), "build", tmpDir, "--format=css", "--gamut-map=srgb", "--output", outputDir)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("build failed: %v\n%s", err, out)
+	}
+	css, err := os.ReadFile(filepath.Join(outputDir, "tokens.css"))
+	if err != nil {
+		t.Fatalf("read built css: %v", err)
+	}
+	if strings.Contains(string(css), "0.309 275.75") || strings.Contains(string(css), "0.35 150") {
+		t.Errorf("out-of-gamut colors survived --gamut-map:\n%s", css)
+	}
+
+	cmd = exec.Command(getTokenctlPath(), "build", tmpDir, "--gamut-map=rec2020", "--output", t.TempDir())
+	if out, err := cmd.CombinedOutput(); err == nil {
+		t.Errorf("expected --gamut-map=rec2020 to be rejected:\n%s", out)
+	}
+}
+
+func TestIntegration_ColorFormat(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "themes"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "tokens.json"), []byte(`{
+  "color": {
+    "$type": "color",
+    "primary": { "$value": "#3b82f6" },
+    "accent": { "$value": "rgb(245 158 11)" },
+    "brand": {
+      "$colorFormat": "hex",
+      "main": { "$value": "oklch(62.8% 0.258 29.23)" }
+    }
+  },
+  "keyframes": {
+    "flash": { "from": { "color": "#ff0000" }, "to": { "color": "#ffffff" } }
+  },
+  "components": {
+    "badge": { "$type": "component", "$class": "badge", "base": { "border-color": "#000000" } }
+  }
+}`), 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "themes", "dark.json"), []byte(`{
+  "color": { "primary": { "$value": "#1e3a8a" } }
+}`), 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	cmd := exec.Command(getTokenctlPath(), "build", tmpDir, "--format=css", "--color-format=oklch", "--color-precision=1", "--output", outputDir)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("build failed: %v\n%s", err, out)
+	}
+	data, err := os.ReadFile(filepath.Join(outputDir, "tokens.css"))
+	if err != nil {
+		t.Fatalf("read built css: %v", err)
+	}
+	css := string(data)
+	for _, want := range []string{
+		"--color-primary: oklch(62.3% 0.2 259.8);",
+		"--color-brand-main: #ff0000;",
+		"--color-primary: oklch(37.9% 0.1 265.5);",
+		"color: oklch(62.8% 0.3 29.2);",
+		"border-color: oklch(0.0% 0.0 none);",
+	} {
+		if !strings.Contains(css, want) {
+			t.Errorf("css missing %q:\n%s", want, css)
+		}
+	}
+	if strings.Contains(css, "#3b82f6") || strings.Contains(css, "rgb(245") {
+		t.Errorf("colors kept their source format:\n%s", css)
+	}
+
+	cmd = exec.Command(getTokenctlPath(), "build", tmpDir, "--color-format=cmyk", "--output", t.TempDir())
+	if out, err := cmd.CombinedOutput(); err == nil {
+		t.Errorf("expected --color-format=cmyk to be rejected:\n%s", out)
+	}
+}
+
+func TestIntegration_ColorFallback(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "tokens.json"), []byte(`{
+  "color": {
+    "$type": "color",
+    "primary": { "$value": "oklch(49.12% 0.309 275.75)" },
+    "soft": { "$value": "oklch(70% 0.1 200)" }
+  }
+}`), 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	cmd := exec.Command(getTokenctlPath(), "build", tmpDir, "--format=css", "--color-fallback=gamut", "--output", outputDir)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("build failed: %v\n%s", err, out)
+	}
+	data, err := os.ReadFile(filepath.Join(outputDir, "tokens.css"))
+	if err != nil {
+		t.Fatalf("read built css: %v", err)
+	}
+	css := string(data)
+	for _, want := range []string{
+		"--color-primary: #4a00ff;",
+		"--color-soft: oklch(70% 0.1 200);",
+		"@media (color-gamut: p3) {\n    :root {\n      --color-primary: oklch(49.12% 0.309 275.75);",
+	} {
+		if !strings.Contains(css, want) {
+			t.Errorf("css missing %q:\n%s", want, css)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"--color-fallback=always"},
+		{"--color-fallback=supports", "--format=catalog"},
+	} {
+		cmd = exec.Command(getTokenctlPath(), append([]string{"build", tmpDir, "--output", t.TempDir()}, args...)...)
+		if out, err := cmd.CombinedOutput(); err == nil {
+			t.Errorf("expected %v to be rejected:\n%s", args, out)
+		}
+	}
+}
+
+func TestIntegration_ColorScheme(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "themes"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"tokens.json":       `{"color": {"$type": "color", "base-100": {"$value": "#ffffff"}}}`,
+		"themes/light.json": `{"$default": true, "$colorScheme": "light"}`,
+		"themes/dark.json":  `{"$colorScheme": "dark", "color": {"base-100": {"$value": "#1d232a"}}}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write fixture: %v", err)
+		}
+	}
+
+	tests := []struct {
+		mode string
+		want string
+	}{
+		{"media", "@media (prefers-color-scheme: dark) {\n    :root:not([data-theme]) {\n      color-scheme: dark;\n      --color-base-100: #1d232a;"},
+		{"light-dark", "--color-base-100: light-dark(#ffffff, #1d232a);"},
+	}
+	for _, tt := range tests {
+		outputDir := t.TempDir()
+		cmd := exec.Command(getTokenctlPath(), "build", tmpDir, "--color-scheme="+tt.mode, "--output", outputDir)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("build --color-scheme=%s failed: %v\n%s", tt.mode, err, out)
+		}
+		css, err := os.ReadFile(filepath.Join(outputDir, "tokens.css"))
+		if err != nil {
+			t.Fatalf("read built css: %v", err)
+		}
+		if !strings.Contains(string(css), tt.want) {
+			t.Errorf("--color-scheme=%s output missing %q:\n%s", tt.mode, tt.want, css)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "themes", "dark.json"), []byte(`{"$colorScheme": "dim"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(getTokenctlPath(), "build", tmpDir, "--output", t.TempDir())
+	if out, err := cmd.CombinedOutput(); err == nil || !strings.Contains(string(out), "$colorScheme") {
+		t.Errorf("expected an invalid $colorScheme to fail the build:\n%s", out)
+	}
+}
+
+func TestIntegration_ForcedColors(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "themes"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"tokens.json": `{
+  "$forcedColors": { "color.primary": "Highlight" },
+  "color": { "$type": "color", "primary": { "$value": "#3b82f6" }, "base-100": { "$value": "#ffffff" } },
+  "button": {
+    "$type": "component",
+    "$class": "btn",
+    "base": { "cursor": "pointer", "background-color": "{color.primary}" },
+    "$forcedColors": { "border": "1px solid ButtonText" }
+  },
+  "chip": {
+    "$type": "component",
+    "$class": "chip",
+    "base": { "background-color": "{color.base-100}", "&:active": { "color": "{color.primary}" } }
+  }
+}`,
+		"themes/dark.json": `{"$forcedColors": {"color.base-100": "Canvas"}, "color": {"base-100": {"$value": "#1d232a"}}}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write fixture: %v", err)
+		}
+	}
+
+	for _, format := range []string{"tailwind", "css"} {
+		outputDir := t.TempDir()
+		cmd := exec.Command(getTokenctlPath(), "build", tmpDir, "--format="+format, "--output", outputDir)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("build --format=%s failed: %v\n%s", format, err, out)
+		}
+		css, err := os.ReadFile(filepath.Join(outputDir, "tokens.css"))
+		if err != nil {
+			t.Fatalf("read built css: %v", err)
+		}
+		for _, want := range []string{
+			"  @media (forced-colors: active) {\n    :root, [data-theme] {\n      --color-primary: Highlight;\n    }\n" +
+				"    [data-theme=\"dark\"] {\n      --color-base-100: Canvas;\n    }\n",
+			"  @media (forced-colors: active) {\n    .btn {\n      border: 1px solid ButtonText;\n    }\n",
+		} {
+			if !strings.Contains(string(css), want) {
+				t.Errorf("--format=%s output missing %q:\n%s", format, want, css)
+			}
+		}
+	}
+
+	// Only the chip lacks an edge. Validation also reports the component
+	// properties as malformed tokens, so only the warnings are checked.
+	cmd := exec.Command(getTokenctlPath(), "validate", tmpDir)
+	output, _ := cmd.CombinedOutput()
+	if out := string(output); !strings.Contains(out, "[Warning] chip") || strings.Contains(out, "[Warning] button") {
+		t.Errorf("unexpected forced colors warnings:\n%s", out)
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "themes", "dark.json"), []byte(`{"$forcedColors": {"color.base-100": "black"}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cmd = exec.Command(getTokenctlPath(), "build", tmpDir, "--output", t.TempDir())
+	if out, err := cmd.CombinedOutput(); err == nil || !strings.Contains(string(out), "not a system color") {
+		t.Errorf("expected a non-system color in $forcedColors to fail the build:\n%s", out)
+	}
+}
+
+func TestIntegration_ReducedMotion(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "tokens.json"), []byte(`{
+  "duration": { "$type": "duration", "fast": { "$value": "150ms", "$reducedMotion": "0ms" } },
+  "keyframes": {
+    "spin": { "to": { "transform": "rotate(360deg)" }, "$reducedMotion": "none" }
+  },
+  "fade": { "$type": "component", "$class": "fade", "base": { "transition": "opacity {duration.fast}" } },
+  "spinner": { "$type": "component", "$class": "spinner", "base": { "animation": "spin 1s", "transition": "transform 2s" } }
+}`), 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+
+	for _, format := range []string{"tailwind", "css"} {
+		outputDir := t.TempDir()
+		cmd := exec.Command(getTokenctlPath(), "build", tmpDir, "--format="+format, "--output", outputDir)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("build --format=%s failed: %v\n%s", format, err, out)
+		}
+		css, err := os.ReadFile(filepath.Join(outputDir, "tokens.css"))
+		if err != nil {
+			t.Fatalf("read built css: %v", err)
+		}
+		for _, want := range []string{
+			"@media (prefers-reduced-motion: reduce) {\n  @keyframes spin {\n  }\n}\n",
+			"@media (prefers-reduced-motion: reduce) {\n  :root {\n    --duration-fast: 0ms;\n  }\n}\n",
+		} {
+			if !strings.Contains(string(css), want) {
+				t.Errorf("--format=%s output missing %q:\n%s", format, want, css)
+			}
+		}
+	}
+
+	// Validation also reports the component properties as malformed
+	// tokens, so only the warnings are checked
+	cmd := exec.Command(getTokenctlPath(), "validate", tmpDir)
+	output, _ := cmd.CombinedOutput()
+	out := string(output)
+	if !strings.Contains(out, "[Warning] spinner") || !strings.Contains(out, ".spinner sets transition without") || strings.Contains(out, "[Warning] fade") {
+		t.Errorf("unexpected reduced motion warnings:\n%s", out)
+	}
+}
+
+func TestIntegration_ThemeAxes(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"tokens.json": `{
+  "color": { "$type": "color", "primary": { "$value": "#3b82f6" }, "link": { "$value": "{color.primary}" } },
+  "spacing": { "$type": "dimension", "md": { "$value": "1rem" } }
+}`,
+		"themes/light.json":           `{"$default": true}`,
+		"themes/dark.json":            `{"color": {"link": {"$value": "#93c5fd"}}}`,
+		"themes/brand/acme.json":      `{"color": {"primary": {"$value": "#e11d48"}}}`,
+		"themes/density/compact.json": `{"spacing": {"md": {"$value": "0.5rem"}}}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write fixture: %v", err)
+		}
+	}
+
+	for _, format := range []string{"tailwind", "css"} {
+		outputDir := t.TempDir()
+		cmd := exec.Command(getTokenctlPath(), "build", tmpDir, "--format="+format, "--output", outputDir)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("build --format=%s failed: %v\n%s", format, err, out)
+		}
+		css, err := os.ReadFile(filepath.Join(outputDir, "tokens.css"))
+		if err != nil {
+			t.Fatalf("read built css: %v", err)
+		}
+		for _, want := range []string{
+			"  [data-brand=\"acme\"] {\n    --color-link: #e11d48;\n    --color-primary: #e11d48;\n  }\n",
+			"  [data-density=\"compact\"] {\n    --spacing-md: 0.5rem;\n  }\n",
+			"  [data-brand=\"acme\"][data-theme=\"dark\"] {\n    --color-link: #93c5fd;\n  }\n",
+		} {
+			if !strings.Contains(string(css), want) {
+				t.Errorf("--format=%s output missing %q:\n%s", format, want, css)
+			}
+		}
+		// The density axis never interacts with the others
+		if strings.Contains(string(css), `[data-density="compact"][`) {
+			t.Errorf("--format=%s emitted a combination with nothing to add:\n%s", format, css)
+		}
+	}
+
+	cmd := exec.Command(getTokenctlPath(), "validate", tmpDir)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("validate failed: %v\n%s", err, output)
+	}
+	if out := string(output); !strings.Contains(out, "Checking Theme 'dark+brand/acme+density/compact'...") {
+		t.Errorf("combinations not validated:\n%s", out)
+	}
+}
+
+func TestIntegration_ThemeSelectors(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"tokens.json":       `{"color": {"$type": "color", "primary": {"$value": "#3b82f6"}}}`,
+		"themes/light.json": `{"$default": true}`,
+		"themes/dark.json":  `{"color": {"primary": {"$value": "#60a5fa"}}}`,
+		// $selector and $media are the file's own; dim does not inherit them
+		"themes/hc.json":  `{"$selector": ":host([theme=hc])", "$media": "(prefers-contrast: more)", "color": {"primary": {"$value": "#0000ff"}}}`,
+		"themes/dim.json": `{"$extends": "hc", "color": {"primary": {"$value": "#334155"}}}`,
+	}
+	if err := os.MkdirAll(filepath.Join(tmpDir, "themes"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write fixture: %v", err)
+		}
+	}
+
+	for _, format := range []string{"tailwind", "css"} {
+		outputDir := t.TempDir()
+		cmd := exec.Command(getTokenctlPath(), "build", tmpDir, "--format="+format, "--theme-selector=.theme-{name}", "--output", outputDir)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("build --format=%s failed: %v\n%s", format, err, out)
+		}
+		css, err := os.ReadFile(filepath.Join(outputDir, "tokens.css"))
+		if err != nil {
+			t.Fatalf("read built css: %v", err)
+		}
+		for _, want := range []string{
+			"  .theme-dark {\n    --color-primary: #60a5fa;\n  }\n",
+			"  .theme-dim {\n    --color-primary: #334155;\n  }\n",
+			"  @media (prefers-contrast: more) {\n    :host([theme=hc]) {\n      --color-primary: #0000ff;\n    }\n  }\n",
+		} {
+			if !strings.Contains(string(css), want) {
+				t.Errorf("--format=%s output missing %q:\n%s", format, want, css)
+			}
+		}
+	}
+
+	cmd := exec.Command(getTokenctlPath(), "build", tmpDir, "--theme-selector=.theme", "--output", t.TempDir())
+	if out, err := cmd.CombinedOutput(); err == nil || !strings.Contains(string(out), "must contain {name}") {
+		t.Errorf("expected a template without {name} to fail, got %v:\n%s", err, out)
+	}
+}
+
+func TestIntegration_ThemeComponents(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"tokens.json": `{"color": {"$type": "color", "border": {"$value": "#e5e7eb"}, "border-dark": {"$value": "#374151"}},
+			"components": {"card": {"$type": "component", "$class": "card", "base": {"padding": "1rem", "border": "1px solid {color.border}"}}}}`,
+		"themes/light.json": `{"$default": true}`,
+		"themes/dark.json":  `{"components": {"card": {"base": {"border": "1px solid {color.border-dark}"}}}}`,
+	}
+	if err := os.MkdirAll(filepath.Join(tmpDir, "themes"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write fixture: %v", err)
+		}
+	}
+
+	want := "  [data-theme=\"dark\"] .card {\n    border: 1px solid var(--color-border-dark);\n  }\n"
+	for _, format := range []string{"tailwind", "css"} {
+		outputDir := t.TempDir()
+		cmd := exec.Command(getTokenctlPath(), "build", tmpDir, "--format="+format, "--output", outputDir)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("build --format=%s failed: %v\n%s", format, err, out)
+		}
+		css, err := os.ReadFile(filepath.Join(outputDir, "tokens.css"))
+		if err != nil {
+			t.Fatalf("read built css: %v", err)
+		}
+		if !strings.Contains(string(css), want) {
+			t.Errorf("--format=%s output missing %q:\n%s", format, want, css)
+		}
+		if strings.Count(string(css), "padding: 1rem") != 1 {
+			t.Errorf("--format=%s repeated unchanged declarations under the theme:\n%s", format, css)
+		}
+	}
+
+	outputDir := t.TempDir()
+	cmd := exec.Command(getTokenctlPath(), "build", tmpDir, "--format=catalog", "--output", outputDir)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("build catalog failed: %v\n%s", err, out)
+	}
+	data, err := os.ReadFile(filepath.Join(outputDir, "catalog.json"))
+	if err != nil {
+		t.Fatalf("reading catalog: %v", err)
+	}
+	var catalog struct {
+		Themes map[string]struct {
+			Components map[string]map[string]any `json:"components"`
+		} `json:"themes"`
+	}
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		t.Fatalf("parse catalog: %v", err)
+	}
+	if got := catalog.Themes["dark"].Components[".card"]["border"]; got != "1px solid {color.border-dark}" {
+		t.Errorf("dark catalog components = %v", catalog.Themes["dark"].Components)
+	}
+	if got := catalog.Themes["light"].Components; got != nil {
+		t.Errorf("expected no components for light, got %v", got)
+	}
+}
@@ -0,0 +1,111 @@
+// tokenctl/cmd/tokenctl/report.go
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/dmoose/tokenctl/pkg/report"
+	"github.com/dmoose/tokenctl/pkg/tokens"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reportFormat string
+	reportFile   string
+)
+
+// addReportFlags registers --report and --report-file on a command that
+// collects diagnostics
+func addReportFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&reportFormat, "report", "", "Write diagnostics as json, sarif or junit")
+	cmd.Flags().StringVar(&reportFile, "report-file", "", "File for --report output (default: stdout)")
+}
+
+// checkReportFlags validates --report before any work is done
+func checkReportFlags() error {
+	if reportFormat == "" {
+		if reportFile != "" {
+			return fmt.Errorf("--report-file requires --report")
+		}
+		return nil
+	}
+	if !slices.Contains(report.Formats, reportFormat) {
+		return fmt.Errorf("unknown report format: %s (valid: %s)", reportFormat, strings.Join(report.Formats, ", "))
+	}
+	return nil
+}
+
+// progress is where human-oriented output goes. A report written to
+// stdout keeps stdout to itself so it can be piped straight to a file.
+func progress() io.Writer {
+	if reportFormat != "" && reportFile == "" {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// writeReport writes the collected diagnostics when --report is set. A
+// run that stopped on runErr still reports, with runErr as its last
+// diagnostic, so CI sees why nothing was produced.
+func writeReport(command string, diags []tokens.Diagnostic, runErr error) error {
+	if reportFormat == "" {
+		return nil
+	}
+	if errs, _ := report.Counts(diags); runErr != nil && errs == 0 {
+		diags = append(diags, tokens.ErrorDiagnostic(runErr))
+	}
+
+	w := io.Writer(os.Stdout)
+	if reportFile != "" {
+		f, err := os.Create(reportFile)
+		if err != nil {
+			return fmt.Errorf("failed to create report: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		w = f
+	}
+	if err := report.Write(w, reportFormat, command, diags); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// findingDiagnostics converts unknown-key findings, which fail the run
+// under --strict-unknown-keys and warn otherwise
+func findingDiagnostics(findings []tokens.Finding, strict bool) []tokens.Diagnostic {
+	severity := tokens.SeverityWarning
+	if strict {
+		severity = tokens.SeverityError
+	}
+	diags := make([]tokens.Diagnostic, 0, len(findings))
+	for _, f := range findings {
+		diags = append(diags, f.Diagnostic(severity))
+	}
+	return diags
+}
+
+// conflictDiagnostics reports merge conflicts in the base and in each
+// raw theme, before inheritance folds the base into the themes
+func conflictDiagnostics(base *tokens.Dictionary, themes map[string]*tokens.Dictionary) []tokens.Diagnostic {
+	diags := tokens.MergeConflictDiagnostics(base)
+	for _, name := range sortedKeys(themes) {
+		for _, d := range tokens.MergeConflictDiagnostics(themes[name]) {
+			d.Theme = name
+			diags = append(diags, d)
+		}
+	}
+	return diags
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
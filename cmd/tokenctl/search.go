@@ -74,13 +74,19 @@ func runSearch(cmd *cobra.Command, args []string) error {
 	var results []searchResult
 
 	for path, value := range resolved {
-		// Skip non-atomic values
-		if _, ok := value.(map[string]any); ok {
+		meta := metadata[path]
+
+		// Composite values display as their CSS; other maps are not atomic
+		var tokenType string
+		if meta != nil {
+			tokenType = meta.Type
+		}
+		if css, ok := tokens.FormatCompositeCSS(tokenType, value); ok {
+			value = css
+		} else if _, ok := value.(map[string]any); ok {
 			continue
 		}
 
-		meta := metadata[path]
-
 		// Apply filters
 		if !matchesSearch(path, meta, query, searchType, searchCategory) {
 			continue
@@ -5,6 +5,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/dmoose/tokenctl/pkg/colors"
 	"github.com/dmoose/tokenctl/pkg/tokens"
 	"github.com/spf13/cobra"
 )
@@ -23,6 +24,32 @@ Layer validation (--strict-layers) enforces design system architecture:
   - semantic layer: Can reference brand tokens
   - component layer: Can only reference semantic tokens
 
+Contrast: tokens declaring $contrastWith (and optionally $minContrast) are
+always checked against their background. --contrast=AA (or AAA, AA-large,
+AAA-large, a ratio such as 4.5, or an APCA lightness contrast such as Lc60)
+also checks every X / X-content pair, the DaisyUI convention, in the base
+and every theme. --require-content warns about color roles in those groups
+that have no -content partner.
+
+Gamut: authored colors outside sRGB (or Display-P3 with --gamut=display-p3)
+are reported as warnings, per theme. Browsers clip them on screens that
+cannot show them; tokenctl build --gamut-map maps them in instead.
+
+Forced colors: interactive components (cursor: pointer, :focus or :active
+styles) with a background but no border or outline are reported as
+warnings, since forced colors mode drops the background. A transparent
+border or a $forcedColors block on the component fixes it.
+
+Reduced motion: components whose animation or transition references no
+duration token or keyframe declaring $reducedMotion are reported as
+warnings, since their motion plays in full under prefers-reduced-motion.
+
+Machine-readable diagnostics (--report=json|sarif|junit) cover validation
+errors, layer violations, unconsumed keys and merge conflicts, each with a
+stable rule ID, severity and file:line:column. SARIF uploads to GitHub code
+scanning; JUnit feeds test dashboards. Without --report-file the report is
+written to stdout and progress moves to stderr.
+
 Example token structure with layers:
   {
     "color": {
@@ -41,60 +68,97 @@ Example token structure with layers:
 var (
 	strictLayers      bool
 	validateStrictKey bool
+	contrastLevel     string
+	requireContent    bool
+	gamutTarget       string
 )
 
 func init() {
 	validateCmd.Flags().BoolVar(&strictLayers, "strict-layers", false, "Enforce layer reference rules (brand -> semantic -> component)")
 	validateCmd.Flags().BoolVar(&validateStrictKey, "strict-unknown-keys", false, "Treat unconsumed input keys as validation errors (default: warn)")
+	validateCmd.Flags().StringVar(&contrastLevel, "contrast", "", "Check every X / X-content color pair at a WCAG level (AA, AAA, AA-large, AAA-large), ratio or APCA Lc (Lc60)")
+	validateCmd.Flags().BoolVar(&requireContent, "require-content", false, "With --contrast, warn about color roles without a -content partner")
+	validateCmd.Flags().StringVar(&gamutTarget, "gamut", colors.GamutSRGB, "Warn about authored colors outside this gamut (srgb, display-p3)")
+	addReportFlags(validateCmd)
 	rootCmd.AddCommand(validateCmd)
 }
 
 func runValidate(cmd *cobra.Command, args []string) error {
+	if err := checkReportFlags(); err != nil {
+		return err
+	}
+	if contrastLevel != "" {
+		if _, err := tokens.ParseMinContrast(contrastLevel); err != nil {
+			return fmt.Errorf("--contrast: %w", err)
+		}
+	}
+	gamut, err := colors.ParseGamut(gamutTarget)
+	if err != nil {
+		return fmt.Errorf("--gamut: %w", err)
+	}
+	gamutTarget = gamut
 	dirs := args
 	if len(dirs) == 0 {
 		dirs = []string{"."}
 	}
 
-	fmt.Printf("Validating token system in %s...\n", strings.Join(dirs, ", "))
+	diags, err := validateDirs(dirs)
+	if reportErr := writeReport("validate", diags, err); reportErr != nil {
+		return reportErr
+	}
+	return err
+}
+
+// validateDirs runs every check, printing progress as it goes, and
+// returns what it found for --report
+func validateDirs(dirs []string) ([]tokens.Diagnostic, error) {
+	out := progress()
+	fmt.Fprintf(out, "Validating token system in %s...\n", strings.Join(dirs, ", "))
 
 	// 1. Load Dictionary (Base + Themes)
 	baseDict, themes, findings, err := loadTokens(dirs...)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	hasErrors := false
+	diags := findingDiagnostics(findings, validateStrictKey)
+	diags = append(diags, conflictDiagnostics(baseDict, themes)...)
 
 	// 1b. Report input the build will not consume. A key outside the
 	// vocabulary parses fine and validates fine, then contributes nothing
 	// to the CSS — so validation has to say so out loud or the first
 	// signal is unstyled markup in production.
-	fmt.Println("Checking for unconsumed input keys...")
+	fmt.Fprintln(out, "Checking for unconsumed input keys...")
 	if err := auditLoaded(validateStrictKey, findings); err != nil {
 		hasErrors = true
-		fmt.Printf("  [Error] %v\n", err)
+		fmt.Fprintf(out, "  [Error] %v\n", err)
 	}
 
 	// 2. Validate Base
-	fmt.Println("Checking Base Dictionary...")
+	fmt.Fprintln(out, "Checking Base Dictionary...")
 	errs, err := tokens.Validate(baseDict)
 	if err != nil {
-		return fmt.Errorf("base validation failed to run: %w", err)
+		return diags, fmt.Errorf("base validation failed to run: %w", err)
 	}
+	baseErrs := make(map[tokens.Diagnostic]bool, len(errs))
 	if len(errs) > 0 {
 		hasErrors = true
 		for _, e := range errs {
-			fmt.Printf("  [Error] %s\n", e)
+			fmt.Fprintf(out, "  [Error] %s\n", e)
+			d := e.Diagnostic()
+			baseErrs[d] = true
+			diags = append(diags, d)
 		}
 	} else {
-		fmt.Println("  OK")
+		fmt.Fprintln(out, "  OK")
 	}
 
 	// 3. Validate Themes (Inheritance + Resolution)
 	// Resolve theme inheritance chains (handles $extends)
 	inheritedThemes, err := tokens.ResolveThemeInheritance(baseDict, themes)
 	if err != nil {
-		return fmt.Errorf("theme inheritance failed: %w", err)
+		return diags, fmt.Errorf("theme inheritance failed: %w", err)
 	}
 
 	themeNames := make([]string, 0, len(inheritedThemes))
@@ -105,42 +169,150 @@ func runValidate(cmd *cobra.Command, args []string) error {
 
 	for _, name := range themeNames {
 		merged := inheritedThemes[name]
-		fmt.Printf("Checking Theme '%s'...\n", name)
+		fmt.Fprintf(out, "Checking Theme '%s'...\n", name)
 
 		errs, err := tokens.Validate(merged)
 		if err != nil {
-			return fmt.Errorf("theme validation failed to run: %w", err)
+			return diags, fmt.Errorf("theme validation failed to run: %w", err)
 		}
 		if len(errs) > 0 {
 			hasErrors = true
 			for _, e := range errs {
-				fmt.Printf("  [Error] %s\n", e)
+				fmt.Fprintf(out, "  [Error] %s\n", e)
+				// Errors inherited unchanged from the base are
+				// reported once, against the base
+				d := e.Diagnostic()
+				if baseErrs[d] {
+					continue
+				}
+				d.Theme = name
+				diags = append(diags, d)
 			}
 		} else {
-			fmt.Println("  OK")
+			fmt.Fprintln(out, "  OK")
 		}
 	}
 
 	// 4. Layer Validation (if --strict-layers)
 	if strictLayers {
-		fmt.Println("Checking Layer Rules...")
+		fmt.Fprintln(out, "Checking Layer Rules...")
 		layerValidator := tokens.NewLayerValidator(baseDict)
 		violations := layerValidator.ValidateReferences(baseDict)
 
 		if len(violations) > 0 {
 			hasErrors = true
 			for _, v := range violations {
-				fmt.Printf("  [Error] %s\n", v)
+				fmt.Fprintf(out, "  [Error] %s\n", v)
+				diags = append(diags, v.Diagnostic())
+			}
+		} else {
+			fmt.Fprintln(out, "  OK")
+		}
+	}
+
+	// 5. Contrast: pairs declared with $contrastWith, and with
+	// --contrast every X / X-content pair, in the base and every theme
+	fmt.Fprintln(out, "Checking Contrast...")
+	opts := tokens.ContrastOptions{Level: contrastLevel, RequireContent: requireContent}
+	issues, err := tokens.CheckContrast(baseDict, opts)
+	if err != nil {
+		return diags, fmt.Errorf("contrast check failed to run: %w", err)
+	}
+	baseIssues := make(map[tokens.ContrastIssue]bool, len(issues))
+	for _, issue := range issues {
+		baseIssues[issue] = true
+	}
+	for _, name := range themeNames {
+		themeIssues, err := tokens.CheckContrast(inheritedThemes[name], opts)
+		if err != nil {
+			return diags, fmt.Errorf("contrast check failed to run for theme %s: %w", name, err)
+		}
+		// A failure inherited unchanged from the base is reported once
+		for _, issue := range themeIssues {
+			if !baseIssues[issue] {
+				issue.Theme = name
+				issues = append(issues, issue)
 			}
+		}
+	}
+	for _, issue := range issues {
+		label := "Error"
+		if issue.Missing {
+			label = "Warning"
 		} else {
-			fmt.Println("  OK")
+			hasErrors = true
+		}
+		fmt.Fprintf(out, "  [%s] %s\n", label, issue)
+		diags = append(diags, issue.Diagnostic())
+	}
+	if len(issues) == 0 {
+		fmt.Fprintln(out, "  OK")
+	}
+
+	// 6. Gamut: authored colors a screen of the target gamut would clip
+	fmt.Fprintln(out, "Checking Gamut...")
+	gamutIssues, err := tokens.CheckGamut(baseDict, gamutTarget)
+	if err != nil {
+		return diags, fmt.Errorf("gamut check failed to run: %w", err)
+	}
+	baseGamut := make(map[tokens.GamutIssue]bool, len(gamutIssues))
+	for _, issue := range gamutIssues {
+		baseGamut[issue] = true
+	}
+	for _, name := range themeNames {
+		themeIssues, err := tokens.CheckGamut(inheritedThemes[name], gamutTarget)
+		if err != nil {
+			return diags, fmt.Errorf("gamut check failed to run for theme %s: %w", name, err)
+		}
+		for _, issue := range themeIssues {
+			if !baseGamut[issue] {
+				issue.Theme = name
+				gamutIssues = append(gamutIssues, issue)
+			}
 		}
 	}
+	for _, issue := range gamutIssues {
+		fmt.Fprintf(out, "  [Warning] %s\n", issue)
+		diags = append(diags, issue.Diagnostic())
+	}
+	if len(gamutIssues) == 0 {
+		fmt.Fprintln(out, "  OK")
+	}
+
+	// 7. Forced colors: interactive components that lose their edge
+	// when the browser drops backgrounds
+	fmt.Fprintln(out, "Checking Forced Colors...")
+	forcedIssues, err := tokens.CheckForcedColors(baseDict)
+	if err != nil {
+		return diags, fmt.Errorf("forced colors check failed to run: %w", err)
+	}
+	for _, issue := range forcedIssues {
+		fmt.Fprintf(out, "  [Warning] %s\n", issue)
+		diags = append(diags, issue.Diagnostic())
+	}
+	if len(forcedIssues) == 0 {
+		fmt.Fprintln(out, "  OK")
+	}
+
+	// 8. Reduced motion: components whose motion ignores
+	// prefers-reduced-motion
+	fmt.Fprintln(out, "Checking Reduced Motion...")
+	motionIssues, err := tokens.CheckReducedMotion(baseDict)
+	if err != nil {
+		return diags, fmt.Errorf("reduced motion check failed to run: %w", err)
+	}
+	for _, issue := range motionIssues {
+		fmt.Fprintf(out, "  [Warning] %s\n", issue)
+		diags = append(diags, issue.Diagnostic())
+	}
+	if len(motionIssues) == 0 {
+		fmt.Fprintln(out, "  OK")
+	}
 
 	if hasErrors {
-		return fmt.Errorf("validation failed")
+		return diags, fmt.Errorf("validation failed")
 	}
 
-	fmt.Println("\nValidation Passed!")
-	return nil
+	fmt.Fprintln(out, "\nValidation Passed!")
+	return diags, nil
 }
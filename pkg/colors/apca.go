@@ -0,0 +1,167 @@
+// tokenctl/pkg/colors/apca.go
+
+package colors
+
+import (
+	"fmt"
+	"math"
+)
+
+// Contrast algorithms
+const (
+	AlgorithmWCAG2 = "wcag2"
+	AlgorithmAPCA  = "apca"
+)
+
+// ParseAlgorithm validates a contrast algorithm name; empty means WCAG 2
+func ParseAlgorithm(name string) (string, error) {
+	switch name {
+	case "", AlgorithmWCAG2:
+		return AlgorithmWCAG2, nil
+	case AlgorithmAPCA:
+		return AlgorithmAPCA, nil
+	default:
+		return "", fmt.Errorf("unknown contrast algorithm: %s (valid: %s, %s)", name, AlgorithmWCAG2, AlgorithmAPCA)
+	}
+}
+
+// APCA 0.0.98G-4g constants
+const (
+	apcaMainTRC   = 2.4
+	apcaNormBG    = 0.56
+	apcaNormTXT   = 0.57
+	apcaRevTXT    = 0.62
+	apcaRevBG     = 0.65
+	apcaBlkThrs   = 0.022
+	apcaBlkClmp   = 1.414
+	apcaScale     = 1.14
+	apcaLoOffset  = 0.027
+	apcaDeltaYMin = 0.0005
+	apcaLoClip    = 0.1
+	apcaLumaRed   = 0.2126729
+	apcaLumaGreen = 0.7151522
+	apcaLumaBlue  = 0.0721750
+	apcaLcScale   = 100
+)
+
+// APCA Bronze simple-mode levels (|Lc|)
+const (
+	APCABodyPreferred = 90 // fluent body text
+	APCABodyText      = 75 // minimum for body text columns
+	APCAContentText   = 60 // content text that is not body text
+	APCALargeText     = 45 // headlines and large, heavy text
+	APCASpotText      = 30 // placeholder, disabled and other spot text
+	APCANonText       = 15 // non-text elements that must be discernible
+)
+
+// apcaY is APCA's screen luminance estimate: a plain 2.4 power curve
+// rather than the piecewise sRGB transfer WCAG 2 uses
+func apcaY(c Color) float64 {
+	cl := c.Color.Clamped()
+	return apcaLumaRed*math.Pow(cl.R, apcaMainTRC) +
+		apcaLumaGreen*math.Pow(cl.G, apcaMainTRC) +
+		apcaLumaBlue*math.Pow(cl.B, apcaMainTRC)
+}
+
+// APCAContrast returns the APCA lightness contrast (Lc) of text on a
+// background. Unlike the WCAG 2 ratio it is not symmetric: dark text on
+// a light background gives a positive Lc, light text on a dark background
+// a negative one. Magnitudes run from 0 to about 106 (108 at most).
+func APCAContrast(text, background Color) float64 {
+	yText := apcaSoftClamp(apcaY(text))
+	yBG := apcaSoftClamp(apcaY(background))
+
+	if math.Abs(yBG-yText) < apcaDeltaYMin {
+		return 0
+	}
+
+	var lc float64
+	if yBG > yText {
+		// Normal polarity: dark text on a light background
+		sapc := (math.Pow(yBG, apcaNormBG) - math.Pow(yText, apcaNormTXT)) * apcaScale
+		if sapc >= apcaLoClip {
+			lc = sapc - apcaLoOffset
+		}
+	} else {
+		// Reverse polarity: light text on a dark background
+		sapc := (math.Pow(yBG, apcaRevBG) - math.Pow(yText, apcaRevTXT)) * apcaScale
+		if sapc <= -apcaLoClip {
+			lc = sapc + apcaLoOffset
+		}
+	}
+	return lc * apcaLcScale
+}
+
+// apcaSoftClamp lifts near-black luminance to model flare, so very dark
+// pairs are not over-credited
+func apcaSoftClamp(y float64) float64 {
+	if y >= apcaBlkThrs {
+		return y
+	}
+	return y + math.Pow(apcaBlkThrs-y, apcaBlkClmp)
+}
+
+// APCAContentColor returns white or black, whichever has the larger
+// |Lc| as text on background
+func APCAContentColor(background Color) Color {
+	if math.Abs(APCAContrast(White(), background)) >= math.Abs(APCAContrast(Black(), background)) {
+		return White()
+	}
+	return Black()
+}
+
+// apcaFontMinimums gives, for each Bronze level, the smallest font size
+// in px at each weight that the level covers. Weights not listed at a
+// level are not readable at that level at any size.
+var apcaFontMinimums = []struct {
+	lc    float64
+	sizes map[int]float64 // weight -> minimum px
+}{
+	{APCALargeText, map[int]float64{400: 36, 700: 24}},
+	{APCAContentText, map[int]float64{200: 48, 300: 36, 400: 24, 500: 21, 600: 18, 700: 16}},
+	{APCABodyText, map[int]float64{300: 24, 400: 18, 500: 16, 700: 14}},
+	{APCABodyPreferred, map[int]float64{300: 18, 400: 14}},
+}
+
+// minimumSize returns the smallest px size a level allows at weight,
+// reading weights between listed ones as the next lighter listed weight
+func minimumSize(sizes map[int]float64, weight int) (float64, bool) {
+	best := -1
+	for w := range sizes {
+		if w <= weight && w > best {
+			best = w
+		}
+	}
+	if best < 0 {
+		return 0, false
+	}
+	return sizes[best], true
+}
+
+// APCAMinimumLc returns the |Lc| text of the given size (px) and weight
+// (100-900) needs under APCA Bronze simple mode. ok is false when the
+// text is too small or thin for any level.
+func APCAMinimumLc(fontSizePx float64, weight int) (lc float64, ok bool) {
+	for _, level := range apcaFontMinimums {
+		if minSize, listed := minimumSize(level.sizes, weight); listed && fontSizePx >= minSize {
+			return level.lc, true
+		}
+	}
+	return 0, false
+}
+
+// APCAMinimumFontSize returns the smallest font size (px) at weight that
+// a contrast of lc supports. ok is false when |lc| is below the large
+// text level or no level up to |lc| covers the weight.
+func APCAMinimumFontSize(lc float64, weight int) (px float64, ok bool) {
+	lc = math.Abs(lc)
+	for _, level := range apcaFontMinimums {
+		if level.lc > lc {
+			break
+		}
+		if size, listed := minimumSize(level.sizes, weight); listed && (!ok || size < px) {
+			px, ok = size, true
+		}
+	}
+	return px, ok
+}
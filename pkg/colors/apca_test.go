@@ -0,0 +1,100 @@
+// tokenctl/pkg/colors/apca_test.go
+
+package colors
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAPCAContrast(t *testing.T) {
+	t.Parallel()
+	// Reference values from the APCA 0.0.98G-4g test suite
+	tests := []struct {
+		text, bg string
+		want     float64
+	}{
+		{"#888", "#fff", 63.056469930209424},
+		{"#fff", "#888", -68.54146436644962},
+		{"#000", "#aaa", 58.146262578561334},
+		{"#aaa", "#000", -56.24113336839742},
+		{"#123", "#def", 91.66830811481631},
+		{"#def", "#123", -93.06770049484275},
+		{"#123", "#234", 0}, // below the low clip
+		{"#3b82f6", "#3b82f6", 0},
+	}
+	for _, tt := range tests {
+		got := APCAContrast(MustParse(tt.text), MustParse(tt.bg))
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("APCAContrast(%s on %s) = %v, want %v", tt.text, tt.bg, got, tt.want)
+		}
+	}
+}
+
+func TestAPCAContentColor(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		bg   string
+		want string
+	}{
+		{"#ffffff", "#000000"},
+		{"#1e3a8a", "#ffffff"},
+		// WCAG 2 picks black here (7.5:1 vs 2.8:1); APCA prefers white (Lc -58 vs 51)
+		{"#f97316", "#ffffff"},
+	}
+	for _, tt := range tests {
+		if got := APCAContentColor(MustParse(tt.bg)).Hex(); got != tt.want {
+			t.Errorf("APCAContentColor(%s) = %s, want %s", tt.bg, got, tt.want)
+		}
+	}
+}
+
+func TestAPCAMinimumLc(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		size   float64
+		weight int
+		want   float64
+		ok     bool
+	}{
+		{16, 400, APCABodyPreferred, true},
+		{18, 400, APCABodyText, true},
+		{24, 400, APCAContentText, true},
+		{36, 400, APCALargeText, true},
+		{24, 700, APCALargeText, true},
+		{16, 700, APCAContentText, true},
+		{24, 900, APCALargeText, true}, // heavier than listed reads as 700
+		{40, 300, APCAContentText, true},
+		{12, 400, 0, false},
+		{96, 100, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := APCAMinimumLc(tt.size, tt.weight)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("APCAMinimumLc(%v, %d) = %v, %v; want %v, %v", tt.size, tt.weight, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestAPCAMinimumFontSize(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		lc     float64
+		weight int
+		want   float64
+		ok     bool
+	}{
+		{-90, 400, 14, true},
+		{75, 400, 18, true},
+		{62, 700, 16, true},
+		{45, 400, 36, true},
+		{45, 300, 0, false},
+		{30, 700, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := APCAMinimumFontSize(tt.lc, tt.weight)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("APCAMinimumFontSize(%v, %d) = %v, %v; want %v, %v", tt.lc, tt.weight, got, ok, tt.want, tt.ok)
+		}
+	}
+}
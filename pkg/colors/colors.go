@@ -4,7 +4,7 @@ package colors
 
 import (
 	"fmt"
-	"regexp"
+	"math"
 	"strconv"
 	"strings"
 
@@ -14,24 +14,58 @@ import (
 // Color wraps go-colorful.Color with additional utilities for CSS color handling
 type Color struct {
 	colorful.Color
-	originalFormat string // preserve input format for round-trip output
+	originalFormat string  // preserve input format for round-trip output
+	transparency   float64 // 1 - alpha, so the zero Color is opaque
+	src            source  // channels as written, for round-trip output
+}
+
+// source is a color as it was written, so it can be written back without
+// a round trip through sRGB. It holds only while the color is unchanged.
+type source struct {
+	color  colorful.Color // the color the channels were read as
+	format string
+	ch     [3]float64 // channels in the units of format's function
+	none   [3]bool    // channels written as none
+	modern bool       // rgb() or hsl() in space-separated syntax
+	name   string     // a named color as written
+}
+
+// authored returns the channels c was written with when it was parsed
+// in format and has not changed since
+func (c Color) authored(format string) (source, bool) {
+	return c.src, c.src.format == format && c.src.color == c.Color
 }
 
 // Format constants for output
 const (
-	FormatHex   = "hex"
-	FormatRGB   = "rgb"
-	FormatHSL   = "hsl"
-	FormatOKLCH = "oklch"
+	FormatHex        = "hex"
+	FormatRGB        = "rgb"
+	FormatHSL        = "hsl"
+	FormatHWB        = "hwb"
+	FormatLab        = "lab"
+	FormatLCH        = "lch"
+	FormatOKLab      = "oklab"
+	FormatOKLCH      = "oklch"
+	FormatP3         = "p3"          // color(display-p3 r g b)
+	FormatSRGB       = "srgb"        // color(srgb r g b)
+	FormatSRGBLinear = "srgb-linear" // color(srgb-linear r g b)
+	FormatXYZ        = "xyz"         // color(xyz-d65 x y z)
+	FormatXYZD50     = "xyz-d50"     // color(xyz-d50 x y z)
+	FormatNamed      = "named"       // a CSS named color, hex once changed
 )
 
-// Parse accepts any CSS color format and returns a normalized Color
+// Parse accepts any CSS Color 4 color and returns a normalized Color
 // Supported formats:
-//   - Hex: #fff, #ffffff, #ffffffff (with alpha)
-//   - RGB: rgb(255, 128, 0), rgb(255 128 0), rgba(255, 128, 0, 0.5)
-//   - HSL: hsl(180, 50%, 50%), hsla(180, 50%, 50%, 0.5)
-//   - OKLCH: oklch(0.5 0.2 180), oklch(50% 0.2 180)
-//   - Named colors: red, blue, etc. (limited set)
+//   - Hex: #rgb, #rgba, #rrggbb, #rrggbbaa
+//   - rgb(), rgba(), hsl(), hsla(): legacy comma and modern space syntax
+//   - hwb(), lab(), lch(), oklab(), oklch()
+//   - color(): srgb, srgb-linear, display-p3, xyz, xyz-d65, xyz-d50
+//   - Relative colors: oklch(from #3b82f6 calc(l - 0.1) c h)
+//   - The 148 CSS named colors and transparent
+//
+// Channels accept none, percentages and, for hues, deg/rad/grad/turn
+// units. A relative color whose origin is var() or currentcolor returns
+// an error wrapping ErrUnresolvedOrigin.
 func Parse(input string) (Color, error) {
 	input = strings.TrimSpace(input)
 	if input == "" {
@@ -43,43 +77,25 @@ func Parse(input string) (Color, error) {
 
 	// Hex format
 	if strings.HasPrefix(input, "#") {
-		c, err := parseHex(input)
-		if err != nil {
-			return Color{}, err
-		}
-		return Color{Color: c, originalFormat: FormatHex}, nil
-	}
-
-	// RGB/RGBA format
-	if strings.HasPrefix(lower, "rgb") {
-		c, err := parseRGB(input)
-		if err != nil {
-			return Color{}, err
-		}
-		return Color{Color: c, originalFormat: FormatRGB}, nil
+		return parseHex(input)
 	}
 
-	// HSL/HSLA format
-	if strings.HasPrefix(lower, "hsl") {
-		c, err := parseHSL(input)
-		if err != nil {
-			return Color{}, err
-		}
-		return Color{Color: c, originalFormat: FormatHSL}, nil
+	// Color functions
+	if strings.Contains(input, "(") {
+		return parseColorFunction(input)
 	}
 
-	// OKLCH format
-	if strings.HasPrefix(lower, "oklch") {
-		c, err := parseOKLCH(input)
+	if hex, ok := namedColors[lower]; ok {
+		c, err := parseHex(hex)
 		if err != nil {
 			return Color{}, err
 		}
-		return Color{Color: c, originalFormat: FormatOKLCH}, nil
+		c.originalFormat = FormatNamed
+		c.src = source{color: c.Color, format: FormatNamed, name: input}
+		return c, nil
 	}
-
-	// Try named colors
-	if c, ok := namedColors[lower]; ok {
-		return Color{Color: c, originalFormat: FormatHex}, nil
+	if lower == "transparent" {
+		return Color{originalFormat: FormatNamed, transparency: 1, src: source{format: FormatNamed, name: input}}, nil
 	}
 
 	return Color{}, fmt.Errorf("unrecognized color format: %s", input)
@@ -94,156 +110,240 @@ func MustParse(input string) Color {
 	return c
 }
 
-// parseHex parses hex color formats: #rgb, #rrggbb, #rrggbbaa
-func parseHex(input string) (colorful.Color, error) {
-	// go-colorful's Hex() handles #rrggbb
-	// We need to also handle #rgb shorthand and #rrggbbaa
+// parseHex parses hex color formats: #rgb, #rgba, #rrggbb, #rrggbbaa
+func parseHex(input string) (Color, error) {
 	hex := strings.TrimPrefix(input, "#")
 
 	switch len(hex) {
-	case 3:
-		// Expand #rgb to #rrggbb
-		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
-	case 4:
-		// Expand #rgba to #rrggbbaa (we ignore alpha for now)
-		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
-	case 6:
+	case 3, 4:
+		// Expand #rgb(a) to #rrggbb(aa)
+		long := make([]byte, 0, 2*len(hex))
+		for i := range len(hex) {
+			long = append(long, hex[i], hex[i])
+		}
+		hex = string(long)
+	case 6, 8:
 		// Standard format, use as-is
-	case 8:
-		// Has alpha, strip it (go-colorful doesn't handle alpha)
-		hex = hex[:6]
 	default:
-		return colorful.Color{}, fmt.Errorf("invalid hex color: %s", input)
-	}
-
-	return colorful.Hex("#" + hex)
-}
-
-// parseRGB parses rgb() and rgba() formats
-// Supports both comma and space separators
-var rgbRegex = regexp.MustCompile(`rgba?\s*\(\s*([0-9.]+%?)\s*[,\s]\s*([0-9.]+%?)\s*[,\s]\s*([0-9.]+%?)(?:\s*[,/]\s*([0-9.]+%?))?\s*\)`)
-
-func parseRGB(input string) (colorful.Color, error) {
-	matches := rgbRegex.FindStringSubmatch(input)
-	if matches == nil {
-		return colorful.Color{}, fmt.Errorf("invalid rgb color: %s", input)
+		return Color{}, fmt.Errorf("invalid hex color: %s", input)
 	}
 
-	r, err := parseColorComponent(matches[1], 255)
+	// go-colorful's Hex() handles #rrggbb; alpha is read here
+	c, err := colorful.Hex("#" + hex[:6])
 	if err != nil {
-		return colorful.Color{}, fmt.Errorf("invalid red component: %w", err)
+		return Color{}, err
 	}
-
-	g, err := parseColorComponent(matches[2], 255)
-	if err != nil {
-		return colorful.Color{}, fmt.Errorf("invalid green component: %w", err)
-	}
-
-	b, err := parseColorComponent(matches[3], 255)
-	if err != nil {
-		return colorful.Color{}, fmt.Errorf("invalid blue component: %w", err)
+	alpha := 1.0
+	if len(hex) == 8 {
+		a, err := strconv.ParseUint(hex[6:], 16, 8)
+		if err != nil {
+			return Color{}, fmt.Errorf("invalid hex color: %s", input)
+		}
+		alpha = float64(a) / 255
 	}
-
-	// Alpha is ignored for now (matches[4])
-
-	return colorful.Color{R: r, G: g, B: b}, nil
+	return Color{Color: c, originalFormat: FormatHex}.WithAlpha(alpha), nil
 }
 
-// parseHSL parses hsl() and hsla() formats
-var hslRegex = regexp.MustCompile(`hsla?\s*\(\s*([0-9.]+)(?:deg)?\s*[,\s]\s*([0-9.]+)%\s*[,\s]\s*([0-9.]+)%(?:\s*[,/]\s*([0-9.]+%?))?\s*\)`)
-
-func parseHSL(input string) (colorful.Color, error) {
-	matches := hslRegex.FindStringSubmatch(input)
-	if matches == nil {
-		return colorful.Color{}, fmt.Errorf("invalid hsl color: %s", input)
-	}
-
-	h, err := strconv.ParseFloat(matches[1], 64)
-	if err != nil {
-		return colorful.Color{}, fmt.Errorf("invalid hue: %w", err)
-	}
-
-	s, err := strconv.ParseFloat(matches[2], 64)
-	if err != nil {
-		return colorful.Color{}, fmt.Errorf("invalid saturation: %w", err)
-	}
-
-	l, err := strconv.ParseFloat(matches[3], 64)
-	if err != nil {
-		return colorful.Color{}, fmt.Errorf("invalid lightness: %w", err)
-	}
+// Alpha returns the color's opacity, 0-1
+func (c Color) Alpha() float64 {
+	return 1 - c.transparency
+}
 
-	// go-colorful expects s and l in 0-1 range
-	return colorful.Hsl(h, s/100, l/100), nil
+// WithAlpha returns a copy of the color with the given opacity, clamped
+// to 0-1
+func (c Color) WithAlpha(alpha float64) Color {
+	c.transparency = 1 - math.Max(0, math.Min(1, alpha))
+	return c
 }
 
-// parseOKLCH parses oklch() format
-// CSS syntax: oklch(L C H) where L is 0-1 or 0%-100%, C is typically 0-0.4, H is 0-360
-var oklchRegex = regexp.MustCompile(`oklch\s*\(\s*([0-9.]+)(%?)\s+([0-9.]+)\s+([0-9.]+)\s*\)`)
+// with returns col carrying c's original format and alpha
+func (c Color) with(col colorful.Color) Color {
+	c.Color = col
+	return c
+}
 
-func parseOKLCH(input string) (colorful.Color, error) {
-	matches := oklchRegex.FindStringSubmatch(input)
-	if matches == nil {
-		return colorful.Color{}, fmt.Errorf("invalid oklch color: %s", input)
+// ParseFormat validates an output format name; "display-p3" is accepted
+// for p3
+func ParseFormat(name string) (string, error) {
+	switch name {
+	case FormatHex, FormatRGB, FormatHSL, FormatHWB, FormatLab, FormatLCH, FormatOKLab, FormatOKLCH,
+		FormatP3, FormatSRGB, FormatSRGBLinear, FormatXYZ, FormatXYZD50:
+		return name, nil
+	case "display-p3":
+		return FormatP3, nil
+	default:
+		return "", fmt.Errorf("unknown color format: %s (valid: hex, rgb, hsl, hwb, lab, lch, oklab, oklch, p3, srgb, srgb-linear, xyz, xyz-d50)", name)
 	}
+}
 
-	l, err := strconv.ParseFloat(matches[1], 64)
-	if err != nil {
-		return colorful.Color{}, fmt.Errorf("invalid lightness: %w", err)
-	}
+// ToCSS outputs the color in the specified format, with the color's
+// alpha when it is translucent
+func (c Color) ToCSS(format string) string {
+	return c.ToCSSPrecision(format, -1)
+}
 
-	// If percentage, convert to 0-1
-	if matches[2] == "%" {
-		l = l / 100
+// ToCSSPrecision is ToCSS with channels written to the given number of
+// decimals. A negative precision keeps each format's default: whole
+// numbers for rgb(), two decimals for oklch() lightness and hue, three
+// for its chroma, and so on. Hex output has no decimals to set.
+//
+// A color written in format, and not changed since, keeps its channels
+// as written, including none, and rgb() and hsl() keep the modern syntax
+// they were written in; by default each channel is written as authored.
+// A hue that an achromatic color does not use is written as none.
+func (c Color) ToCSSPrecision(format string, precision int) string {
+	// sRGB formats cannot hold channels outside 0-1; callers that want
+	// the color kept in sRGB gamut-map it first
+	switch format {
+	case FormatHex, FormatRGB, FormatHSL, FormatHWB, FormatNamed:
+		c = c.Clamped()
 	}
 
-	c, err := strconv.ParseFloat(matches[3], 64)
-	if err != nil {
-		return colorful.Color{}, fmt.Errorf("invalid chroma: %w", err)
+	src, authored := c.authored(format)
+	ch := src.ch
+	if !authored {
+		ch = formatChannels(c, format)
 	}
-
-	h, err := strconv.ParseFloat(matches[4], 64)
-	if err != nil {
-		return colorful.Color{}, fmt.Errorf("invalid hue: %w", err)
+	modern := authored && src.modern
+
+	// num formats channel i, scaled, at precision or def decimals by
+	// default, without the sign of a value that rounds to zero. An
+	// authored channel is written as it was by default.
+	num := func(i int, scale float64, def int) string {
+		v := ch[i] * scale
+		if precision >= 0 {
+			def = precision
+		} else if authored {
+			v, def = math.Round(v*1e6)/1e6, -1
+		}
+		out := strconv.FormatFloat(v, 'f', def, 64)
+		if strings.Trim(out, "-0.") == "" {
+			out = strings.TrimPrefix(out, "-")
+		}
+		return out
 	}
-
-	return colorful.OkLch(l, c, h), nil
-}
-
-// parseColorComponent parses a color component value (0-255 or 0%-100%)
-func parseColorComponent(s string, max float64) (float64, error) {
-	s = strings.TrimSpace(s)
-	if pct, found := strings.CutSuffix(s, "%"); found {
-		v, err := strconv.ParseFloat(pct, 64)
-		if err != nil {
-			return 0, err
+	// channel is num for modern syntax, where a channel can be none
+	channel := func(i int, scale float64, def int, unit string) string {
+		if authored && src.none[i] {
+			return "none"
 		}
-		return v / 100, nil
+		return num(i, scale, def) + unit
 	}
-
-	v, err := strconv.ParseFloat(s, 64)
-	if err != nil {
-		return 0, err
+	// hue is channel for a hue, which is none when chroma rounds to zero
+	hue := func(i int, chroma string) string {
+		if strings.Trim(chroma, "0.%") == "" {
+			return "none"
+		}
+		return channel(i, 1, 2, "")
 	}
-	return v / max, nil
-}
 
-// ToCSS outputs the color in the specified format
-func (c Color) ToCSS(format string) string {
 	switch format {
+	case FormatNamed:
+		if authored && c.Alpha() == namedAlpha(src.name) {
+			return src.name
+		}
+		return c.Hex()
 	case FormatHex:
 		return c.Hex()
 	case FormatRGB:
-		return c.ToRGB()
+		if modern {
+			return c.closeCSS(fmt.Sprintf("rgb(%s %s %s", channel(0, 1, 0, ""), channel(1, 1, 0, ""), channel(2, 1, 0, "")))
+		}
+		if precision < 0 && !authored {
+			return c.ToRGB()
+		}
+		fn := fmt.Sprintf("rgb(%s, %s, %s", num(0, 1, 0), num(1, 1, 0), num(2, 1, 0))
+		if c.Alpha() < 1 {
+			return "rgba" + strings.TrimPrefix(fn, "rgb") + ", " + c.alphaString() + ")"
+		}
+		return fn + ")"
 	case FormatHSL:
-		return c.ToHSL()
+		if modern {
+			sat := channel(1, 1, 1, "%")
+			return c.closeCSS(fmt.Sprintf("hsl(%s %s %s", hue(0, sat), sat, channel(2, 1, 1, "%")))
+		}
+		fn := fmt.Sprintf("hsl(%s, %s%%, %s%%", num(0, 1, 1), num(1, 1, 1), num(2, 1, 1))
+		if c.Alpha() < 1 {
+			return "hsla" + strings.TrimPrefix(fn, "hsl") + ", " + c.alphaString() + ")"
+		}
+		return fn + ")"
+	case FormatHWB:
+		// Whiteness and blackness that add up to 100% leave no hue
+		h := channel(0, 1, 1, "")
+		if ch[1]+ch[2] > 100-1e-9 {
+			h = "none"
+		}
+		return c.closeCSS(fmt.Sprintf("hwb(%s %s %s", h, channel(1, 1, 1, "%"), channel(2, 1, 1, "%")))
+	case FormatLab:
+		return c.closeCSS(fmt.Sprintf("lab(%s %s %s", channel(0, 1, 2, "%"), channel(1, 1, 2, ""), channel(2, 1, 2, "")))
+	case FormatLCH:
+		chroma := channel(1, 1, 2, "")
+		return c.closeCSS(fmt.Sprintf("lch(%s %s %s", channel(0, 1, 2, "%"), chroma, hue(2, chroma)))
+	case FormatOKLab:
+		return c.closeCSS(fmt.Sprintf("oklab(%s %s %s", channel(0, 100, 2, "%"), channel(1, 1, 3, ""), channel(2, 1, 3, "")))
 	case FormatOKLCH:
-		return c.ToOKLCH()
+		chroma := channel(1, 1, 3, "")
+		return c.closeCSS(fmt.Sprintf("oklch(%s %s %s", channel(0, 100, 2, "%"), chroma, hue(2, chroma)))
+	case FormatP3:
+		return c.colorFunctionCSS("display-p3", channel(0, 1, 4, ""), channel(1, 1, 4, ""), channel(2, 1, 4, ""))
+	case FormatSRGB:
+		return c.colorFunctionCSS("srgb", channel(0, 1, 4, ""), channel(1, 1, 4, ""), channel(2, 1, 4, ""))
+	case FormatSRGBLinear:
+		return c.colorFunctionCSS("srgb-linear", channel(0, 1, 4, ""), channel(1, 1, 4, ""), channel(2, 1, 4, ""))
+	case FormatXYZ:
+		return c.colorFunctionCSS("xyz-d65", channel(0, 1, 4, ""), channel(1, 1, 4, ""), channel(2, 1, 4, ""))
+	case FormatXYZD50:
+		return c.colorFunctionCSS("xyz-d50", channel(0, 1, 4, ""), channel(1, 1, 4, ""), channel(2, 1, 4, ""))
 	default:
 		return c.Hex()
 	}
 }
 
+// formatChannels returns c's channels in the units of format's color
+// function, as parsing reads them
+func formatChannels(c Color, format string) [3]float64 {
+	for _, spec := range colorSpecs {
+		if spec.format == format {
+			a, b, ch := spec.channels(c)
+			return [3]float64{a, b, ch}
+		}
+	}
+	return [3]float64{}
+}
+
+// namedAlpha is the opacity of a named color: transparent or opaque
+func namedAlpha(name string) float64 {
+	if strings.EqualFold(name, "transparent") {
+		return 0
+	}
+	return 1
+}
+
+// ToCSSWithAlpha outputs the color in the specified format with the
+// given alpha (0-1) in place of its own. Fully opaque colors are emitted
+// without an alpha channel.
+func (c Color) ToCSSWithAlpha(format string, alpha float64) string {
+	return c.WithAlpha(alpha).ToCSS(format)
+}
+
+// closeCSS closes a space-separated color function, adding "/ alpha"
+// when the color is translucent
+func (c Color) closeCSS(fn string) string {
+	if c.Alpha() < 1 {
+		return fn + " / " + c.alphaString() + ")"
+	}
+	return fn + ")"
+}
+
+func (c Color) colorFunctionCSS(space, a, b, ch string) string {
+	return c.closeCSS(fmt.Sprintf("color(%s %s %s %s", space, a, b, ch))
+}
+
+// alphaString formats alpha to at most three decimals
+func (c Color) alphaString() string {
+	return strconv.FormatFloat(math.Round(c.Alpha()*1000)/1000, 'f', -1, 64)
+}
+
 // ToOriginalFormat outputs the color in its original parsed format
 func (c Color) ToOriginalFormat() string {
 	return c.ToCSS(c.originalFormat)
@@ -254,28 +354,37 @@ func (c Color) OriginalFormat() string {
 	return c.originalFormat
 }
 
-// Hex returns the color as a hex string (#rrggbb)
+// Hex returns the color as a hex string: #rrggbb, or #rrggbbaa when the
+// color is translucent. Channels outside sRGB are clamped.
 func (c Color) Hex() string {
+	c = c.Clamped()
+	if c.Alpha() < 1 {
+		return fmt.Sprintf("%s%02x", c.Color.Hex(), uint8(math.Round(c.Alpha()*255)))
+	}
 	return c.Color.Hex()
 }
 
-// ToRGB returns the color as an rgb() CSS string
+// ToRGB returns the color as an rgb() CSS string, or rgba() when the
+// color is translucent. Channels outside sRGB are clamped.
 func (c Color) ToRGB() string {
-	r, g, b := c.Color.RGB255()
+	r, g, b := c.Color.Clamped().RGB255()
+	if c.Alpha() < 1 {
+		return fmt.Sprintf("rgba(%d, %d, %d, %s)", r, g, b, c.alphaString())
+	}
 	return fmt.Sprintf("rgb(%d, %d, %d)", r, g, b)
 }
 
-// ToHSL returns the color as an hsl() CSS string
+// ToHSL returns the color as an hsl() CSS string, or hsla() when the
+// color is translucent
 func (c Color) ToHSL() string {
-	h, s, l := c.Hsl()
-	return fmt.Sprintf("hsl(%.1f, %.1f%%, %.1f%%)", h, s*100, l*100)
+	return c.ToCSS(FormatHSL)
 }
 
 // ToOKLCH returns the color as an oklch() CSS string
-// Format matches DaisyUI convention: oklch(L% C H)
+// Format matches DaisyUI convention: oklch(L% C H), with "/ alpha" when
+// the color is translucent
 func (c Color) ToOKLCH() string {
-	l, ch, h := c.Color.OkLch()
-	return fmt.Sprintf("oklch(%.2f%% %.3f %.2f)", l*100, ch, h)
+	return c.ToCSS(FormatOKLCH)
 }
 
 // RGB255 returns the RGB components as 0-255 integers
@@ -295,25 +404,7 @@ func (c Color) IsValid() bool {
 
 // Clamped returns a copy of the color clamped to valid RGB range
 func (c Color) Clamped() Color {
-	return Color{Color: c.Color.Clamped(), originalFormat: c.originalFormat}
-}
-
-// namedColors maps CSS named colors to their colorful.Color values
-var namedColors = map[string]colorful.Color{
-	"black":   {R: 0, G: 0, B: 0},
-	"white":   {R: 1, G: 1, B: 1},
-	"red":     {R: 1, G: 0, B: 0},
-	"green":   {R: 0, G: 0.502, B: 0}, // CSS green is #008000
-	"blue":    {R: 0, G: 0, B: 1},
-	"yellow":  {R: 1, G: 1, B: 0},
-	"cyan":    {R: 0, G: 1, B: 1},
-	"magenta": {R: 1, G: 0, B: 1},
-	"orange":  {R: 1, G: 0.647, B: 0},
-	"purple":  {R: 0.502, G: 0, B: 0.502},
-	"pink":    {R: 1, G: 0.753, B: 0.796},
-	"gray":    {R: 0.502, G: 0.502, B: 0.502},
-	"grey":    {R: 0.502, G: 0.502, B: 0.502},
-	// Add more as needed
+	return c.with(c.Color.Clamped())
 }
 
 // White returns a white color
@@ -3,6 +3,7 @@
 package colors
 
 import (
+	"fmt"
 	"math"
 	"testing"
 )
@@ -463,6 +464,62 @@ func TestColor_ToCSS(t *testing.T) {
 	}
 }
 
+func TestColor_ToCSSPrecision(t *testing.T) {
+	t.Parallel()
+
+	c := MustParse("#3b82f6")
+
+	tests := []struct {
+		format    string
+		precision int
+		want      string
+	}{
+		{FormatRGB, -1, "rgb(59, 130, 246)"},
+		{FormatRGB, 2, "rgb(59.00, 130.00, 246.00)"},
+		{FormatHSL, 0, "hsl(217, 91%, 60%)"},
+		{FormatOKLCH, 1, "oklch(62.3% 0.2 259.8)"},
+		{FormatP3, 2, "color(display-p3 0.30 0.50 0.93)"},
+		{FormatHex, 3, "#3b82f6"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%d", tt.format, tt.precision), func(t *testing.T) {
+			t.Parallel()
+
+			if got := c.ToCSSPrecision(tt.format, tt.precision); got != tt.want {
+				t.Errorf("ToCSSPrecision(%q, %d) = %q, want %q", tt.format, tt.precision, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"oklch", FormatOKLCH, false},
+		{"hex", FormatHex, false},
+		{"p3", FormatP3, false},
+		{"display-p3", FormatP3, false},
+		{"rec2020", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseFormat(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseFormat(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseFormat(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
 func TestColor_ToOKLCH(t *testing.T) {
 	t.Parallel()
 
@@ -588,6 +645,37 @@ func TestContrastRatio(t *testing.T) {
 	}
 }
 
+func TestComposite(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		c         string
+		backdrop  string
+		wantHex   string
+		wantAlpha float64
+	}{
+		{"opaque color is unchanged", "#3b82f6", "#000000", "#3b82f6", 1},
+		{"half black over white", "#00000080", "#ffffff", "#7f7f7f", 1},
+		{"transparent shows backdrop", "transparent", "#ff0000", "#ff0000", 1},
+		{"translucent over translucent", "#ff000080", "#0000ff80", "#aa0055c0", 0.75},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := Composite(MustParse(tt.c), MustParse(tt.backdrop))
+			if got.Hex() != tt.wantHex {
+				t.Errorf("Composite = %s, want %s", got.Hex(), tt.wantHex)
+			}
+			if math.Abs(got.Alpha()-tt.wantAlpha) > 0.01 {
+				t.Errorf("alpha = %v, want %v", got.Alpha(), tt.wantAlpha)
+			}
+		})
+	}
+}
+
 func TestContrastRatio_Symmetric(t *testing.T) {
 	t.Parallel()
 
@@ -4,6 +4,8 @@ package colors
 
 import (
 	"math"
+
+	"github.com/lucasb-eyer/go-colorful"
 )
 
 // WCAG contrast ratio thresholds
@@ -28,6 +30,23 @@ func RelativeLuminance(c Color) float64 {
 	return 0.2126*r + 0.7152*g + 0.0722*b
 }
 
+// Composite paints c over backdrop with source-over blending in sRGB, as
+// browsers do, and returns the color that shows. Over an opaque backdrop
+// the result is opaque. Contrast is only meaningful between opaque
+// colors, so translucent ones are composited before measuring.
+func Composite(c, backdrop Color) Color {
+	a, ab := c.Alpha(), backdrop.Alpha()
+	alpha := a + ab*(1-a)
+	if alpha == 0 {
+		return c.with(colorful.Color{})
+	}
+	blend := func(v, bv float64) float64 {
+		return (v*a + bv*ab*(1-a)) / alpha
+	}
+	out := c.with(colorful.Color{R: blend(c.R, backdrop.R), G: blend(c.G, backdrop.G), B: blend(c.B, backdrop.B)})
+	return out.WithAlpha(alpha)
+}
+
 // ContrastRatio calculates the WCAG contrast ratio between two colors
 // Returns a value between 1.0 (identical) and 21.0 (black/white)
 // Formula: (L1 + 0.05) / (L2 + 0.05) where L1 is the lighter color
@@ -0,0 +1,124 @@
+// tokenctl/pkg/colors/gamut.go
+
+package colors
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+// Gamuts a color can be checked against or mapped into, named as in CSS
+const (
+	GamutSRGB      = "srgb"
+	GamutDisplayP3 = "display-p3"
+)
+
+// ParseGamut validates a gamut name; "p3" is accepted for display-p3
+func ParseGamut(name string) (string, error) {
+	switch name {
+	case GamutSRGB:
+		return GamutSRGB, nil
+	case GamutDisplayP3, "p3":
+		return GamutDisplayP3, nil
+	default:
+		return "", fmt.Errorf("unknown gamut: %s (valid: %s, %s)", name, GamutSRGB, GamutDisplayP3)
+	}
+}
+
+// GamutName returns a gamut's display name for messages
+func GamutName(gamut string) string {
+	if gamut == GamutDisplayP3 {
+		return "Display-P3"
+	}
+	return "sRGB"
+}
+
+const (
+	// gamutEpsilon absorbs round-trip error: an sRGB color converted to
+	// OKLCH and back lands a few 1e-6 outside [0, 1]
+	gamutEpsilon = 1e-4
+
+	// gamutJND is the OKLab distance below which a clipped color is
+	// indistinguishable from the one it replaces (CSS Color 4 §13.2)
+	gamutJND = 0.02
+)
+
+// InGamut reports whether c can be displayed in gamut without clipping
+func InGamut(c Color, gamut string) bool {
+	r, g, b := c.R, c.G, c.B
+	if gamut == GamutDisplayP3 {
+		r, g, b = toP3(c)
+	}
+	return inUnitRange(r) && inUnitRange(g) && inUnitRange(b)
+}
+
+func inUnitRange(v float64) bool {
+	return v >= -gamutEpsilon && v <= 1+gamutEpsilon
+}
+
+// MapToGamut brings c into gamut with the CSS Color 4 gamut mapping
+// algorithm: OKLCH chroma is reduced, keeping lightness and hue, until
+// clipping the remainder changes the color by less than a just-noticeable
+// difference. Plain clipping instead shifts hue and lightness visibly.
+// Colors already in gamut are returned unchanged. The result keeps c's
+// original format.
+func MapToGamut(c Color, gamut string) Color {
+	if InGamut(c, gamut) {
+		return c
+	}
+	l, ch, h := c.OkLch()
+	if l >= 1-gamutEpsilon {
+		return c.with(colorful.Color{R: 1, G: 1, B: 1})
+	}
+	if l <= 0 {
+		return c.with(colorful.Color{})
+	}
+
+	current := FromOkLch(l, ch, h)
+	clipped := clipToGamut(current, gamut)
+	if deltaEOK(clipped, current) >= gamutJND {
+		lo, hi, loInGamut := 0.0, ch, true
+		for hi-lo > gamutEpsilon {
+			chroma := (lo + hi) / 2
+			current = FromOkLch(l, chroma, h)
+			if loInGamut && InGamut(current, gamut) {
+				lo = chroma
+				continue
+			}
+			clipped = clipToGamut(current, gamut)
+			e := deltaEOK(clipped, current)
+			if e < gamutJND {
+				if gamutJND-e < gamutEpsilon {
+					break
+				}
+				loInGamut = false
+				lo = chroma
+			} else {
+				hi = chroma
+			}
+		}
+	}
+	return c.with(clipped.Color)
+}
+
+// clipToGamut clamps each channel of c into gamut
+func clipToGamut(c Color, gamut string) Color {
+	if gamut != GamutDisplayP3 {
+		return c.Clamped()
+	}
+	r, g, b := toP3(c)
+	return c.with(fromP3(clamp01(r), clamp01(g), clamp01(b)))
+}
+
+// deltaEOK is the Euclidean distance between two colors in OKLab
+func deltaEOK(a, b Color) float64 {
+	l1, a1, b1 := a.OkLab()
+	l2, a2, b2 := b.OkLab()
+	return math.Sqrt((l1-l2)*(l1-l2) + (a1-a2)*(a1-a2) + (b1-b2)*(b1-b2))
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
@@ -0,0 +1,102 @@
+// tokenctl/pkg/colors/gamut_test.go
+
+package colors
+
+import (
+	"math"
+	"testing"
+)
+
+func TestInGamut(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		input string
+		srgb  bool
+		p3    bool
+	}{
+		{"#ff0000", true, true},
+		{"#ffffff", true, true},
+		{"#000000", true, true},
+		{"oklch(62.80% 0.2577 29.23)", true, true},  // sRGB red
+		{"oklch(64.86% 0.29 28.96)", false, true},   // just inside P3 red
+		{"oklch(49.12% 0.309 275.75)", false, true}, // DaisyUI primary
+		{"oklch(70% 0.35 150)", false, false},
+		{"color(srgb 1.5 0 0)", false, false},
+	}
+	for _, tt := range tests {
+		c := MustParse(tt.input)
+		if got := InGamut(c, GamutSRGB); got != tt.srgb {
+			t.Errorf("InGamut(%s, srgb) = %v, want %v", tt.input, got, tt.srgb)
+		}
+		if got := InGamut(c, GamutDisplayP3); got != tt.p3 {
+			t.Errorf("InGamut(%s, display-p3) = %v, want %v", tt.input, got, tt.p3)
+		}
+	}
+}
+
+func TestMapToGamut(t *testing.T) {
+	t.Parallel()
+	inputs := []string{
+		"oklch(70% 0.35 150)",
+		"oklch(64.86% 0.29 28.96)",
+		"oklch(50% 0.4 300)",
+		"oklch(95% 0.3 100)",
+		"oklch(20% 0.3 250)",
+	}
+	for _, gamut := range []string{GamutSRGB, GamutDisplayP3} {
+		for _, input := range inputs {
+			c := MustParse(input)
+			got := MapToGamut(c, gamut)
+			if !InGamut(got, gamut) {
+				t.Errorf("MapToGamut(%s, %s) = %s, still out of gamut", input, gamut, got.ToOKLCH())
+			}
+			if got.OriginalFormat() != FormatOKLCH {
+				t.Errorf("MapToGamut(%s, %s) format = %s, want oklch", input, gamut, got.OriginalFormat())
+			}
+			l, ch, h := c.OkLch()
+			_, gch, _ := got.OkLch()
+			// Chroma gives way; lightness and hue hold to within the
+			// final clip, which is below a just-noticeable difference
+			if gch > ch || deltaEOK(got, FromOkLch(l, gch, h)) > gamutJND {
+				t.Errorf("MapToGamut(%s, %s) = %s, drifted from lightness or hue", input, gamut, got.ToOKLCH())
+			}
+		}
+	}
+}
+
+func TestMapToGamut_BeatsClipping(t *testing.T) {
+	t.Parallel()
+	// Clipping oklch(70% 0.35 150) to sRGB lands 4.5 points lighter and
+	// 7.5 degrees off in hue; chroma reduction keeps both close
+	c := MustParse("oklch(70% 0.35 150)")
+	clippedL, _, clippedH := c.Clamped().OkLch()
+	mappedL, _, mappedH := MapToGamut(c, GamutSRGB).OkLch()
+	if math.Abs(mappedL-0.7) >= math.Abs(clippedL-0.7) || math.Abs(mappedH-150) >= math.Abs(clippedH-150) {
+		t.Errorf("mapped L=%.4f H=%.2f, clipped L=%.4f H=%.2f", mappedL, mappedH, clippedL, clippedH)
+	}
+}
+
+func TestMapToGamut_Unchanged(t *testing.T) {
+	t.Parallel()
+	for _, input := range []string{"#3b82f6", "#ffffff", "#000000"} {
+		c := MustParse(input)
+		if got := MapToGamut(c, GamutSRGB); got.Hex() != c.Hex() {
+			t.Errorf("MapToGamut(%s) = %s, want unchanged", input, got.Hex())
+		}
+	}
+	if got := MapToGamut(MustParse("oklch(100% 0.2 120)"), GamutSRGB).Hex(); got != "#ffffff" {
+		t.Errorf("MapToGamut(L=100%%) = %s, want #ffffff", got)
+	}
+}
+
+func TestParseGamut(t *testing.T) {
+	t.Parallel()
+	for input, want := range map[string]string{"srgb": GamutSRGB, "display-p3": GamutDisplayP3, "p3": GamutDisplayP3} {
+		if got, err := ParseGamut(input); err != nil || got != want {
+			t.Errorf("ParseGamut(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseGamut("rec2020"); err == nil {
+		t.Error("ParseGamut(rec2020) expected error")
+	}
+}
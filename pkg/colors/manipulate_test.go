@@ -0,0 +1,185 @@
+// tokenctl/pkg/colors/manipulate_test.go
+
+package colors
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMix(t *testing.T) {
+	t.Parallel()
+
+	red := MustParse("#ff0000")
+	blue := MustParse("#0000ff")
+
+	tests := []struct {
+		name    string
+		a, b    Color
+		weight  float64
+		space   string
+		wantHex string
+		wantErr bool
+	}{
+		{name: "srgb midpoint", a: red, b: blue, weight: 0.5, space: SpaceSRGB, wantHex: "#800080"},
+		{name: "weight 1 returns a", a: red, b: blue, weight: 1, space: SpaceOKLCH, wantHex: "#ff0000"},
+		{name: "weight 0 returns b", a: red, b: blue, weight: 0, space: SpaceOKLCH, wantHex: "#0000ff"},
+		{name: "default space is oklch", a: White(), b: Black(), weight: 1, wantHex: "#ffffff"},
+		{name: "weight out of range", a: red, b: blue, weight: 1.5, wantErr: true},
+		{name: "unknown space", a: red, b: blue, weight: 0.5, space: "hsl", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := Mix(tt.a, tt.b, tt.weight, tt.space)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Hex() != tt.wantHex {
+				t.Errorf("Mix() = %s, want %s", got.Hex(), tt.wantHex)
+			}
+			if got.OriginalFormat() != tt.a.OriginalFormat() {
+				t.Errorf("format = %s, want %s", got.OriginalFormat(), tt.a.OriginalFormat())
+			}
+		})
+	}
+}
+
+func TestMix_AchromaticKeepsHue(t *testing.T) {
+	t.Parallel()
+
+	blue := FromOkLch(0.6, 0.15, 250)
+	got, err := Mix(blue, White(), 0.5, SpaceOKLCH)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, h := got.OkLch()
+	if math.Abs(h-250) > 1 {
+		t.Errorf("hue = %.2f, want ~250 (white's hue must not be interpolated)", h)
+	}
+}
+
+func TestSaturateAndRotateHue(t *testing.T) {
+	t.Parallel()
+
+	base := FromOkLch(0.6, 0.1, 40)
+
+	_, c, _ := Saturate(base, 0.5).OkLch()
+	if math.Abs(c-0.15) > 0.002 {
+		t.Errorf("Saturate chroma = %.3f, want 0.15", c)
+	}
+
+	_, c, _ = Saturate(base, -1).OkLch()
+	if c > 0.002 {
+		t.Errorf("full desaturation chroma = %.3f, want 0", c)
+	}
+
+	_, _, h := RotateHue(base, -60).OkLch()
+	if math.Abs(h-340) > 0.5 {
+		t.Errorf("RotateHue(-60) hue = %.2f, want 340", h)
+	}
+
+	_, _, h = Complement(base).OkLch()
+	if math.Abs(h-220) > 0.5 {
+		t.Errorf("Complement hue = %.2f, want 220", h)
+	}
+}
+
+func TestManipulation_KeepsAlpha(t *testing.T) {
+	t.Parallel()
+
+	c := MustParse("#3b82f680")
+	for name, got := range map[string]Color{
+		"Saturate":   Saturate(c, 0.2),
+		"RotateHue":  RotateHue(c, 30),
+		"Complement": Complement(c),
+		"Clamped":    c.Clamped(),
+	} {
+		if math.Abs(got.Alpha()-c.Alpha()) > 1e-9 {
+			t.Errorf("%s alpha = %v, want %v", name, got.Alpha(), c.Alpha())
+		}
+	}
+}
+
+func TestMix_Alpha(t *testing.T) {
+	t.Parallel()
+
+	red := MustParse("#ff0000")
+	transparent := MustParse("transparent")
+
+	// Premultiplied: fading red toward transparent keeps it red rather
+	// than darkening it toward transparent's black channels
+	for _, space := range []string{SpaceSRGB, SpaceOKLCH} {
+		got, err := Mix(red, transparent, 0.5, space)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(got.Alpha()-0.5) > 1e-9 {
+			t.Errorf("%s: alpha = %v, want 0.5", space, got.Alpha())
+		}
+		if deltaEOK(got, red) > 0.01 {
+			t.Errorf("%s: mixed color %s, want red at half opacity", space, got.Hex())
+		}
+	}
+
+	// Two translucent colors interpolate alpha linearly
+	got, err := Mix(MustParse("#00000040"), MustParse("#000000c0"), 0.25, SpaceSRGB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 0.25*64.0/255 + 0.75*192.0/255; math.Abs(got.Alpha()-want) > 1e-9 {
+		t.Errorf("alpha = %v, want %v", got.Alpha(), want)
+	}
+}
+
+func TestColor_TranslucentOutput(t *testing.T) {
+	t.Parallel()
+
+	c := MustParse("#3b82f6").WithAlpha(0.5)
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"Hex", c.Hex(), "#3b82f680"},
+		{"ToRGB", c.ToRGB(), "rgba(59, 130, 246, 0.5)"},
+		{"ToHSL", c.ToHSL(), "hsla(217.2, 91.2%, 59.8%, 0.5)"},
+		{"ToOKLCH", c.ToOKLCH(), "oklch(62.31% 0.188 259.82 / 0.5)"},
+		{"ToOriginalFormat", MustParse("#3b82f680").ToOriginalFormat(), "#3b82f680"},
+		{"opaque Hex", c.WithAlpha(1).Hex(), "#3b82f6"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestColor_ToCSSWithAlpha(t *testing.T) {
+	t.Parallel()
+
+	c := MustParse("#3b82f6")
+	tests := []struct {
+		format string
+		alpha  float64
+		want   string
+	}{
+		{FormatHex, 0.5, "#3b82f680"},
+		{FormatHex, 1, "#3b82f6"},
+		{FormatRGB, 0.25, "rgba(59, 130, 246, 0.25)"},
+		{FormatHex, 0, "#3b82f600"},
+	}
+
+	for _, tt := range tests {
+		if got := c.ToCSSWithAlpha(tt.format, tt.alpha); got != tt.want {
+			t.Errorf("ToCSSWithAlpha(%s, %g) = %q, want %q", tt.format, tt.alpha, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+// tokenctl/pkg/colors/spaces.go
+
+package colors
+
+import (
+	"math"
+
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+// Conversions between sRGB, which Color stores, and the other CSS Color 4
+// spaces. Matrices are the ones CSS Color 4 publishes in its sample code,
+// so values agree with browsers. OKLab and OKLCH go through go-colorful,
+// as they always have.
+
+var (
+	// Linear sRGB <-> CIE XYZ, D65
+	linearSRGBToXYZ = [3][3]float64{
+		{506752.0 / 1228815, 87881.0 / 245763, 12673.0 / 70218},
+		{87098.0 / 409605, 175762.0 / 245763, 12673.0 / 175545},
+		{7918.0 / 409605, 87881.0 / 737289, 1001167.0 / 1053270},
+	}
+	xyzToLinearSRGB = [3][3]float64{
+		{12831.0 / 3959, -329.0 / 214, -1974.0 / 3959},
+		{-851781.0 / 878810, 1648619.0 / 878810, 36519.0 / 878810},
+		{705.0 / 12673, -2585.0 / 12673, 705.0 / 667},
+	}
+
+	// Bradford chromatic adaptation between the D65 and D50 white points
+	d65ToD50 = [3][3]float64{
+		{1.0479297925449969, 0.022946870601609652, -0.05019226628920524},
+		{0.02962780877005599, 0.9904344267538799, -0.017073799063418826},
+		{-0.009243040646204504, 0.015055191490298152, 0.7518742814281371},
+	}
+	d50ToD65 = [3][3]float64{
+		{0.955473421488075, -0.02309845494876471, 0.06325924320057072},
+		{-0.0283697093338637, 1.0099953980813041, 0.021041441191917323},
+		{0.012314014864481998, -0.020507649298898964, 1.330365926242124},
+	}
+
+	// Linear sRGB <-> linear Display-P3, both D65
+	linearSRGBToP3 = [3][3]float64{
+		{0.8224619687143616, 0.17753803128563838, 0},
+		{0.033194198850966, 0.966805801149034, 0},
+		{0.017082630593412, 0.07239744066396343, 0.9105199287426245},
+	}
+	linearP3ToSRGB = [3][3]float64{
+		{1.2249401762805598, -0.22494017628055996, 0},
+		{-0.04205695470968816, 1.0420569547096882, 0},
+		{-0.019637554590334432, -0.07863604555063189, 1.0982736001409663},
+	}
+)
+
+// D50 reference white, as CSS lab() and lch() use
+var d50White = [3]float64{0.3457 / 0.3585, 1, (1 - 0.3457 - 0.3585) / 0.3585}
+
+// CIE Lab constants
+const (
+	labKappa   = 24389.0 / 27
+	labEpsilon = 216.0 / 24389
+)
+
+// linearize and delinearize apply the sRGB transfer function, which
+// Display-P3 shares. Below zero they stay on the linear segment, as
+// go-colorful's conversions do, so out-of-gamut colors round-trip.
+func linearize(v float64) float64 {
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+func delinearize(v float64) float64 {
+	if v <= 0.0031308 {
+		return v * 12.92
+	}
+	return 1.055*math.Pow(v, 1/2.4) - 0.055
+}
+
+func mulMatrix(m [3][3]float64, a, b, c float64) (float64, float64, float64) {
+	return m[0][0]*a + m[0][1]*b + m[0][2]*c,
+		m[1][0]*a + m[1][1]*b + m[1][2]*c,
+		m[2][0]*a + m[2][1]*b + m[2][2]*c
+}
+
+// fromLinearSRGB encodes linear sRGB channels
+func fromLinearSRGB(r, g, b float64) colorful.Color {
+	return colorful.Color{R: delinearize(r), G: delinearize(g), B: delinearize(b)}
+}
+
+// linearSRGB returns c's linear-light sRGB channels
+func linearSRGB(c Color) (r, g, b float64) {
+	return linearize(c.R), linearize(c.G), linearize(c.B)
+}
+
+// toP3 returns c's gamma-encoded Display-P3 channels, which fall outside
+// [0, 1] when c is out of that gamut
+func toP3(c Color) (r, g, b float64) {
+	r, g, b = linearSRGB(c)
+	r, g, b = mulMatrix(linearSRGBToP3, r, g, b)
+	return delinearize(r), delinearize(g), delinearize(b)
+}
+
+// fromP3 converts gamma-encoded Display-P3 channels
+func fromP3(r, g, b float64) colorful.Color {
+	return fromLinearSRGB(mulMatrix(linearP3ToSRGB, linearize(r), linearize(g), linearize(b)))
+}
+
+// toXYZ returns c in CIE XYZ, D65 or D50
+func toXYZ(c Color, d50 bool) (x, y, z float64) {
+	r, g, b := linearSRGB(c)
+	x, y, z = mulMatrix(linearSRGBToXYZ, r, g, b)
+	if d50 {
+		x, y, z = mulMatrix(d65ToD50, x, y, z)
+	}
+	return x, y, z
+}
+
+// fromXYZ converts CIE XYZ, D65 or D50
+func fromXYZ(x, y, z float64, d50 bool) colorful.Color {
+	if d50 {
+		x, y, z = mulMatrix(d50ToD65, x, y, z)
+	}
+	return fromLinearSRGB(mulMatrix(xyzToLinearSRGB, x, y, z))
+}
+
+// toLab returns c in CIE Lab (D50), L in 0-100
+func toLab(c Color) (l, a, b float64) {
+	x, y, z := toXYZ(c, true)
+	f := func(v float64) float64 {
+		if v > labEpsilon {
+			return math.Cbrt(v)
+		}
+		return (labKappa*v + 16) / 116
+	}
+	fx, fy, fz := f(x/d50White[0]), f(y/d50White[1]), f(z/d50White[2])
+	return 116*fy - 16, 500 * (fx - fy), 200 * (fy - fz)
+}
+
+// fromLab converts CIE Lab (D50)
+func fromLab(l, a, b float64) colorful.Color {
+	fy := (l + 16) / 116
+	fx := a/500 + fy
+	fz := fy - b/200
+	inv := func(f float64) float64 {
+		if f3 := f * f * f; f3 > labEpsilon {
+			return f3
+		}
+		return (116*f - 16) / labKappa
+	}
+	y := l / labKappa
+	if l > labKappa*labEpsilon {
+		y = fy * fy * fy
+	}
+	return fromXYZ(inv(fx)*d50White[0], y*d50White[1], inv(fz)*d50White[2], true)
+}
+
+// toPolar and fromPolar convert between a/b and chroma/hue
+func toPolar(a, b float64) (chroma, hue float64) {
+	return math.Hypot(a, b), normalizeHue(math.Atan2(b, a) * 180 / math.Pi)
+}
+
+func fromPolar(chroma, hue float64) (a, b float64) {
+	rad := hue * math.Pi / 180
+	return chroma * math.Cos(rad), chroma * math.Sin(rad)
+}
+
+// toHWB returns c's hue, whiteness and blackness (0-1)
+func toHWB(c Color) (h, w, b float64) {
+	h, _, _ = c.Hsv()
+	return h, math.Min(c.R, math.Min(c.G, c.B)), 1 - math.Max(c.R, math.Max(c.G, c.B))
+}
+
+// fromHWB converts hue, whiteness and blackness (0-1)
+func fromHWB(h, w, b float64) colorful.Color {
+	if w+b >= 1 {
+		gray := w / (w + b)
+		return colorful.Color{R: gray, G: gray, B: gray}
+	}
+	pure := colorful.Hsl(h, 1, 0.5)
+	scale := 1 - w - b
+	return colorful.Color{R: pure.R*scale + w, G: pure.G*scale + w, B: pure.B*scale + w}
+}
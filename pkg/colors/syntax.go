@@ -0,0 +1,499 @@
+// tokenctl/pkg/colors/syntax.go
+
+package colors
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+// ErrUnresolvedOrigin is returned for a relative color whose origin only
+// the browser knows, such as oklch(from var(--brand) l c h). The syntax
+// is valid; the color cannot be computed at build time.
+var ErrUnresolvedOrigin = errors.New("relative color origin is resolved in the browser")
+
+// colorFunction is a color function split into its parts:
+// oklch(from #3b82f6 calc(l - 0.1) c h / 50%)
+type colorFunction struct {
+	name   string   // lowercased function name
+	space  string   // color space of color(), lowercased
+	from   string   // origin of a relative color, empty otherwise
+	args   []string // channel arguments
+	alpha  string   // alpha argument, empty when omitted
+	legacy bool     // comma-separated syntax
+}
+
+// splitFunction splits a color function at its top-level separators,
+// keeping nested functions such as calc() and var() whole
+func splitFunction(input string) (colorFunction, error) {
+	open := strings.IndexByte(input, '(')
+	if open < 0 || !strings.HasSuffix(input, ")") {
+		return colorFunction{}, fmt.Errorf("unrecognized color format: %s", input)
+	}
+	fn := colorFunction{name: strings.ToLower(strings.TrimSpace(input[:open]))}
+
+	var tokens []string
+	var current strings.Builder
+	depth := 0
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+	for _, r := range input[open+1 : len(input)-1] {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+			if depth < 0 {
+				return colorFunction{}, fmt.Errorf("unbalanced parentheses in %s", input)
+			}
+		case depth == 0 && unicode.IsSpace(r):
+			flush()
+			continue
+		case depth == 0 && (r == ',' || r == '/'):
+			flush()
+			tokens = append(tokens, string(r))
+			continue
+		}
+		current.WriteRune(r)
+	}
+	flush()
+	if depth != 0 {
+		return colorFunction{}, fmt.Errorf("unbalanced parentheses in %s", input)
+	}
+
+	// Legacy syntax: rgba(255, 0, 0, 0.5)
+	if len(tokens) > 1 && tokens[1] == "," {
+		fn.legacy = true
+		for i, tok := range tokens {
+			if (i%2 == 1) != (tok == ",") {
+				return colorFunction{}, fmt.Errorf("invalid color syntax: %s", input)
+			}
+			if i%2 == 0 {
+				fn.args = append(fn.args, tok)
+			}
+		}
+		if len(fn.args) == 4 {
+			fn.alpha, fn.args = fn.args[3], fn.args[:3]
+		}
+		return fn, nil
+	}
+
+	if len(tokens) > 0 && strings.EqualFold(tokens[0], "from") {
+		if len(tokens) < 2 {
+			return colorFunction{}, fmt.Errorf("relative color is missing its origin: %s", input)
+		}
+		fn.from, tokens = tokens[1], tokens[2:]
+	}
+	if fn.name == "color" {
+		if len(tokens) == 0 {
+			return colorFunction{}, fmt.Errorf("color() is missing a color space: %s", input)
+		}
+		fn.space, tokens = strings.ToLower(tokens[0]), tokens[1:]
+	}
+	for i, tok := range tokens {
+		switch tok {
+		case ",":
+			return colorFunction{}, fmt.Errorf("invalid color syntax: %s", input)
+		case "/":
+			if i != len(tokens)-2 {
+				return colorFunction{}, fmt.Errorf("invalid alpha in %s", input)
+			}
+			fn.alpha = tokens[i+1]
+			return fn, nil
+		}
+		fn.args = append(fn.args, tok)
+	}
+	return fn, nil
+}
+
+// channelScale says how a channel reads: the value 100% stands for, and
+// whether the channel is a hue angle
+type channelScale struct {
+	percent float64
+	hue     bool
+}
+
+// colorSpec describes one color function: its output format, channel
+// names and scales, how to build a color from channels and how to read
+// an origin's channels for relative syntax
+type colorSpec struct {
+	format   string
+	names    [3]string
+	scales   [3]channelScale
+	bounds   [3]float64 // upper bound a channel clamps to, from 0; 0 for none
+	legacy   bool       // accepts comma syntax
+	build    func(a, b, c float64) colorful.Color
+	channels func(Color) (a, b, c float64)
+}
+
+var (
+	hueScale  = channelScale{hue: true}
+	unitScale = channelScale{percent: 1}
+)
+
+// colorSpecs maps function names, and color() spaces as "color <space>",
+// to their specs
+var colorSpecs = map[string]colorSpec{
+	"rgb": {
+		format: FormatRGB, names: [3]string{"r", "g", "b"}, legacy: true,
+		scales: [3]channelScale{{percent: 255}, {percent: 255}, {percent: 255}},
+		// Out-of-range channels clamp, as CSS does when it parses rgb()
+		bounds: [3]float64{255, 255, 255},
+		build: func(r, g, b float64) colorful.Color {
+			return colorful.Color{R: r / 255, G: g / 255, B: b / 255}
+		},
+		channels: func(c Color) (float64, float64, float64) { return c.R * 255, c.G * 255, c.B * 255 },
+	},
+	"hsl": {
+		format: FormatHSL, names: [3]string{"h", "s", "l"}, legacy: true,
+		scales: [3]channelScale{hueScale, {percent: 100}, {percent: 100}},
+		bounds: [3]float64{0, 100, 100},
+		build:  func(h, s, l float64) colorful.Color { return colorful.Hsl(h, s/100, l/100) },
+		channels: func(c Color) (float64, float64, float64) {
+			h, s, l := c.Hsl()
+			return h, s * 100, l * 100
+		},
+	},
+	"hwb": {
+		format: FormatHWB, names: [3]string{"h", "w", "b"},
+		scales: [3]channelScale{hueScale, {percent: 100}, {percent: 100}},
+		build:  func(h, w, b float64) colorful.Color { return fromHWB(h, w/100, b/100) },
+		channels: func(c Color) (float64, float64, float64) {
+			h, w, b := toHWB(c)
+			return h, w * 100, b * 100
+		},
+	},
+	"lab": {
+		format: FormatLab, names: [3]string{"l", "a", "b"},
+		scales:   [3]channelScale{{percent: 100}, {percent: 125}, {percent: 125}},
+		build:    fromLab,
+		channels: toLab,
+	},
+	"lch": {
+		format: FormatLCH, names: [3]string{"l", "c", "h"},
+		scales: [3]channelScale{{percent: 100}, {percent: 150}, hueScale},
+		build: func(l, c, h float64) colorful.Color {
+			a, b := fromPolar(c, h)
+			return fromLab(l, a, b)
+		},
+		channels: func(col Color) (float64, float64, float64) {
+			l, a, b := toLab(col)
+			c, h := toPolar(a, b)
+			return l, c, h
+		},
+	},
+	"oklab": {
+		format: FormatOKLab, names: [3]string{"l", "a", "b"},
+		scales:   [3]channelScale{{percent: 1}, {percent: 0.4}, {percent: 0.4}},
+		build:    colorful.OkLab,
+		channels: func(c Color) (float64, float64, float64) { return c.OkLab() },
+	},
+	"oklch": {
+		format: FormatOKLCH, names: [3]string{"l", "c", "h"},
+		scales:   [3]channelScale{{percent: 1}, {percent: 0.4}, hueScale},
+		build:    colorful.OkLch,
+		channels: func(c Color) (float64, float64, float64) { return c.OkLch() },
+	},
+	"color srgb": {
+		format: FormatSRGB, names: [3]string{"r", "g", "b"},
+		scales:   [3]channelScale{unitScale, unitScale, unitScale},
+		build:    func(r, g, b float64) colorful.Color { return colorful.Color{R: r, G: g, B: b} },
+		channels: func(c Color) (float64, float64, float64) { return c.R, c.G, c.B },
+	},
+	"color srgb-linear": {
+		format: FormatSRGBLinear, names: [3]string{"r", "g", "b"},
+		scales:   [3]channelScale{unitScale, unitScale, unitScale},
+		build:    fromLinearSRGB,
+		channels: linearSRGB,
+	},
+	"color display-p3": {
+		format: FormatP3, names: [3]string{"r", "g", "b"},
+		scales:   [3]channelScale{unitScale, unitScale, unitScale},
+		build:    fromP3,
+		channels: toP3,
+	},
+	"color xyz-d65": {
+		format: FormatXYZ, names: [3]string{"x", "y", "z"},
+		scales:   [3]channelScale{unitScale, unitScale, unitScale},
+		build:    func(x, y, z float64) colorful.Color { return fromXYZ(x, y, z, false) },
+		channels: func(c Color) (float64, float64, float64) { return toXYZ(c, false) },
+	},
+	"color xyz-d50": {
+		format: FormatXYZD50, names: [3]string{"x", "y", "z"},
+		scales:   [3]channelScale{unitScale, unitScale, unitScale},
+		build:    func(x, y, z float64) colorful.Color { return fromXYZ(x, y, z, true) },
+		channels: func(c Color) (float64, float64, float64) { return toXYZ(c, true) },
+	},
+}
+
+// parseColorFunction parses rgb(), hsl(), hwb(), lab(), lch(), oklab(),
+// oklch() and color(), in legacy, modern and relative syntax
+func parseColorFunction(input string) (Color, error) {
+	fn, err := splitFunction(input)
+	if err != nil {
+		return Color{}, err
+	}
+
+	key := fn.name
+	switch {
+	case key == "rgba" || key == "hsla":
+		key = strings.TrimSuffix(key, "a")
+	case key == "color" && fn.space == "xyz":
+		key = "color xyz-d65"
+	case key == "color":
+		key = "color " + fn.space
+	}
+	spec, ok := colorSpecs[key]
+	if !ok {
+		if fn.name == "color" {
+			return Color{}, fmt.Errorf("unsupported color space %q in %s", fn.space, input)
+		}
+		return Color{}, fmt.Errorf("unrecognized color format: %s", input)
+	}
+	if fn.legacy && !spec.legacy {
+		return Color{}, fmt.Errorf("%s() does not accept commas: %s", fn.name, input)
+	}
+	if len(fn.args) != 3 {
+		return Color{}, fmt.Errorf("%s() takes 3 channels, got %d: %s", fn.name, len(fn.args), input)
+	}
+
+	// Relative syntax: channel keywords read the origin in this space
+	var env map[string]float64
+	unresolved := false
+	if fn.from != "" {
+		origin, err := Parse(fn.from)
+		if err != nil {
+			if !isRuntimeColor(fn.from) {
+				return Color{}, fmt.Errorf("relative color origin: %w", err)
+			}
+			unresolved = true
+		}
+		a, b, c := spec.channels(origin)
+		env = map[string]float64{
+			spec.names[0]: a, spec.names[1]: b, spec.names[2]: c,
+			"alpha": origin.Alpha(),
+		}
+	}
+
+	var ch [3]float64
+	var none [3]bool
+	for i, arg := range fn.args {
+		v, err := evalChannel(arg, spec.scales[i], env)
+		if err != nil {
+			return Color{}, fmt.Errorf("invalid %s channel in %s: %w", spec.names[i], input, err)
+		}
+		if bound := spec.bounds[i]; bound > 0 {
+			v = math.Max(0, math.Min(bound, v))
+		}
+		ch[i], none[i] = v, strings.EqualFold(arg, "none")
+	}
+	alpha := 1.0
+	if env != nil {
+		alpha = env["alpha"]
+	}
+	if fn.alpha != "" {
+		alpha, err = evalChannel(fn.alpha, unitScale, env)
+		if err != nil {
+			return Color{}, fmt.Errorf("invalid alpha in %s: %w", input, err)
+		}
+	}
+	if unresolved {
+		return Color{}, fmt.Errorf("%w: %s", ErrUnresolvedOrigin, fn.from)
+	}
+
+	c := Color{Color: spec.build(ch[0], ch[1], ch[2]), originalFormat: spec.format}
+	// A relative color's channels are computed, not written
+	if fn.from == "" {
+		c.src = source{color: c.Color, format: spec.format, ch: ch, none: none, modern: !fn.legacy}
+	}
+	return c.WithAlpha(alpha), nil
+}
+
+// isRuntimeColor reports whether s is a color only the browser can
+// resolve
+func isRuntimeColor(s string) bool {
+	lower := strings.ToLower(s)
+	return strings.HasPrefix(lower, "var(") || lower == "currentcolor"
+}
+
+// evalChannel reads one channel: a number, a percentage of scale, an
+// angle for hues, none (zero), or in relative syntax a channel keyword
+// or a calc() over keywords
+func evalChannel(tok string, scale channelScale, env map[string]float64) (float64, error) {
+	lower := strings.ToLower(tok)
+	if lower == "none" {
+		return 0, nil
+	}
+	if v, ok := env[lower]; ok {
+		return v, nil
+	}
+	if strings.HasPrefix(lower, "calc(") {
+		return evalCalc(lower, env)
+	}
+	if pct, ok := strings.CutSuffix(lower, "%"); ok && !scale.hue {
+		v, err := strconv.ParseFloat(pct, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid percentage %q", tok)
+		}
+		return v * scale.percent / 100, nil
+	}
+	if scale.hue {
+		for _, unit := range []struct {
+			suffix string
+			deg    float64
+		}{{"deg", 1}, {"grad", 0.9}, {"rad", 180 / math.Pi}, {"turn", 360}} {
+			if num, ok := strings.CutSuffix(lower, unit.suffix); ok {
+				v, err := strconv.ParseFloat(num, 64)
+				if err != nil {
+					return 0, fmt.Errorf("invalid angle %q", tok)
+				}
+				return v * unit.deg, nil
+			}
+		}
+	}
+	v, err := strconv.ParseFloat(lower, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", tok)
+	}
+	return v, nil
+}
+
+// evalCalc evaluates calc() over numbers and channel keywords with
+// + - * / and parentheses
+func evalCalc(expr string, env map[string]float64) (float64, error) {
+	p := calcParser{env: env}
+	if err := p.tokenize(expr); err != nil {
+		return 0, err
+	}
+	v, err := p.sum()
+	if err != nil {
+		return 0, err
+	}
+	if p.pos != len(p.tokens) {
+		return 0, fmt.Errorf("unexpected %q in %s", p.tokens[p.pos], expr)
+	}
+	return v, nil
+}
+
+type calcParser struct {
+	tokens []string
+	pos    int
+	env    map[string]float64
+}
+
+func (p *calcParser) tokenize(expr string) error {
+	for i := 0; i < len(expr); {
+		ch := expr[i]
+		switch {
+		case ch == ' ' || ch == '\t':
+			i++
+		case strings.IndexByte("+-*/()", ch) >= 0:
+			p.tokens = append(p.tokens, string(ch))
+			i++
+		case ch == '.' || (ch >= '0' && ch <= '9'):
+			j := i
+			for j < len(expr) && (expr[j] == '.' || (expr[j] >= '0' && expr[j] <= '9')) {
+				j++
+			}
+			p.tokens = append(p.tokens, expr[i:j])
+			i = j
+		case ch >= 'a' && ch <= 'z':
+			j := i
+			for j < len(expr) && expr[j] >= 'a' && expr[j] <= 'z' {
+				j++
+			}
+			// calc( nested inside calc() is plain grouping
+			if word := expr[i:j]; word != "calc" {
+				p.tokens = append(p.tokens, word)
+			}
+			i = j
+		default:
+			return fmt.Errorf("unexpected %q in %s", ch, expr)
+		}
+	}
+	return nil
+}
+
+func (p *calcParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *calcParser) sum() (float64, error) {
+	v, err := p.product()
+	for err == nil && (p.peek() == "+" || p.peek() == "-") {
+		op := p.tokens[p.pos]
+		p.pos++
+		var rhs float64
+		if rhs, err = p.product(); op == "+" {
+			v += rhs
+		} else {
+			v -= rhs
+		}
+	}
+	return v, err
+}
+
+func (p *calcParser) product() (float64, error) {
+	v, err := p.factor()
+	for err == nil && (p.peek() == "*" || p.peek() == "/") {
+		op := p.tokens[p.pos]
+		p.pos++
+		var rhs float64
+		if rhs, err = p.factor(); err != nil {
+			break
+		}
+		if op == "*" {
+			v *= rhs
+		} else if rhs == 0 {
+			return 0, fmt.Errorf("division by zero")
+		} else {
+			v /= rhs
+		}
+	}
+	return v, err
+}
+
+func (p *calcParser) factor() (float64, error) {
+	tok := p.peek()
+	p.pos++
+	switch {
+	case tok == "":
+		return 0, fmt.Errorf("unexpected end of calc()")
+	case tok == "-":
+		v, err := p.factor()
+		return -v, err
+	case tok == "+":
+		return p.factor()
+	case tok == "(":
+		v, err := p.sum()
+		if err != nil {
+			return 0, err
+		}
+		if p.peek() != ")" {
+			return 0, fmt.Errorf("missing ) in calc()")
+		}
+		p.pos++
+		return v, nil
+	}
+	if v, ok := p.env[tok]; ok {
+		return v, nil
+	}
+	v, err := strconv.ParseFloat(tok, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unknown value %q in calc()", tok)
+	}
+	return v, nil
+}
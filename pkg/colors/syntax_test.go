@@ -0,0 +1,352 @@
+// tokenctl/pkg/colors/syntax_test.go
+
+package colors
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParse_CSSColor4(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		wantHex string
+		wantFmt string
+	}{
+		{"lab", "lab(54.29% 80.8 69.89)", "#ff0000", FormatLab},
+		{"lab unitless lightness", "lab(54.29 80.8 69.89)", "#ff0000", FormatLab},
+		{"lch", "lch(54.29% 106.84 40.85)", "#ff0000", FormatLCH},
+		{"lch hue in turns", "lch(54.29% 106.84 0.11347turn)", "#ff0000", FormatLCH},
+		{"oklab", "oklab(62.8% 0.2249 0.1258)", "#ff0000", FormatOKLab},
+		{"oklab percentages", "oklab(62.8% 56.22% 31.45%)", "#ff0000", FormatOKLab},
+		{"hwb", "hwb(0 0% 0%)", "#ff0000", FormatHWB},
+		{"hwb gray", "hwb(90 60% 60%)", "#808080", FormatHWB},
+		{"srgb", "color(srgb 1 0 0)", "#ff0000", FormatSRGB},
+		{"srgb percentages", "color(srgb 100% 0% 0%)", "#ff0000", FormatSRGB},
+		{"srgb-linear", "color(srgb-linear 1 0.2159 0)", "#ff8000", FormatSRGBLinear},
+		{"display-p3", "color(display-p3 0.9175 0.2003 0.1386)", "#ff0000", FormatP3},
+		{"xyz", "color(xyz 0.4124 0.2126 0.0193)", "#ff0000", FormatXYZ},
+		{"xyz-d65", "color(xyz-d65 0.4124 0.2126 0.0193)", "#ff0000", FormatXYZ},
+		{"xyz-d50", "color(xyz-d50 0.4361 0.2225 0.0139)", "#ff0000", FormatXYZD50},
+		{"rgb modern", "rgb(59 130 246)", "#3b82f6", FormatRGB},
+		{"rgb none", "rgb(none 255 0)", "#00ff00", FormatRGB},
+		{"hsl modern with deg", "hsl(120deg 100% 25%)", "#008000", FormatHSL},
+		{"hsl hue in rad", "hsl(3.14159rad 100% 50%)", "#00ffff", FormatHSL},
+		{"hsl hue in grad", "hsl(200grad 100% 50%)", "#00ffff", FormatHSL},
+		{"hsl unitless saturation", "hsl(0 100 50)", "#ff0000", FormatHSL},
+		{"rgb clamps channels", "rgb(300 0 -20)", "#ff0000", FormatRGB},
+		{"hsl clamps saturation", "hsl(0 150% 50%)", "#ff0000", FormatHSL},
+		{"named rebeccapurple", "rebeccapurple", "#663399", FormatNamed},
+		{"named mixed case", "CornflowerBlue", "#6495ed", FormatNamed},
+		{"uppercase function", "OKLCH(62.8% 0.258 29.23)", "#ff0000", FormatOKLCH},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) unexpected error: %v", tt.input, err)
+			}
+			if got := c.Hex(); got != tt.wantHex {
+				t.Errorf("Parse(%q) = %s, want %s", tt.input, got, tt.wantHex)
+			}
+			if got := c.OriginalFormat(); got != tt.wantFmt {
+				t.Errorf("Parse(%q) format = %q, want %q", tt.input, got, tt.wantFmt)
+			}
+		})
+	}
+}
+
+func TestParse_Alpha(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input     string
+		wantAlpha float64
+	}{
+		{"#3b82f6", 1},
+		{"#3b82f680", 128.0 / 255},
+		{"#f008", 136.0 / 255},
+		{"rgba(59, 130, 246, 0.5)", 0.5},
+		{"rgb(59 130 246 / 50%)", 0.5},
+		{"hsla(217, 91%, 60%, 0.25)", 0.25},
+		{"oklch(62.8% 0.258 29.23 / 0.8)", 0.8},
+		{"lab(50% 20 30 / none)", 0},
+		{"color(display-p3 1 0 0 / 0.3)", 0.3},
+		{"rgb(0 0 0 / 150%)", 1},
+		{"transparent", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) unexpected error: %v", tt.input, err)
+			}
+			if got := c.Alpha(); math.Abs(got-tt.wantAlpha) > 1e-9 {
+				t.Errorf("Parse(%q) alpha = %v, want %v", tt.input, got, tt.wantAlpha)
+			}
+		})
+	}
+}
+
+func TestParse_RelativeColor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		input     string
+		wantHex   string
+		wantAlpha float64
+	}{
+		{"identity", "rgb(from #3b82f6 r g b)", "#3b82f6", 1},
+		{"replace channel", "rgb(from red r g 255)", "#ff00ff", 1},
+		{"calc on hue", "hsl(from red calc(h + 120) s l)", "#00ff00", 1},
+		{"nested calc", "rgb(from #804020 calc((r + g) / 2) calc(g * 2) b)", "#608020", 1},
+		{"named origin", "oklch(from rebeccapurple l c h)", "#663399", 1},
+		{"relative origin", "rgb(from rgb(from red r 255 b) r g 255)", "#ffffff", 1},
+		{"origin alpha carries", "rgb(from #ff000080 r g b)", "#ff000080", 128.0 / 255},
+		{"alpha keyword", "rgb(from #ff000080 r g b / calc(alpha * 2))", "#ff0000", 1},
+		{"color space", "color(from red srgb r g 1)", "#ff00ff", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) unexpected error: %v", tt.input, err)
+			}
+			if got := c.Hex(); got != tt.wantHex {
+				t.Errorf("Parse(%q) = %s, want %s", tt.input, got, tt.wantHex)
+			}
+			if got := c.Alpha(); math.Abs(got-tt.wantAlpha) > 1e-9 {
+				t.Errorf("Parse(%q) alpha = %v, want %v", tt.input, got, tt.wantAlpha)
+			}
+		})
+	}
+}
+
+func TestParse_RelativeColorLightness(t *testing.T) {
+	t.Parallel()
+
+	base := MustParse("#3b82f6")
+	c := MustParse("oklch(from #3b82f6 calc(l - 0.1) c h)")
+
+	bl, bc, bh := base.OkLch()
+	l, ch, h := c.OkLch()
+	if math.Abs(l-(bl-0.1)) > 1e-4 || math.Abs(ch-bc) > 1e-3 || math.Abs(h-bh) > 0.05 {
+		t.Errorf("got oklch(%v %v %v), want oklch(%v %v %v)", l, ch, h, bl-0.1, bc, bh)
+	}
+}
+
+func TestParse_UnresolvedOrigin(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{
+		"oklch(from var(--brand) calc(l - 0.1) c h)",
+		"rgb(from currentColor r g b / 50%)",
+	} {
+		_, err := Parse(input)
+		if !errors.Is(err, ErrUnresolvedOrigin) {
+			t.Errorf("Parse(%q) error = %v, want ErrUnresolvedOrigin", input, err)
+		}
+	}
+
+	// Syntax errors are still reported for browser-resolved origins
+	_, err := Parse("oklch(from var(--brand) calc(l -) c h)")
+	if err == nil || errors.Is(err, ErrUnresolvedOrigin) {
+		t.Errorf("expected a syntax error, got %v", err)
+	}
+}
+
+func TestParse_CSSColor4Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"commas in modern function", "lab(50%, 20, 30)"},
+		{"mixed separators", "rgb(255, 0 0)"},
+		{"too few channels", "rgb(255 0)"},
+		{"too many channels", "oklch(0.5 0.1 120 0.5)"},
+		{"unknown color space", "color(rec2020 1 0 0)"},
+		{"missing color space", "color()"},
+		{"percentage hue", "hsl(50% 100% 50%)"},
+		{"unknown channel keyword", "rgb(from red r g x)"},
+		{"keyword outside relative color", "rgb(r g b)"},
+		{"unknown origin", "rgb(from notacolor r g b)"},
+		{"missing origin", "rgb(from)"},
+		{"unbalanced calc", "rgb(from red calc(r g b)"},
+		{"division by zero", "rgb(from red calc(r / 0) g b)"},
+		{"misplaced alpha", "rgb(255 / 0.5 0 0)"},
+		{"unknown named color", "notacolor"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := Parse(tt.input); err == nil {
+				t.Errorf("Parse(%q) expected error, got nil", tt.input)
+			}
+		})
+	}
+}
+
+func TestColor_ToCSS_CSSColor4(t *testing.T) {
+	t.Parallel()
+
+	red := MustParse("#ff0000")
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{FormatHWB, "hwb(0.0 0.0% 0.0%)"},
+		{FormatLab, "lab(54.29% 80.80 69.89)"},
+		{FormatLCH, "lch(54.29% 106.84 40.86)"},
+		{FormatOKLab, "oklab(62.80% 0.225 0.126)"},
+		{FormatP3, "color(display-p3 0.9175 0.2003 0.1386)"},
+		{FormatSRGB, "color(srgb 1.0000 0.0000 0.0000)"},
+		{FormatSRGBLinear, "color(srgb-linear 1.0000 0.0000 0.0000)"},
+		{FormatXYZ, "color(xyz-d65 0.4124 0.2126 0.0193)"},
+		{FormatXYZD50, "color(xyz-d50 0.4361 0.2225 0.0139)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			t.Parallel()
+
+			if got := red.ToCSS(tt.format); got != tt.want {
+				t.Errorf("ToCSS(%q) = %q, want %q", tt.format, got, tt.want)
+			}
+			if got := red.WithAlpha(0.5).ToCSS(tt.format); got != tt.want[:len(tt.want)-1]+" / 0.5)" {
+				t.Errorf("ToCSS(%q) with alpha = %q", tt.format, got)
+			}
+		})
+	}
+}
+
+func TestColor_ToCSS_OutOfSRGB(t *testing.T) {
+	t.Parallel()
+
+	// Beyond sRGB on every channel: red above 1, green and blue below 0
+	p3Red := MustParse("color(display-p3 1 0 0)")
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{FormatHex, "#ff0000"},
+		{FormatRGB, "rgb(255, 0, 0)"},
+		{FormatHSL, "hsl(0.0, 100.0%, 50.0%)"},
+		{FormatHWB, "hwb(0.0 0.0% 0.0%)"},
+		{FormatP3, "color(display-p3 1 0 0)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			t.Parallel()
+
+			if got := p3Red.ToCSS(tt.format); got != tt.want {
+				t.Errorf("ToCSS(%q) = %q, want %q", tt.format, got, tt.want)
+			}
+		})
+	}
+	if got := p3Red.ToRGB(); got != "rgb(255, 0, 0)" {
+		t.Errorf("ToRGB() = %q, want rgb(255, 0, 0)", got)
+	}
+}
+
+func TestColor_ToOriginalFormat_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	inputs := []string{
+		"lab(54.29% 80.8 69.89)",
+		"lch(70% 45 200)",
+		"oklab(70% -0.1 0.05)",
+		"hwb(200 10% 30%)",
+		"color(display-p3 0.2 0.6 0.4)",
+		"color(srgb-linear 0.2 0.5 0.9)",
+		"color(xyz-d50 0.3 0.4 0.2)",
+		"rgb(59 130 246 / 0.5)",
+	}
+
+	for _, input := range inputs {
+		t.Run(input, func(t *testing.T) {
+			t.Parallel()
+
+			c := MustParse(input)
+			out := c.ToOriginalFormat()
+			again, err := Parse(out)
+			if err != nil {
+				t.Fatalf("Parse(%q) unexpected error: %v", out, err)
+			}
+			if again.OriginalFormat() != c.OriginalFormat() {
+				t.Errorf("%q -> %q changed format %q -> %q", input, out, c.OriginalFormat(), again.OriginalFormat())
+			}
+			if deltaEOK(c, again) > 0.001 || math.Abs(c.Alpha()-again.Alpha()) > 0.001 {
+				t.Errorf("%q -> %q does not round-trip", input, out)
+			}
+		})
+	}
+}
+
+func TestColor_ToCSS_KeepsAuthoredChannels(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		input  string
+		format string
+		want   string
+	}{
+		{"oklch as written", "oklch(60% 0.1 200)", FormatOKLCH, "oklch(60% 0.1 200)"},
+		{"none carried", "oklch(70% 0 none)", FormatOKLCH, "oklch(70% 0 none)"},
+		{"achromatic hue is none", "#ffffff", FormatOKLCH, "oklch(100.00% 0.000 none)"},
+		{"achromatic hsl hue is none", "hsl(200 0% 50%)", FormatHSL, "hsl(none 0% 50%)"},
+		{"modern hsl", "hsl(200 50% 50% / 0.5)", FormatHSL, "hsl(200 50% 50% / 0.5)"},
+		{"legacy hsl", "hsla(200, 50%, 50%, 0.5)", FormatHSL, "hsla(200, 50%, 50%, 0.5)"},
+		{"modern rgb with none", "rgb(none 128 255 / 50%)", FormatRGB, "rgb(none 128 255 / 0.5)"},
+		{"rgb clamped as parsed", "rgb(300 0 -20)", FormatRGB, "rgb(255 0 0)"},
+		{"hue in turns", "lch(50% 30 0.5turn)", FormatLCH, "lch(50% 30 180)"},
+		{"named", "rebeccapurple", FormatNamed, "rebeccapurple"},
+		{"named keeps case", "CornflowerBlue", FormatNamed, "CornflowerBlue"},
+		{"transparent", "transparent", FormatNamed, "transparent"},
+		{"converted when the format changes", "oklch(60% 0.1 200)", FormatHex, "#0d9298"},
+		{"relative color is computed", "oklch(from #3b82f6 l c h)", FormatOKLCH, "oklch(62.31% 0.188 259.83)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := MustParse(tt.input).ToCSS(tt.format); got != tt.want {
+				t.Errorf("Parse(%q).ToCSS(%q) = %q, want %q", tt.input, tt.format, got, tt.want)
+			}
+		})
+	}
+
+	// A changed color no longer has channels as written
+	c := MustParse("rebeccapurple")
+	if got := c.WithAlpha(0.5).ToOriginalFormat(); got != "#66339980" {
+		t.Errorf("translucent named color = %q, want #66339980", got)
+	}
+	if got := RotateHue(MustParse("oklch(60% 0.1 200)"), 30).ToCSS(FormatOKLCH); got == "oklch(60% 0.1 200)" {
+		t.Errorf("rotated color kept its authored channels: %q", got)
+	}
+	if got := MustParse("oklch(60% 0.1 200)").ToCSSPrecision(FormatOKLCH, 1); got != "oklch(60.0% 0.1 200.0)" {
+		t.Errorf("ToCSSPrecision(oklch, 1) = %q, want oklch(60.0%% 0.1 200.0)", got)
+	}
+}
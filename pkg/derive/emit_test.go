@@ -243,6 +243,21 @@ func TestParamsValidate(t *testing.T) {
 	}
 }
 
+func TestPrimaryChroma_ClampsAtMax(t *testing.T) {
+	t.Parallel()
+
+	p := DefaultParams
+	p.Chroma, p.Saturation = 0.35, 150
+	if got := p.PrimaryChroma(); got <= MaxChroma {
+		t.Fatalf("PrimaryChroma() = %g, want above %g", got, MaxChroma)
+	}
+	// The theme is still built, at the ceiling
+	want := oklch(55, MaxChroma, p.Hue)
+	if got, _ := GenerateColors(p).Get("--color-primary"); got != want {
+		t.Errorf("--color-primary = %s, want %s", got, want)
+	}
+}
+
 func TestPresetsAndSystems(t *testing.T) {
 	t.Parallel()
 
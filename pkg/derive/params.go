@@ -16,7 +16,7 @@ import "fmt"
 // Params fully describes a derived theme.
 type Params struct {
 	Hue         float64 // 0–360, OKLCH hue angle
-	Chroma      float64 // OKLCH chroma of the primary; clamped to 0.4
+	Chroma      float64 // OKLCH chroma of the primary; clamped to MaxChroma
 	IsDark      bool    // dark mode instead of light
 	Tint        float64 // 0–100: how much the hue bleeds into neutrals
 	Saturation  float64 // 0–150: global chroma multiplier, 100 = normal
@@ -37,6 +37,11 @@ var DefaultParams = Params{
 
 // Bounds of each control, as documented by the engine.
 const (
+	// MaxChroma caps every derived chroma after the saturation
+	// multiplier. Unlike the other bounds it clamps rather than refuses,
+	// since it is the product of two valid controls that overflows it.
+	MaxChroma = 0.4
+
 	TintMin       = 0.0
 	TintMax       = 100.0
 	SaturationMin = 0.0
@@ -110,6 +115,12 @@ func (p Params) Validate() error {
 	return nil
 }
 
+// PrimaryChroma is the chroma the primary is derived at: Chroma scaled
+// by Saturation. Above MaxChroma the theme is built at MaxChroma.
+func (p Params) PrimaryChroma() float64 {
+	return p.Chroma * p.Saturation / 100
+}
+
 func equalFold(a, b string) bool {
 	if len(a) != len(b) {
 		return false
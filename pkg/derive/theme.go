@@ -69,7 +69,7 @@ func oklch(l, c, h float64) string {
 }
 
 func clampL(l float64) float64 { return math.Max(0, math.Min(100, l)) }
-func clampC(c float64) float64 { return math.Max(0, math.Min(0.4, c)) }
+func clampC(c float64) float64 { return math.Max(0, math.Min(MaxChroma, c)) }
 
 func generateColors(p Params, t *Theme) {
 	// Saturation 0–150 maps to a 0.0–1.5x multiplier on action chroma.
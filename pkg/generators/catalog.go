@@ -101,16 +101,23 @@ type ComponentSummary struct {
 type ThemeInfo struct {
 	Extends     *string        `json:"extends"`
 	Description string         `json:"description,omitempty"`
+	ColorScheme string         `json:"color_scheme,omitempty"`
 	Tokens      map[string]any `json:"tokens"`
 	Diff        map[string]any `json:"diff,omitempty"`
+
+	// Components is the component declarations the theme changes,
+	// keyed by the selector of the rule they belong to
+	Components map[string]map[string]any `json:"components,omitempty"`
 }
 
 // CatalogThemeInput provides theme data from the build process
 type CatalogThemeInput struct {
-	Extends        *string        // Parent theme name (nil if extends base)
-	Description    string         // From $description field
-	ResolvedTokens map[string]any // Fully resolved token values
-	DiffTokens     map[string]any // Only tokens that differ from parent/base
+	Extends        *string                               // Parent theme name (nil if extends base)
+	Description    string                                // From $description field
+	ColorScheme    string                                // From $colorScheme, inherited through $extends
+	ResolvedTokens map[string]any                        // Fully resolved token values
+	DiffTokens     map[string]any                        // Only tokens that differ from parent/base
+	Components     map[string]tokens.ComponentDefinition // Components as the theme resolves them
 }
 
 // Generate creates the JSON catalog
@@ -143,9 +150,14 @@ func (g *CatalogGenerator) GenerateWithMetadata(
 		Themes:     make(map[string]ThemeInfo),
 	}
 
-	// 1. Filter Atomic Tokens (exclude components/maps)
-	for k, v := range resolvedTokens {
-		if _, ok := v.(map[string]any); !ok {
+	// 1. Filter Atomic Tokens (exclude components/maps; composites are
+	// written as the CSS value the stylesheet carries)
+	for k, raw := range resolvedTokens {
+		var tokenType string
+		if meta, ok := metadata[k]; ok {
+			tokenType = meta.Type
+		}
+		if v, ok := catalogValue(raw, tokenType); ok {
 			// Apply category filter if specified
 			if g.Category != "" && !g.matchesCategory(k) {
 				continue
@@ -236,8 +248,10 @@ func (g *CatalogGenerator) GenerateWithMetadata(
 			themeInfo := ThemeInfo{
 				Extends:     themeInput.Extends,
 				Description: themeInput.Description,
+				ColorScheme: themeInput.ColorScheme,
 				Tokens:      filterAtomicTokens(themeInput.ResolvedTokens),
 				Diff:        filterAtomicTokens(themeInput.DiffTokens),
+				Components:  themeComponentDiff(themeInput.Components, components),
 			}
 			catalog.Themes[name] = themeInfo
 		}
@@ -246,14 +260,20 @@ func (g *CatalogGenerator) GenerateWithMetadata(
 		for name, themeInput := range themes {
 			filteredTokens := g.filterByCategory(filterAtomicTokens(themeInput.ResolvedTokens))
 			filteredDiff := g.filterByCategory(filterAtomicTokens(themeInput.DiffTokens))
+			var overrides map[string]map[string]any
+			if g.Category == "components" {
+				overrides = themeComponentDiff(themeInput.Components, components)
+			}
 
 			// Only include theme if it has tokens in this category
-			if len(filteredTokens) > 0 || len(filteredDiff) > 0 {
+			if len(filteredTokens) > 0 || len(filteredDiff) > 0 || len(overrides) > 0 {
 				themeInfo := ThemeInfo{
 					Extends:     themeInput.Extends,
 					Description: themeInput.Description,
+					ColorScheme: themeInput.ColorScheme,
 					Tokens:      filteredTokens,
 					Diff:        filteredDiff,
+					Components:  overrides,
 				}
 				catalog.Themes[name] = themeInfo
 			}
@@ -280,7 +300,7 @@ func (g *CatalogGenerator) GenerateWithMetadata(
 }
 
 // sortedKeys returns a map's keys in a stable order.
-func sortedKeys(m map[string]tokens.VariantDef) []string {
+func sortedKeys[V any](m map[string]V) []string {
 	keys := make([]string, 0, len(m))
 	for k := range m {
 		keys = append(keys, k)
@@ -289,20 +309,35 @@ func sortedKeys(m map[string]tokens.VariantDef) []string {
 	return keys
 }
 
-// filterAtomicTokens filters out nested maps, keeping only atomic token values
+// filterAtomicTokens filters out nested maps, keeping only atomic token
+// values. Composite values are kept, serialized to CSS.
 func filterAtomicTokens(tokens map[string]any) map[string]any {
 	if tokens == nil {
 		return nil
 	}
 	result := make(map[string]any)
 	for k, v := range tokens {
-		if _, ok := v.(map[string]any); !ok {
-			result[k] = v
+		if val, ok := catalogValue(v, ""); ok {
+			result[k] = val
 		}
 	}
 	return result
 }
 
+// catalogValue returns the value the catalog records for a resolved
+// token. Composite values become the same CSS string the stylesheet
+// emits, so a consumer never has to know the sub-field layout; other
+// object values are not tokens and are dropped.
+func catalogValue(v any, tokenType string) (any, bool) {
+	if css, ok := tokens.FormatCompositeCSS(tokenType, v); ok {
+		return css, true
+	}
+	if _, ok := v.(map[string]any); ok {
+		return nil, false
+	}
+	return v, true
+}
+
 // matchesCategory checks if a token path belongs to the specified category
 // Category matching is based on the first segment of the token path
 // e.g., "color.primary" matches category "color"
@@ -361,3 +396,16 @@ func hasRichMetadata(meta *tokens.TokenMetadata) bool {
 	}
 	return meta.Description != "" || len(meta.Usage) > 0 || meta.Avoid != "" || meta.Deprecated != nil || meta.Customizable
 }
+
+// themeComponentDiff returns a theme's component overrides for its
+// catalog entry, nil when it has none
+func themeComponentDiff(theme, base map[string]tokens.ComponentDefinition) map[string]map[string]any {
+	if theme == nil {
+		return nil
+	}
+	overrides := ComponentOverrides(theme, base)
+	if len(overrides) == 0 {
+		return nil
+	}
+	return overrides
+}
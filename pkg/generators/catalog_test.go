@@ -3,6 +3,8 @@ package generators
 
 import (
 	"encoding/json"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/dmoose/tokenctl/pkg/tokens"
@@ -143,6 +145,7 @@ func TestCatalogGenerator_Generate_WithThemes(t *testing.T) {
 		"dark": {
 			Extends:     &darkExtends,
 			Description: "Dark theme extends light theme",
+			ColorScheme: "dark",
 			ResolvedTokens: map[string]any{
 				"color.primary": "#1e40af",
 			},
@@ -209,6 +212,15 @@ func TestCatalogGenerator_Generate_WithThemes(t *testing.T) {
 	if darkTheme.Diff["color.primary"] != "#1e40af" {
 		t.Errorf("Expected dark theme diff to contain color.primary")
 	}
+	if darkTheme.ColorScheme != "dark" {
+		t.Errorf("Expected dark theme color_scheme dark, got %q", darkTheme.ColorScheme)
+	}
+	if lightTheme.ColorScheme != "" {
+		t.Errorf("Expected no color_scheme for light theme, got %q", lightTheme.ColorScheme)
+	}
+	if strings.Count(result, `"color_scheme"`) != 1 {
+		t.Errorf("Expected color_scheme only where declared:\n%s", result)
+	}
 }
 
 func TestCatalogGenerator_Generate_FiltersNestedMaps(t *testing.T) {
@@ -471,6 +483,51 @@ func TestCatalogGenerator_CategoryFilter_WithThemes(t *testing.T) {
 	}
 }
 
+func TestCatalogGenerator_ThemeComponents(t *testing.T) {
+	t.Parallel()
+
+	base := map[string]tokens.ComponentDefinition{
+		"card": {Class: "card", Base: map[string]any{"padding": "1rem", "border": "1px solid #e5e7eb"}},
+	}
+	dark := map[string]tokens.ComponentDefinition{
+		"card": {Class: "card", Base: map[string]any{"padding": "1rem", "border": "2px solid #374151"}},
+	}
+	themes := map[string]CatalogThemeInput{
+		"dark":  {ResolvedTokens: map[string]any{}, DiffTokens: map[string]any{}, Components: dark},
+		"light": {ResolvedTokens: map[string]any{}, DiffTokens: map[string]any{}, Components: base},
+	}
+
+	tests := []struct {
+		name     string
+		category string
+		want     map[string]map[string]any
+	}{
+		{"unfiltered", "", map[string]map[string]any{".card": {"border": "2px solid #374151"}}},
+		{"components category", "components", map[string]map[string]any{".card": {"border": "2px solid #374151"}}},
+		{"other category", "color", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			gen := NewCatalogGeneratorWithOptions(CatalogOptions{Category: tt.category})
+			result, err := gen.Generate(map[string]any{}, base, themes)
+			if err != nil {
+				t.Fatalf("Generate failed: %v", err)
+			}
+			var catalog CatalogSchema
+			if err := json.Unmarshal([]byte(result), &catalog); err != nil {
+				t.Fatalf("Failed to parse catalog JSON: %v", err)
+			}
+			if got := catalog.Themes["dark"].Components; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("dark components = %v, want %v", got, tt.want)
+			}
+			if got := catalog.Themes["light"].Components; got != nil {
+				t.Errorf("expected no components for an unchanged theme, got %v", got)
+			}
+		})
+	}
+}
+
 func TestCatalogGenerator_CategoryFilter_PluralSingular(t *testing.T) {
 	t.Parallel()
 
@@ -11,12 +11,20 @@ import (
 
 // CSSGenerator generates pure CSS without Tailwind dependencies
 type CSSGenerator struct {
+	ColorFallback string // Optional: ColorFallbackSupports or ColorFallbackGamut
+	ColorScheme   string // Optional: ColorSchemeMedia or ColorSchemeLightDark
+	ThemeSelector string // Optional: selector template, see CSSOptions
 }
 
 func NewCSSGenerator() *CSSGenerator {
 	return &CSSGenerator{}
 }
 
+// NewCSSGeneratorWithOptions creates a CSS generator with the given options
+func NewCSSGeneratorWithOptions(opts CSSOptions) *CSSGenerator {
+	return &CSSGenerator{ColorFallback: opts.ColorFallback, ColorScheme: opts.ColorScheme, ThemeSelector: opts.ThemeSelector}
+}
+
 // Generate creates pure CSS from generation context
 func (g *CSSGenerator) Generate(ctx *GenerationContext) (string, error) {
 	var sb strings.Builder
@@ -26,7 +34,7 @@ func (g *CSSGenerator) Generate(ctx *GenerationContext) (string, error) {
 
 	// 2. @property declarations (if any)
 	if len(ctx.PropertyTokens) > 0 {
-		sb.WriteString(generatePropertyDeclarations(ctx.PropertyTokens))
+		sb.WriteString(generatePropertyDeclarations(ctx.PropertyTokens, g.ColorFallback))
 	}
 
 	// 3. @keyframes declarations (global animations)
@@ -39,35 +47,52 @@ func (g *CSSGenerator) Generate(ctx *GenerationContext) (string, error) {
 	sb.WriteString(generateReset())
 
 	// 5. Root variables (in tokens layer)
-	rootVars, err := g.generateRootVariables(ctx.ResolvedTokens)
+	rootVars, err := g.generateRootVariables(ctx.ResolvedTokens, tokenTypes(ctx.BaseDict))
 	if err != nil {
 		return "", fmt.Errorf("failed to generate root variables: %w", err)
 	}
 	sb.WriteString(rootVars)
 
+	defaultTheme := ctx.DefaultTheme
+	if defaultTheme == "" {
+		defaultTheme = DefaultThemeName
+	}
+	themes := cascadeCombinations(ctx.Themes, ctx.ResolvedTokens, defaultTheme)
+
 	// 6. Theme variations
-	if len(ctx.Themes) > 0 {
-		defaultTheme := ctx.DefaultTheme
-		if defaultTheme == "" {
-			defaultTheme = DefaultThemeName
-		}
-		themeVariations, err := g.generateThemeVariations(ctx.Themes, defaultTheme)
+	if len(themes) > 0 {
+		themeVariations, err := g.generateThemeVariations(themes, defaultTheme)
 		if err != nil {
 			return "", fmt.Errorf("failed to generate theme variations: %w", err)
 		}
 		sb.WriteString(themeVariations)
 	}
 
-	// 7. Components
-	if len(ctx.Components) > 0 {
-		components, err := g.generateComponents(ctx.Components, ctx.Breakpoints)
+	// 7. Forced colors token overrides, after the themes they must win over
+	if forced := forcedColorTokens(ctx.ForcedColors, newThemeSelectors(themes, defaultTheme, g.ThemeSelector)); forced != "" {
+		sb.WriteString("@layer themes {\n")
+		sb.WriteString(forced)
+		sb.WriteString("}\n\n")
+	}
+
+	// 8. Components, ending with the theme component overrides scoped
+	// under each theme's selector
+	themeRules := themeComponentRules(ctx.Themes, ctx.Components, newThemeSelectors(ctx.Themes, defaultTheme, g.ThemeSelector), g.ColorScheme)
+	if len(ctx.Components) > 0 || themeRules != "" {
+		components, err := g.generateComponents(ctx.Components, ctx.Breakpoints, themeRules)
 		if err != nil {
 			return "", fmt.Errorf("failed to generate components: %w", err)
 		}
 		sb.WriteString(components)
 	}
 
-	// 8. Responsive overrides via media queries
+	// 9. Component forced colors overrides
+	if forced := generateForcedColorComponents(ctx.Components); forced != "" {
+		sb.WriteString("\n")
+		sb.WriteString(forced)
+	}
+
+	// 10. Responsive overrides via media queries
 	if len(ctx.ResponsiveTokens) > 0 {
 		responsiveCSS := tokens.GenerateResponsiveCSS(ctx.Breakpoints, ctx.ResponsiveTokens)
 		if responsiveCSS != "" {
@@ -76,7 +101,13 @@ func (g *CSSGenerator) Generate(ctx *GenerationContext) (string, error) {
 		}
 	}
 
-	// 9. Container query overrides
+	// 11. Reduced motion overrides
+	if len(ctx.ReducedMotion) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(tokens.GenerateReducedMotionCSS(ctx.ReducedMotion))
+	}
+
+	// 12. Container query overrides
 	if len(ctx.ContainerOverrides) > 0 {
 		containerCSS := GenerateContainerCSS(ctx.ContainerOverrides)
 		if containerCSS != "" {
@@ -89,7 +120,7 @@ func (g *CSSGenerator) Generate(ctx *GenerationContext) (string, error) {
 }
 
 // generateRootVariables creates :root block with base tokens in @layer tokens
-func (g *CSSGenerator) generateRootVariables(resolvedTokens map[string]any) (string, error) {
+func (g *CSSGenerator) generateRootVariables(resolvedTokens map[string]any, types map[string]string) (string, error) {
 	var sb strings.Builder
 	sb.WriteString("@layer tokens {\n")
 	sb.WriteString("  :root {\n")
@@ -101,19 +132,21 @@ func (g *CSSGenerator) generateRootVariables(resolvedTokens map[string]any) (str
 	}
 	sort.Strings(keys)
 
+	fallbacks := fallbackBlock{mode: g.ColorFallback}
 	for _, path := range keys {
 		value := resolvedTokens[path]
-		// Skip non-primitive values
-		if _, ok := value.(map[string]any); ok {
+		// Skip object values that have no CSS form (composites serialize)
+		cssValue, ok := serializeTokenValue(value, types[path])
+		if !ok {
 			continue
 		}
 
 		cssVar := strings.ReplaceAll(path, ".", "-")
-		cssValue := serializeValueForCSS(value)
-		fmt.Fprintf(&sb, "    --%s: %s;\n", cssVar, cssValue)
+		fmt.Fprintf(&sb, "    --%s: %s;\n", cssVar, fallbacks.value(cssVar, cssValue, types[path]))
 	}
 
 	sb.WriteString("  }\n")
+	fallbacks.write(&sb, ":root", 2)
 	sb.WriteString("}\n\n")
 	return sb.String(), nil
 }
@@ -129,42 +162,51 @@ func (g *CSSGenerator) generateThemeVariations(themes map[string]ThemeContext, d
 		themeNames = append(themeNames, name)
 	}
 	sortThemeNames(themeNames, defaultTheme)
+	selectors := newThemeSelectors(themes, defaultTheme, g.ThemeSelector)
+
+	var lightDark lightDarkPlan
+	if g.ColorScheme == ColorSchemeLightDark {
+		plan, err := planLightDark(selectors)
+		if err != nil {
+			return "", err
+		}
+		lightDark = plan
+		lightDark.write(&sb, selectors, g.ColorFallback)
+	}
 
 	for _, themeName := range themeNames {
 		themeCtx := themes[themeName]
 
-		// Skip if no diff tokens
-		if len(themeCtx.DiffTokens) == 0 {
+		scheme := lightDark.scheme(themeName, themeCtx)
+
+		// Skip if there is nothing to declare
+		if (len(themeCtx.DiffTokens) == 0 || lightDark.covered(themeName, themeCtx)) && scheme == "" {
 			continue
 		}
 
-		fmt.Fprintf(&sb, "  %s {\n", themeSelector(themeName, defaultTheme))
-
-		// Sort token keys for deterministic output
-		tokenKeys := make([]string, 0, len(themeCtx.DiffTokens))
-		for key := range themeCtx.DiffTokens {
-			tokenKeys = append(tokenKeys, key)
-		}
-		sort.Strings(tokenKeys)
+		writeInMedia(&sb, selectors.media(themeName), 2, func(indent int) {
+			writeThemeRule(&sb, selectors.selector(themeName), themeCtx, scheme, lightDark.skip(themeName), g.ColorFallback, indent)
+		})
+	}
 
-		for _, key := range tokenKeys {
-			val := themeCtx.DiffTokens[key]
-			cssVar := strings.ReplaceAll(key, ".", "-")
-			cssValue := serializeValueForCSS(val)
-			fmt.Fprintf(&sb, "    --%s: %s;\n", cssVar, cssValue)
+	switch g.ColorScheme {
+	case ColorSchemeMedia:
+		if err := writeSchemeMedia(&sb, selectors, g.ColorFallback); err != nil {
+			return "", err
 		}
-
-		sb.WriteString("  }\n")
+	case ColorSchemeLightDark:
+		lightDark.writeSchemeMedia(&sb, selectors, g.ColorFallback)
 	}
 
 	sb.WriteString("}\n\n")
 	return sb.String(), nil
 }
 
-// generateComponents creates @layer components with component styles.
-// breakpoints are used to emit per-component @media rules for any
-// component property declared with {"$value": ..., "$responsive": {bp: value}}.
-func (g *CSSGenerator) generateComponents(components map[string]tokens.ComponentDefinition, breakpoints map[string]string) (string, error) {
+// generateComponents creates @layer components with component styles,
+// closing it with themeRules. breakpoints are used to emit per-component
+// @media rules for any component property declared with
+// {"$value": ..., "$responsive": {bp: value}}.
+func (g *CSSGenerator) generateComponents(components map[string]tokens.ComponentDefinition, breakpoints map[string]string, themeRules string) (string, error) {
 	var sb strings.Builder
 	sb.WriteString("@layer components {\n")
 
@@ -317,6 +359,7 @@ func (g *CSSGenerator) generateComponents(components map[string]tokens.Component
 		}
 	}
 
+	sb.WriteString(themeRules)
 	sb.WriteString("}\n")
 
 	// Per-component responsive overrides. For any property declared as
@@ -418,6 +418,43 @@ func TestCSSGenerator_SkipsMapValues(t *testing.T) {
 	}
 }
 
+func TestCSSGenerator_CompositeValues(t *testing.T) {
+	t.Parallel()
+
+	dict := tokens.NewDictionary()
+	dict.Root = map[string]any{
+		"easing": map[string]any{
+			"$type":    "cubicBezier",
+			"standard": map[string]any{"$value": []any{0.4, 0.0, 0.2, 1.0}},
+		},
+	}
+
+	g := NewCSSGenerator()
+	ctx := &GenerationContext{
+		BaseDict: dict,
+		ResolvedTokens: map[string]any{
+			"shadow.sm":       map[string]any{"color": "#0000001a", "offsetX": "0", "offsetY": "1px", "blur": "2px"},
+			"border.focus":    map[string]any{"color": "#3b82f6", "width": "2px", "style": "solid"},
+			"easing.standard": []any{0.4, 0.0, 0.2, 1.0},
+		},
+	}
+
+	output, err := g.Generate(ctx)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"--shadow-sm: 0 1px 2px 0 #0000001a;",
+		"--border-focus: 2px solid #3b82f6;",
+		"--easing-standard: cubic-bezier(0.4, 0, 0.2, 1);",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("missing %q in output:\n%s", want, output)
+		}
+	}
+}
+
 func TestCSSGenerator_DeterministicOutput(t *testing.T) {
 	t.Parallel()
 
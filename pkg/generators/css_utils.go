@@ -3,8 +3,35 @@ package generators
 import (
 	"fmt"
 	"strings"
+
+	"github.com/dmoose/tokenctl/pkg/tokens"
 )
 
+// serializeTokenValue converts a resolved token value to the value of its
+// custom property. Composite types (shadow, typography, transition, ...)
+// serialize to their CSS shorthand; tokenType may be empty, in which case
+// the composite type is inferred from the value's shape. The second
+// result is false for object values that are not a known composite —
+// those have no CSS form and the caller skips them.
+func serializeTokenValue(val any, tokenType string) (string, bool) {
+	if css, ok := tokens.FormatCompositeCSS(tokenType, val); ok {
+		return css, true
+	}
+	if _, ok := val.(map[string]any); ok {
+		return "", false
+	}
+	return serializeValueForCSS(val), true
+}
+
+// tokenTypes returns the effective $type of every token in d, or nil
+// when there is no dictionary to read them from.
+func tokenTypes(d *tokens.Dictionary) map[string]string {
+	if d == nil {
+		return nil
+	}
+	return tokens.ExtractTypes(d)
+}
+
 // serializeValueForCSS converts any value to a proper CSS string for custom properties.
 // Handles arrays by joining with comma separation, and string slices.
 func serializeValueForCSS(val any) string {
@@ -73,6 +100,11 @@ func SerializeValueForProperty(property string, val any) string {
 		if base, ok := v["$value"]; ok {
 			return SerializeValueForProperty(property, base)
 		}
+		// Composite literal written inline on a component property,
+		// e.g. "box-shadow": {"offsetX": "0", "offsetY": "1px", ...}.
+		if css, ok := tokens.FormatCompositeCSS("", v); ok {
+			return css
+		}
 		// Plain object value with no $value (e.g. an unrelated nested
 		// map — shouldn't reach here, but be defensive rather than
 		// emit map[...] junk). Empty string means "skip this property"
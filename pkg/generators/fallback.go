@@ -0,0 +1,110 @@
+// tokenctl/pkg/generators/fallback.go
+package generators
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dmoose/tokenctl/pkg/colors"
+)
+
+// Color fallback modes for CSSOptions.ColorFallback
+const (
+	// ColorFallbackSupports writes every oklch(), lab(), color() ... value
+	// as sRGB hex, restating it under @supports (color: oklch(0 0 0))
+	ColorFallbackSupports = "supports"
+
+	// ColorFallbackGamut writes colors outside sRGB as sRGB hex, restating
+	// them under @media (color-gamut: p3)
+	ColorFallbackGamut = "gamut"
+)
+
+// fallbackCondition returns the at-rule prelude wide-gamut values are
+// restated under
+func fallbackCondition(mode string) string {
+	if mode == ColorFallbackGamut {
+		return "@media (color-gamut: p3)"
+	}
+	return "@supports (color: oklch(0 0 0))"
+}
+
+// legacyColorFormats are understood by every browser, so they need no
+// fallback
+var legacyColorFormats = map[string]bool{
+	colors.FormatHex:   true,
+	colors.FormatRGB:   true,
+	colors.FormatHSL:   true,
+	colors.FormatNamed: true,
+}
+
+// colorFallback returns the sRGB hex to write in place of value, and
+// false when value needs no fallback: fallbacks are off, it is not a
+// color, or it is one every browser and screen shows as written. Colors
+// outside sRGB are gamut mapped rather than clipped.
+func colorFallback(mode, value, tokenType string) (string, bool) {
+	if mode == "" || (tokenType != "" && tokenType != "color") {
+		return "", false
+	}
+	c, err := colors.Parse(value)
+	if err != nil || legacyColorFormats[c.OriginalFormat()] {
+		return "", false
+	}
+	inSRGB := colors.InGamut(c, colors.GamutSRGB)
+	if mode == ColorFallbackGamut && inSRGB {
+		return "", false
+	}
+	if !inSRGB {
+		c = colors.MapToGamut(c, colors.GamutSRGB)
+	}
+	return c.Hex(), true
+}
+
+// fallbackBlock collects the declarations of one rule whose values were
+// replaced by fallbacks, so they can be restated with their authored
+// value under the fallback condition once the rule is closed
+type fallbackBlock struct {
+	mode  string
+	guard string // optional at-rule the fallback condition sits in
+	decls []string
+}
+
+// value returns the value to declare cssVar with now, remembering the
+// authored one when a fallback is written instead
+func (f *fallbackBlock) value(cssVar, value, tokenType string) string {
+	fallback, ok := colorFallback(f.mode, value, tokenType)
+	if !ok {
+		return value
+	}
+	f.restate(cssVar, value)
+	return fallback
+}
+
+// restate remembers a declaration to write under the fallback condition
+func (f *fallbackBlock) restate(cssVar, value string) {
+	f.decls = append(f.decls, fmt.Sprintf("--%s: %s;", cssVar, value))
+}
+
+// write restates the collected declarations for selector under the
+// fallback condition, indented to sit beside the rule, and resets the
+// block for the next rule
+func (f *fallbackBlock) write(sb *strings.Builder, selector string, indent int) {
+	if len(f.decls) == 0 {
+		return
+	}
+	if f.guard != "" {
+		fmt.Fprintf(sb, "%s%s {\n", strings.Repeat(" ", indent), f.guard)
+		indent += 2
+	}
+	pad := strings.Repeat(" ", indent)
+	fmt.Fprintf(sb, "%s%s {\n", pad, fallbackCondition(f.mode))
+	fmt.Fprintf(sb, "%s  %s {\n", pad, selector)
+	for _, decl := range f.decls {
+		fmt.Fprintf(sb, "%s    %s\n", pad, decl)
+	}
+	fmt.Fprintf(sb, "%s  }\n", pad)
+	fmt.Fprintf(sb, "%s}\n", pad)
+	if f.guard != "" {
+		fmt.Fprintf(sb, "%s}\n", strings.Repeat(" ", indent-2))
+	}
+	f.decls = nil
+}
@@ -0,0 +1,116 @@
+// tokenctl/pkg/generators/fallback_test.go
+package generators
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dmoose/tokenctl/pkg/tokens"
+)
+
+func TestColorFallback(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		mode      string
+		value     string
+		tokenType string
+		want      string
+		wantOK    bool
+	}{
+		{"off", "", "oklch(70% 0.1 200)", "color", "", false},
+		{"oklch in sRGB", ColorFallbackSupports, "oklch(70% 0.1 200)", "color", "#40b1b7", true},
+		{"untyped oklch", ColorFallbackSupports, "oklch(70% 0.1 200)", "", "#40b1b7", true},
+		{"out of sRGB is mapped", ColorFallbackSupports, "oklch(49.12% 0.309 275.75)", "color", "#4a00ff", true},
+		{"display-p3", ColorFallbackSupports, "color(display-p3 0.2 0.6 0.4)", "color", "#009c61", true},
+		{"keeps alpha", ColorFallbackSupports, "oklch(70% 0.1 200 / 0.5)", "color", "#40b1b780", true},
+		{"hex needs none", ColorFallbackSupports, "#3b82f6", "color", "", false},
+		{"rgb needs none", ColorFallbackSupports, "rgb(59 130 246)", "color", "", false},
+		{"named needs none", ColorFallbackSupports, "red", "color", "", false},
+		{"reference", ColorFallbackSupports, "var(--color-primary)", "color", "", false},
+		{"not a color token", ColorFallbackSupports, "oklch(70% 0.1 200)", "fontFamily", "", false},
+		{"gamut skips sRGB", ColorFallbackGamut, "oklch(70% 0.1 200)", "color", "", false},
+		{"gamut maps wide", ColorFallbackGamut, "oklch(49.12% 0.309 275.75)", "color", "#4a00ff", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := colorFallback(tt.mode, tt.value, tt.tokenType)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("colorFallback(%q, %q) = %q, %v, want %q, %v", tt.mode, tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGenerators_ColorFallback(t *testing.T) {
+	t.Parallel()
+
+	base := map[string]any{
+		"color.primary": "oklch(49.12% 0.309 275.75)",
+		"color.plain":   "#3b82f6",
+	}
+	dark := map[string]any{
+		"color.primary": "oklch(70% 0.35 150)",
+		"color.plain":   "#3b82f6",
+	}
+	ctx := &GenerationContext{
+		ResolvedTokens: base,
+		Themes: map[string]ThemeContext{
+			"dark": {ResolvedTokens: dark, DiffTokens: tokens.Diff(dark, base)},
+		},
+		PropertyTokens: []tokens.PropertyToken{{
+			Path:         "color.primary",
+			Type:         "color",
+			CSSName:      "--color-primary",
+			CSSSyntax:    "<color>",
+			Inherits:     true,
+			InitialValue: "oklch(49.12% 0.309 275.75)",
+		}},
+	}
+
+	tests := []struct {
+		name     string
+		generate func(*GenerationContext) (string, error)
+		want     []string // nil: no fallbacks at all
+	}{
+		{"css supports", NewCSSGeneratorWithOptions(CSSOptions{ColorFallback: ColorFallbackSupports}).Generate, []string{
+			"  initial-value: #4a00ff;\n}\n\n@supports (color: oklch(0 0 0)) {\n  @property --color-primary {\n",
+			"    initial-value: oklch(49.12% 0.309 275.75);\n",
+			"  :root {\n    --color-plain: #3b82f6;\n    --color-primary: #4a00ff;\n  }\n" +
+				"  @supports (color: oklch(0 0 0)) {\n    :root {\n      --color-primary: oklch(49.12% 0.309 275.75);\n    }\n  }\n}\n",
+			"  [data-theme=\"dark\"] {\n    --color-primary: #00c248;\n  }\n" +
+				"  @supports (color: oklch(0 0 0)) {\n    [data-theme=\"dark\"] {\n      --color-primary: oklch(70% 0.35 150);\n    }\n  }\n",
+		}},
+		{"tailwind gamut", NewTailwindGeneratorWithOptions(CSSOptions{ColorFallback: ColorFallbackGamut}).Generate, []string{
+			"@media (color-gamut: p3) {\n  @property --color-primary {\n",
+			"@theme {\n  --color-plain: #3b82f6;\n  --color-primary: #4a00ff;\n}\n",
+			"@layer theme {\n  @media (color-gamut: p3) {\n    :root, :host {\n      --color-primary: oklch(49.12% 0.309 275.75);\n",
+			"  [data-theme=\"dark\"] {\n    --color-primary: #00c248;\n  }\n" +
+				"  @media (color-gamut: p3) {\n    [data-theme=\"dark\"] {\n      --color-primary: oklch(70% 0.35 150);\n",
+		}},
+		{"css off", NewCSSGenerator().Generate, nil},
+		{"tailwind off", NewTailwindGenerator().Generate, nil},
+	}
+
+	for _, tt := range tests {
+		output, err := tt.generate(ctx)
+		if err != nil {
+			t.Fatalf("%s: Generate failed: %v", tt.name, err)
+		}
+		if tt.want == nil {
+			if strings.Contains(output, "@supports") || strings.Contains(output, "color-gamut") || strings.Contains(output, "#4a00ff") {
+				t.Errorf("%s: fallbacks written without ColorFallback:\n%s", tt.name, output)
+			}
+			continue
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(output, want) {
+				t.Errorf("%s: output missing %q:\n%s", tt.name, want, output)
+			}
+		}
+	}
+}
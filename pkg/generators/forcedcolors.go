@@ -0,0 +1,120 @@
+// tokenctl/pkg/generators/forcedcolors.go
+package generators
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dmoose/tokenctl/pkg/tokens"
+)
+
+// forcedColorsQuery is the condition $forcedColors overrides apply under
+const forcedColorsQuery = "@media (forced-colors: active)"
+
+// forcedColorTokens returns the $forcedColors token overrides as a
+// forced-colors media block for a themes layer, or "" when there are
+// none. Base entries are declared on :root and on every [data-theme], so
+// a theme's own value for the token cannot win over them; a theme's
+// entries that differ from the base follow under its selector. The
+// caller writes the block after the theme variations.
+func forcedColorTokens(base map[string]string, selectors themeSelectors) string {
+	var sb strings.Builder
+	themes := selectors.themes
+
+	if len(base) > 0 {
+		selector := ":root"
+		if len(themes) > 0 {
+			selector = ":root, " + selectors.anyMode()
+		}
+		writeForcedColorRule(&sb, selector, base)
+	}
+
+	themeNames := make([]string, 0, len(themes))
+	for name := range themes {
+		themeNames = append(themeNames, name)
+	}
+	sortThemeNames(themeNames, selectors.defaultTheme)
+
+	for _, name := range themeNames {
+		// A combination's forced colors come from its parts' rules
+		if len(tokens.ThemeParts(name)) > 1 {
+			continue
+		}
+		own := make(map[string]string)
+		for path, color := range themes[name].ForcedColors {
+			if base[path] != color {
+				own[path] = color
+			}
+		}
+		if len(own) > 0 {
+			writeForcedColorRule(&sb, selectors.selector(name), own)
+		}
+	}
+
+	if sb.Len() == 0 {
+		return ""
+	}
+	return fmt.Sprintf("  %s {\n%s  }\n", forcedColorsQuery, sb.String())
+}
+
+// writeForcedColorRule writes one rule of token overrides inside the
+// forced-colors media block
+func writeForcedColorRule(sb *strings.Builder, selector string, overrides map[string]string) {
+	paths := make([]string, 0, len(overrides))
+	for path := range overrides {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	fmt.Fprintf(sb, "    %s {\n", selector)
+	for _, path := range paths {
+		fmt.Fprintf(sb, "      --%s: %s;\n", strings.ReplaceAll(path, ".", "-"), overrides[path])
+	}
+	sb.WriteString("    }\n")
+}
+
+// generateForcedColorComponents returns @layer components with each
+// component's $forcedColors properties under a forced-colors media
+// block, or "" when no component declares any. Nested & and : keys
+// become state selectors as they do in the component's base.
+func generateForcedColorComponents(components map[string]tokens.ComponentDefinition) string {
+	names := make([]string, 0, len(components))
+	for name, comp := range components {
+		if comp.Class != "" && len(comp.ForcedColors) > 0 {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	sb.WriteString("@layer components {\n")
+	fmt.Fprintf(&sb, "  %s {\n", forcedColorsQuery)
+	for _, name := range names {
+		comp := components[name]
+		props, states := tokens.SplitProperties(comp.ForcedColors)
+		if len(props) > 0 {
+			fmt.Fprintf(&sb, "    .%s {\n", comp.Class)
+			writeProperties(&sb, props, 6)
+			sb.WriteString("    }\n")
+		}
+
+		stateKeys := make([]string, 0, len(states))
+		for key := range states {
+			stateKeys = append(stateKeys, key)
+		}
+		sort.Strings(stateKeys)
+
+		for _, key := range stateKeys {
+			fmt.Fprintf(&sb, "    %s {\n", buildStateSelector(comp.Class, key))
+			writeProperties(&sb, states[key].Properties, 6)
+			sb.WriteString("    }\n")
+		}
+	}
+	sb.WriteString("  }\n")
+	sb.WriteString("}\n")
+	return sb.String()
+}
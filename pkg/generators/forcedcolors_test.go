@@ -0,0 +1,97 @@
+// tokenctl/pkg/generators/forcedcolors_test.go
+package generators
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dmoose/tokenctl/pkg/tokens"
+)
+
+func TestForcedColorTokens(t *testing.T) {
+	t.Parallel()
+	themes := map[string]ThemeContext{
+		"light": {ForcedColors: map[string]string{"color.primary": "Highlight", "color.base-100": "Canvas"}},
+		"dark":  {ForcedColors: map[string]string{"color.primary": "LinkText", "color.base-100": "Canvas"}},
+	}
+	got := forcedColorTokens(themes["light"].ForcedColors, newThemeSelectors(themes, "light", ""))
+	want := "  @media (forced-colors: active) {\n" +
+		"    :root, [data-theme] {\n      --color-base-100: Canvas;\n      --color-primary: Highlight;\n    }\n" +
+		"    [data-theme=\"dark\"] {\n      --color-primary: LinkText;\n    }\n" +
+		"  }\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	if got := forcedColorTokens(map[string]string{"color.primary": "Highlight"}, newThemeSelectors(nil, "light", "")); !strings.Contains(got, "    :root {\n") {
+		t.Errorf("without themes the base rule should target :root alone:\n%s", got)
+	}
+	if got := forcedColorTokens(nil, newThemeSelectors(nil, "light", "")); got != "" {
+		t.Errorf("expected no output without $forcedColors, got:\n%s", got)
+	}
+}
+
+func TestGenerators_ForcedColors(t *testing.T) {
+	t.Parallel()
+	base := map[string]any{"color.primary": "#3b82f6", "color.base-100": "#ffffff"}
+	dark := map[string]any{"color.primary": "#3b82f6", "color.base-100": "#1d232a"}
+	ctx := &GenerationContext{
+		ResolvedTokens: base,
+		ForcedColors:   map[string]string{"color.primary": "Highlight", "color.base-100": "Canvas"},
+		Themes: map[string]ThemeContext{
+			"light": {ResolvedTokens: base, DiffTokens: map[string]any{},
+				ForcedColors: map[string]string{"color.primary": "Highlight", "color.base-100": "Canvas"}},
+			"dark": {ResolvedTokens: dark, DiffTokens: tokens.Diff(dark, base),
+				ForcedColors: map[string]string{"color.primary": "LinkText", "color.base-100": "Canvas"}},
+		},
+		Components: map[string]tokens.ComponentDefinition{
+			"button": {
+				Class: "btn",
+				Base:  map[string]any{"background-color": "{color.primary}"},
+				ForcedColors: map[string]any{
+					"border":          "1px solid {color.border}",
+					"&:focus-visible": map[string]any{"outline": "2px solid Highlight"},
+				},
+			},
+			"card": {Class: "card", Base: map[string]any{"padding": "1rem"}},
+		},
+	}
+	css, err := NewCSSGenerator().Generate(ctx)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	tw, err := NewTailwindGenerator().Generate(ctx)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	components := "@layer components {\n  @media (forced-colors: active) {\n" +
+		"    .btn {\n      border: 1px solid var(--color-border);\n    }\n" +
+		"    .btn:focus-visible {\n      outline: 2px solid Highlight;\n    }\n" +
+		"  }\n}\n"
+	tests := []struct {
+		name   string
+		output string
+		layer  string
+	}{
+		{"css", css, "@layer themes {\n  @media (forced-colors: active) {\n"},
+		{"tailwind", tw, "@layer base {\n  @media (forced-colors: active) {\n"},
+	}
+	for _, tt := range tests {
+		tokensAt := strings.Index(tt.output, tt.layer)
+		if tokensAt < 0 {
+			t.Errorf("%s: output missing %q:\n%s", tt.name, tt.layer, tt.output)
+			continue
+		}
+		// Forced values must follow the theme blocks they override
+		if themeAt := strings.Index(tt.output, "--color-base-100: #1d232a;"); themeAt > tokensAt {
+			t.Errorf("%s: forced colors written before the themes:\n%s", tt.name, tt.output)
+		}
+		if !strings.Contains(tt.output, components) {
+			t.Errorf("%s: output missing component overrides %q:\n%s", tt.name, components, tt.output)
+		}
+		if strings.Count(tt.output, "forced-colors") != 2 {
+			t.Errorf("%s: expected one token and one component block:\n%s", tt.name, tt.output)
+		}
+	}
+}
@@ -24,7 +24,10 @@ func resolveTokenReferences(value string) string {
 }
 
 // generatePropertyDeclarations creates @property declarations for typed tokens.
-func generatePropertyDeclarations(properties []tokens.PropertyToken) string {
+// With a color fallback mode, wide-gamut initial values are written as sRGB
+// and the declaration is repeated with the authored value under the
+// fallback condition; the last registration of a property wins.
+func generatePropertyDeclarations(properties []tokens.PropertyToken, colorFallbackMode string) string {
 	var sb strings.Builder
 
 	sorted := make([]tokens.PropertyToken, len(properties))
@@ -33,21 +36,43 @@ func generatePropertyDeclarations(properties []tokens.PropertyToken) string {
 		return sorted[i].Path < sorted[j].Path
 	})
 
+	var wide []tokens.PropertyToken
 	for _, prop := range sorted {
-		fmt.Fprintf(&sb, "@property %s {\n", prop.CSSName)
-		fmt.Fprintf(&sb, "  syntax: '%s';\n", prop.CSSSyntax)
-		if prop.Inherits {
-			sb.WriteString("  inherits: true;\n")
-		} else {
-			sb.WriteString("  inherits: false;\n")
+		initial := prop.InitialValue
+		if prop.CSSSyntax == "<color>" {
+			if fallback, ok := colorFallback(colorFallbackMode, initial, "color"); ok {
+				initial = fallback
+				wide = append(wide, prop)
+			}
+		}
+		writePropertyDeclaration(&sb, prop, initial, "")
+		sb.WriteString("\n")
+	}
+
+	if len(wide) > 0 {
+		fmt.Fprintf(&sb, "%s {\n", fallbackCondition(colorFallbackMode))
+		for _, prop := range wide {
+			writePropertyDeclaration(&sb, prop, prop.InitialValue, "  ")
 		}
-		fmt.Fprintf(&sb, "  initial-value: %s;\n", prop.InitialValue)
 		sb.WriteString("}\n\n")
 	}
 
 	return sb.String()
 }
 
+// writePropertyDeclaration writes one @property rule at the given indent
+func writePropertyDeclaration(sb *strings.Builder, prop tokens.PropertyToken, initial, pad string) {
+	fmt.Fprintf(sb, "%s@property %s {\n", pad, prop.CSSName)
+	fmt.Fprintf(sb, "%s  syntax: '%s';\n", pad, prop.CSSSyntax)
+	if prop.Inherits {
+		fmt.Fprintf(sb, "%s  inherits: true;\n", pad)
+	} else {
+		fmt.Fprintf(sb, "%s  inherits: false;\n", pad)
+	}
+	fmt.Fprintf(sb, "%s  initial-value: %s;\n", pad, initial)
+	fmt.Fprintf(sb, "%s}\n", pad)
+}
+
 // buildStateSelector converts a state key to a CSS selector.
 //
 // A state key may be a selector *list*: "& a, & b" is two selectors, and
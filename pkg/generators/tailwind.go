@@ -19,31 +19,63 @@ type GenerationContext struct {
 	Keyframes          []tokens.KeyframeDefinition           // CSS @keyframes animations
 	Breakpoints        map[string]string                     // Breakpoint definitions (name -> min-width)
 	ResponsiveTokens   []tokens.ResponsiveToken              // Tokens with responsive overrides
+	ReducedMotion      []tokens.ReducedMotionToken           // Duration tokens with $reducedMotion overrides
 	ContainerOverrides []tokens.ContainerOverride            // Component container query overrides
+	ForcedColors       map[string]string                     // Base $forcedColors: token path -> system color
 }
 
 // ThemeContext provides theme-specific generation data
 type ThemeContext struct {
-	Dict           *tokens.Dictionary // Full theme dictionary
-	ResolvedTokens map[string]any     // Resolved tokens for this theme
-	DiffTokens     map[string]any     // Only tokens that differ from base
+	Dict           *tokens.Dictionary                    // Full theme dictionary
+	ResolvedTokens map[string]any                        // Resolved tokens for this theme
+	DiffTokens     map[string]any                        // Only tokens that differ from base
+	ColorScheme    string                                // $colorScheme: "light", "dark" or ""
+	ForcedColors   map[string]string                     // $forcedColors, base entries included
+	Selectors      []string                              // $selector from the theme file, if any
+	Media          string                                // $media from the theme file, if any
+	Components     map[string]tokens.ComponentDefinition // Components as the theme resolves them
+}
+
+// CSSOptions configures Tailwind and pure CSS generation
+type CSSOptions struct {
+	// ColorFallback, when set, gives color custom properties an sRGB
+	// fallback for browsers or screens that cannot show the authored
+	// value. Empty writes colors as they are.
+	ColorFallback string
+
+	// ColorScheme is ColorSchemeAttribute (the default when empty),
+	// ColorSchemeMedia or ColorSchemeLightDark
+	ColorScheme string
+
+	// ThemeSelector is the selector template for themes without a
+	// $selector, {name} standing for the theme name. Empty uses
+	// DefaultThemeSelector.
+	ThemeSelector string
 }
 
 // TailwindGenerator generates Tailwind 4 CSS
 type TailwindGenerator struct {
+	ColorFallback string // Optional: ColorFallbackSupports or ColorFallbackGamut
+	ColorScheme   string // Optional: ColorSchemeMedia or ColorSchemeLightDark
+	ThemeSelector string // Optional: selector template, see CSSOptions
 }
 
 func NewTailwindGenerator() *TailwindGenerator {
 	return &TailwindGenerator{}
 }
 
+// NewTailwindGeneratorWithOptions creates a Tailwind generator with the given options
+func NewTailwindGeneratorWithOptions(opts CSSOptions) *TailwindGenerator {
+	return &TailwindGenerator{ColorFallback: opts.ColorFallback, ColorScheme: opts.ColorScheme, ThemeSelector: opts.ThemeSelector}
+}
+
 // Generate creates complete Tailwind CSS from generation context
 func (g *TailwindGenerator) Generate(ctx *GenerationContext) (string, error) {
 	var sb strings.Builder
 
 	// 1. @property declarations (before @theme for type registration)
 	if len(ctx.PropertyTokens) > 0 {
-		sb.WriteString(generatePropertyDeclarations(ctx.PropertyTokens))
+		sb.WriteString(generatePropertyDeclarations(ctx.PropertyTokens, g.ColorFallback))
 	}
 
 	// 2. @keyframes declarations (global animations)
@@ -53,19 +85,21 @@ func (g *TailwindGenerator) Generate(ctx *GenerationContext) (string, error) {
 	}
 
 	// 3. Import and base @theme block
-	baseTheme, err := g.generateBaseTheme(ctx.ResolvedTokens)
+	baseTheme, err := g.generateBaseTheme(ctx.ResolvedTokens, tokenTypes(ctx.BaseDict))
 	if err != nil {
 		return "", fmt.Errorf("failed to generate base theme: %w", err)
 	}
 	sb.WriteString(baseTheme)
 
+	defaultTheme := ctx.DefaultTheme
+	if defaultTheme == "" {
+		defaultTheme = DefaultThemeName
+	}
+	themes := cascadeCombinations(ctx.Themes, ctx.ResolvedTokens, defaultTheme)
+
 	// 4. Theme variations in @layer base
-	if len(ctx.Themes) > 0 {
-		defaultTheme := ctx.DefaultTheme
-		if defaultTheme == "" {
-			defaultTheme = DefaultThemeName
-		}
-		themeVariations, err := g.generateThemeVariations(ctx.Themes, defaultTheme)
+	if len(themes) > 0 {
+		themeVariations, err := g.generateThemeVariations(themes, defaultTheme)
 		if err != nil {
 			return "", fmt.Errorf("failed to generate theme variations: %w", err)
 		}
@@ -73,15 +107,31 @@ func (g *TailwindGenerator) Generate(ctx *GenerationContext) (string, error) {
 		sb.WriteString(themeVariations)
 	}
 
-	// 5. Components in @layer components (always output for consistency)
-	components, err := g.generateComponents(ctx.Components)
+	// 5. Forced colors token overrides, after the themes they must win over
+	if forced := forcedColorTokens(ctx.ForcedColors, newThemeSelectors(themes, defaultTheme, g.ThemeSelector)); forced != "" {
+		sb.WriteString("\n@layer base {\n")
+		sb.WriteString(forced)
+		sb.WriteString("}\n")
+	}
+
+	// 6. Components in @layer components (always output for consistency),
+	// ending with the theme component overrides scoped under each theme's
+	// selector
+	themeRules := themeComponentRules(ctx.Themes, ctx.Components, newThemeSelectors(ctx.Themes, defaultTheme, g.ThemeSelector), g.ColorScheme)
+	components, err := g.generateComponents(ctx.Components, themeRules)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate components: %w", err)
 	}
 	sb.WriteString("\n")
 	sb.WriteString(components)
 
-	// 6. Responsive overrides via media queries
+	// 7. Component forced colors overrides
+	if forced := generateForcedColorComponents(ctx.Components); forced != "" {
+		sb.WriteString("\n")
+		sb.WriteString(forced)
+	}
+
+	// 8. Responsive overrides via media queries
 	if len(ctx.ResponsiveTokens) > 0 {
 		responsiveCSS := tokens.GenerateResponsiveCSS(ctx.Breakpoints, ctx.ResponsiveTokens)
 		if responsiveCSS != "" {
@@ -90,7 +140,13 @@ func (g *TailwindGenerator) Generate(ctx *GenerationContext) (string, error) {
 		}
 	}
 
-	// 7. Container query overrides
+	// 9. Reduced motion overrides
+	if len(ctx.ReducedMotion) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(tokens.GenerateReducedMotionCSS(ctx.ReducedMotion))
+	}
+
+	// 10. Container query overrides
 	if len(ctx.ContainerOverrides) > 0 {
 		containerCSS := GenerateContainerCSS(ctx.ContainerOverrides)
 		if containerCSS != "" {
@@ -103,7 +159,7 @@ func (g *TailwindGenerator) Generate(ctx *GenerationContext) (string, error) {
 }
 
 // generateBaseTheme creates the root @theme block with base tokens
-func (g *TailwindGenerator) generateBaseTheme(resolvedTokens map[string]any) (string, error) {
+func (g *TailwindGenerator) generateBaseTheme(resolvedTokens map[string]any, types map[string]string) (string, error) {
 	var sb strings.Builder
 	sb.WriteString("@import \"tailwindcss\";\n\n")
 	sb.WriteString("@theme {\n")
@@ -115,19 +171,28 @@ func (g *TailwindGenerator) generateBaseTheme(resolvedTokens map[string]any) (st
 	}
 	sort.Strings(keys)
 
+	fallbacks := fallbackBlock{mode: g.ColorFallback}
 	for _, path := range keys {
 		value := resolvedTokens[path]
-		// Skip non-primitive values (shouldn't happen in resolved tokens, but defensive)
-		if _, ok := value.(map[string]any); ok {
+		// Skip object values that have no CSS form (composites serialize)
+		cssValue, ok := serializeTokenValue(value, types[path])
+		if !ok {
 			continue
 		}
 
 		cssVar := strings.ReplaceAll(path, ".", "-")
-		cssValue := serializeValueForCSS(value)
-		fmt.Fprintf(&sb, "  --%s: %s;\n", cssVar, cssValue)
+		fmt.Fprintf(&sb, "  --%s: %s;\n", cssVar, fallbacks.value(cssVar, cssValue, types[path]))
 	}
 
 	sb.WriteString("}\n\n")
+
+	// @theme accepts only custom properties, so wide-gamut values are
+	// restated in the layer Tailwind emits it into
+	if len(fallbacks.decls) > 0 {
+		sb.WriteString("@layer theme {\n")
+		fallbacks.write(&sb, ":root, :host", 2)
+		sb.WriteString("}\n\n")
+	}
 	return sb.String(), nil
 }
 
@@ -142,35 +207,45 @@ func (g *TailwindGenerator) generateThemeVariations(themes map[string]ThemeConte
 		themeNames = append(themeNames, name)
 	}
 	sortThemeNames(themeNames, defaultTheme)
+	selectors := newThemeSelectors(themes, defaultTheme, g.ThemeSelector)
+
+	var lightDark lightDarkPlan
+	if g.ColorScheme == ColorSchemeLightDark {
+		plan, err := planLightDark(selectors)
+		if err != nil {
+			return "", err
+		}
+		lightDark = plan
+		lightDark.write(&sb, selectors, g.ColorFallback)
+	}
 
 	for _, themeName := range themeNames {
 		themeCtx := themes[themeName]
-
-		fmt.Fprintf(&sb, "  %s {\n", themeSelector(themeName, defaultTheme))
-
-		// Sort token keys for deterministic output
-		tokenKeys := make([]string, 0, len(themeCtx.DiffTokens))
-		for key := range themeCtx.DiffTokens {
-			tokenKeys = append(tokenKeys, key)
+		scheme := lightDark.scheme(themeName, themeCtx)
+		if lightDark.covered(themeName, themeCtx) && scheme == "" {
+			continue
 		}
-		sort.Strings(tokenKeys)
+		writeInMedia(&sb, selectors.media(themeName), 2, func(indent int) {
+			writeThemeRule(&sb, selectors.selector(themeName), themeCtx, scheme, lightDark.skip(themeName), g.ColorFallback, indent)
+		})
+	}
 
-		for _, key := range tokenKeys {
-			val := themeCtx.DiffTokens[key]
-			cssVar := strings.ReplaceAll(key, ".", "-")
-			cssValue := serializeValueForCSS(val)
-			fmt.Fprintf(&sb, "    --%s: %s;\n", cssVar, cssValue)
+	switch g.ColorScheme {
+	case ColorSchemeMedia:
+		if err := writeSchemeMedia(&sb, selectors, g.ColorFallback); err != nil {
+			return "", err
 		}
-
-		sb.WriteString("  }\n")
+	case ColorSchemeLightDark:
+		lightDark.writeSchemeMedia(&sb, selectors, g.ColorFallback)
 	}
 
 	sb.WriteString("}\n")
 	return sb.String(), nil
 }
 
-// generateComponents creates @layer components with component styles
-func (g *TailwindGenerator) generateComponents(components map[string]tokens.ComponentDefinition) (string, error) {
+// generateComponents creates @layer components with component styles,
+// closing it with themeRules
+func (g *TailwindGenerator) generateComponents(components map[string]tokens.ComponentDefinition, themeRules string) (string, error) {
 	var sb strings.Builder
 	sb.WriteString("@layer components {\n")
 
@@ -269,6 +344,7 @@ func (g *TailwindGenerator) generateComponents(components map[string]tokens.Comp
 		}
 	}
 
+	sb.WriteString(themeRules)
 	sb.WriteString("}\n")
 	return sb.String(), nil
 }
@@ -278,11 +354,11 @@ func (g *TailwindGenerator) generateComponents(components map[string]tokens.Comp
 // GenerateFromResolved is deprecated - use Generate with GenerationContext
 // Kept for backwards compatibility with existing tests
 func (g *TailwindGenerator) GenerateFromResolved(tokens map[string]any) (string, error) {
-	return g.generateBaseTheme(tokens)
+	return g.generateBaseTheme(tokens, nil)
 }
 
 // GenerateComponents is deprecated - use Generate with GenerationContext
 // Kept for backwards compatibility with existing tests
 func (g *TailwindGenerator) GenerateComponents(components map[string]tokens.ComponentDefinition) (string, error) {
-	return g.generateComponents(components)
+	return g.generateComponents(components, "")
 }
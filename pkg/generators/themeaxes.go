@@ -0,0 +1,85 @@
+// tokenctl/pkg/generators/themeaxes.go
+package generators
+
+import (
+	"reflect"
+
+	"github.com/dmoose/tokenctl/pkg/tokens"
+)
+
+// cascadeCombinations narrows each theme combination's DiffTokens to the
+// tokens the rules written before it do not already produce, and drops
+// combinations left with nothing to declare. A combination's own block
+// is only needed where composing its parts differs from the cascade of
+// their separate blocks, for example an alias of a token two axes set.
+// Its color-scheme is left to its mode theme's block.
+func cascadeCombinations(themes map[string]ThemeContext, base map[string]any, defaultTheme string) map[string]ThemeContext {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sortThemeNames(names, defaultTheme)
+
+	result := make(map[string]ThemeContext, len(themes))
+	var written []string
+	for _, name := range names {
+		themeCtx := themes[name]
+		if len(tokens.ThemeParts(name)) > 1 {
+			own := make(map[string]any)
+			for path, value := range themeCtx.ResolvedTokens {
+				inherited, ok := cascadeValue(name, written, themes, defaultTheme, func(rule string) (any, bool) {
+					v, ok := result[rule].DiffTokens[path]
+					return v, ok
+				})
+				if !ok {
+					inherited = base[path]
+				}
+				if !reflect.DeepEqual(value, inherited) {
+					own[path] = value
+				}
+			}
+			if len(own) == 0 {
+				continue
+			}
+			themeCtx.DiffTokens = own
+			themeCtx.ColorScheme = ""
+		}
+		result[name] = themeCtx
+		written = append(written, name)
+	}
+	return result
+}
+
+// cascadeValue returns the value the rules in written, in output order,
+// give theme, as lookup reports each rule's declaration, and false when
+// none declares one. Rules are sorted by how many attributes they select
+// on, so the last matching rule that declares a value wins; the default
+// theme matches everywhere through :root unless a $selector replaces it.
+func cascadeValue(theme string, written []string, themes map[string]ThemeContext, defaultTheme string, lookup func(rule string) (any, bool)) (any, bool) {
+	parts := make(map[tokens.ThemePart]bool)
+	for _, part := range tokens.ThemeParts(theme) {
+		parts[part] = true
+	}
+
+	for i := len(written) - 1; i >= 0; i-- {
+		name := written[i]
+		everywhere := name == defaultTheme && len(themes[name].Selectors) == 0
+		if !everywhere && !partsWithin(name, parts) {
+			continue
+		}
+		if value, ok := lookup(name); ok {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// partsWithin reports whether every part of name is in parts
+func partsWithin(name string, parts map[tokens.ThemePart]bool) bool {
+	for _, part := range tokens.ThemeParts(name) {
+		if !parts[part] {
+			return false
+		}
+	}
+	return true
+}
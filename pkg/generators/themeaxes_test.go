@@ -0,0 +1,85 @@
+// tokenctl/pkg/generators/themeaxes_test.go
+package generators
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dmoose/tokenctl/pkg/tokens"
+)
+
+func TestThemeSelector_Axes(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"light", `:root, [data-theme="light"]`},
+		{"dark", `[data-theme="dark"]`},
+		{"brand/acme", `[data-brand="acme"]`},
+		{"dark+brand/acme", `[data-brand="acme"][data-theme="dark"]`},
+		{"dark+brand/acme+density/compact", `[data-brand="acme"][data-density="compact"][data-theme="dark"]`},
+		{"light+brand/acme", `[data-brand="acme"]:not([data-theme]), [data-brand="acme"][data-theme="light"]`},
+		{"brand/acme+density/compact", `[data-brand="acme"][data-density="compact"]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := newThemeSelectors(nil, "light", "").selector(tt.name); got != tt.want {
+				t.Errorf("selector(%q) = %s, want %s", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCascadeCombinations(t *testing.T) {
+	t.Parallel()
+	base := map[string]any{"color.primary": "#3b82f6", "color.link": "#3b82f6", "color.text": "#111111"}
+	theme := func(resolved map[string]any) ThemeContext {
+		merged := make(map[string]any, len(base))
+		for k, v := range base {
+			merged[k] = v
+		}
+		for k, v := range resolved {
+			merged[k] = v
+		}
+		return ThemeContext{ResolvedTokens: merged, DiffTokens: tokens.Diff(merged, base), ColorScheme: "dark"}
+	}
+	themes := map[string]ThemeContext{
+		"light":      theme(nil),
+		"dark":       theme(map[string]any{"color.text": "#eeeeee", "color.link": "#93c5fd"}),
+		"brand/acme": theme(map[string]any{"color.primary": "#e11d48", "color.link": "#e11d48"}),
+		// dark pins the link color, so composing keeps it over the brand's
+		"dark+brand/acme": theme(map[string]any{"color.text": "#eeeeee", "color.primary": "#e11d48", "color.link": "#93c5fd"}),
+		// the cascade of light and acme already gives these values
+		"light+brand/acme": theme(map[string]any{"color.primary": "#e11d48", "color.link": "#e11d48"}),
+	}
+
+	got := cascadeCombinations(themes, base, "light")
+	if _, ok := got["light+brand/acme"]; ok {
+		t.Errorf("combination matching the cascade should be dropped: %v", got["light+brand/acme"].DiffTokens)
+	}
+	combo, ok := got["dark+brand/acme"]
+	if !ok {
+		t.Fatal("dark+brand/acme dropped")
+	}
+	if want := map[string]any{"color.link": "#93c5fd"}; !reflect.DeepEqual(combo.DiffTokens, want) {
+		t.Errorf("DiffTokens = %v, want %v", combo.DiffTokens, want)
+	}
+	if combo.ColorScheme != "" {
+		t.Errorf("combination should leave color-scheme to its mode theme, got %q", combo.ColorScheme)
+	}
+	if !reflect.DeepEqual(got["brand/acme"], themes["brand/acme"]) {
+		t.Error("single-axis themes must pass through unchanged")
+	}
+
+	css, err := NewCSSGenerator().Generate(&GenerationContext{ResolvedTokens: base, Themes: themes, DefaultTheme: "light"})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	want := "  [data-brand=\"acme\"][data-theme=\"dark\"] {\n    --color-link: #93c5fd;\n  }\n"
+	if !strings.Contains(css, want) || strings.Index(css, want) < strings.Index(css, `[data-brand="acme"] {`) {
+		t.Errorf("combination block missing or before its parts:\n%s", css)
+	}
+}
@@ -0,0 +1,221 @@
+// tokenctl/pkg/generators/themecomponents.go
+package generators
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/dmoose/tokenctl/pkg/tokens"
+)
+
+// componentRule is one rule generateComponents writes for a component
+type componentRule struct {
+	selector   string
+	properties map[string]any
+}
+
+// componentRules flattens each component into the rules generateComponents
+// writes for it, in the order it writes them: the base class and its
+// nested states, then variants with their states, sizes, and states with
+// their pseudo-states, each group by name
+func componentRules(components map[string]tokens.ComponentDefinition) map[string][]componentRule {
+	rules := make(map[string][]componentRule, len(components))
+	for name, comp := range components {
+		var list []componentRule
+		add := func(class string, props map[string]any, states map[string]tokens.State) {
+			if class == "" {
+				return
+			}
+			list = append(list, componentRule{"." + class, props})
+			for _, key := range sortedKeys(states) {
+				list = append(list, componentRule{buildStateSelector(class, key), states[key].Properties})
+			}
+		}
+
+		props, states := tokens.SplitProperties(comp.Base)
+		add(comp.Class, props, states)
+		for _, key := range sortedKeys(comp.Variants) {
+			add(comp.Variants[key].Class, comp.Variants[key].Properties, comp.Variants[key].States)
+		}
+		for _, key := range sortedKeys(comp.Sizes) {
+			add(comp.Sizes[key].Class, comp.Sizes[key].Properties, nil)
+		}
+		for _, key := range sortedKeys(comp.States) {
+			add(comp.States[key].Class, comp.States[key].Properties, comp.States[key].States)
+		}
+		rules[name] = list
+	}
+	return rules
+}
+
+// ruleIndex maps every rule's selector to its properties
+func ruleIndex(rules map[string][]componentRule) map[string]map[string]any {
+	index := make(map[string]map[string]any)
+	for _, list := range rules {
+		for _, rule := range list {
+			index[rule.selector] = rule.properties
+		}
+	}
+	return index
+}
+
+// ComponentOverrides returns the component declarations a theme changes
+// or adds relative to the base components, selector -> properties.
+// Properties the theme leaves as they are, and rules left with none,
+// are omitted.
+func ComponentOverrides(theme, base map[string]tokens.ComponentDefinition) map[string]map[string]any {
+	return diffRules(ruleIndex(componentRules(theme)), ruleIndex(componentRules(base)))
+}
+
+// diffRules returns the declarations in rules that differ from base
+func diffRules(rules, base map[string]map[string]any) map[string]map[string]any {
+	diff := make(map[string]map[string]any)
+	for selector, props := range rules {
+		for prop, value := range props {
+			if strings.HasPrefix(prop, "$") {
+				continue
+			}
+			if baseValue, ok := base[selector][prop]; ok && reflect.DeepEqual(value, baseValue) {
+				continue
+			}
+			if diff[selector] == nil {
+				diff[selector] = make(map[string]any)
+			}
+			diff[selector][prop] = value
+		}
+	}
+	return diff
+}
+
+// themeComponentOverrides returns each theme's component overrides in
+// output order, leaving out themes without any. A combination keeps only
+// the declarations its parts' overrides do not already produce, the
+// same way cascadeCombinations narrows its tokens.
+func themeComponentOverrides(themes map[string]ThemeContext, base map[string]tokens.ComponentDefinition, defaultTheme string) ([]string, map[string]map[string]map[string]any) {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sortThemeNames(names, defaultTheme)
+
+	baseRules := ruleIndex(componentRules(base))
+	overrides := make(map[string]map[string]map[string]any)
+	var written []string
+	for _, name := range names {
+		if themes[name].Components == nil {
+			continue
+		}
+		own := diffRules(ruleIndex(componentRules(themes[name].Components)), baseRules)
+		if len(tokens.ThemeParts(name)) > 1 {
+			for selector, props := range own {
+				for prop, value := range props {
+					inherited, ok := cascadeValue(name, written, themes, defaultTheme, func(rule string) (any, bool) {
+						v, ok := overrides[rule][selector][prop]
+						return v, ok
+					})
+					if !ok {
+						inherited = baseRules[selector][prop]
+					}
+					if reflect.DeepEqual(value, inherited) {
+						delete(props, prop)
+					}
+				}
+				if len(props) == 0 {
+					delete(own, selector)
+				}
+			}
+		}
+		if len(own) == 0 {
+			continue
+		}
+		overrides[name] = own
+		written = append(written, name)
+	}
+	return written, overrides
+}
+
+// themeComponentRules returns each theme's component overrides scoped
+// under its selector, for the caller to write at the end of the
+// components layer, or "" when no theme has any. A scoped rule is more
+// specific than the base rule it modifies, and so would also beat the
+// component's later rules, such as a variant or a :hover state; any
+// later rule that sets an overridden property, or its shorthand or one
+// of its longhands, is restated in scope so it still wins. Under
+// --color-scheme=media or light-dark, the overrides of the theme the OS
+// scheme selects also apply while no theme is selected explicitly, as
+// its tokens do.
+func themeComponentRules(themes map[string]ThemeContext, base map[string]tokens.ComponentDefinition, selectors themeSelectors, colorScheme string) string {
+	names, overrides := themeComponentOverrides(themes, base, selectors.defaultTheme)
+	if len(names) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	for _, name := range names {
+		writeInMedia(&sb, selectors.media(name), 2, func(indent int) {
+			writeScopedOverrides(&sb, selectors.selector(name), themes[name].Components, overrides[name], indent)
+		})
+	}
+	if colorScheme == ColorSchemeMedia || colorScheme == ColorSchemeLightDark {
+		scheme, name := schemeMediaTheme(selectors)
+		if name != "" && overrides[name] != nil {
+			fmt.Fprintf(&sb, "  @media (prefers-color-scheme: %s) {\n", scheme)
+			writeScopedOverrides(&sb, ":root"+selectors.unset(), themes[name].Components, overrides[name], 4)
+			sb.WriteString("  }\n")
+		}
+	}
+	return sb.String()
+}
+
+// writeScopedOverrides writes one theme's overrides under scope, with the
+// later rules that must be restated, in the order generateComponents
+// writes the rules they modify
+func writeScopedOverrides(sb *strings.Builder, scope string, components map[string]tokens.ComponentDefinition, overrides map[string]map[string]any, indent int) {
+	pad := strings.Repeat(" ", indent)
+	rules := componentRules(components)
+	for _, comp := range sortedKeys(rules) {
+		overridden := make(map[string]bool)
+		for _, rule := range rules[comp] {
+			props := make(map[string]any)
+			for prop, value := range rule.properties {
+				if overlapsAny(prop, overridden) {
+					props[prop] = value
+				}
+			}
+			for prop, value := range overrides[rule.selector] {
+				props[prop] = value
+				overridden[prop] = true
+			}
+			if len(props) == 0 {
+				continue
+			}
+			fmt.Fprintf(sb, "%s%s {\n", pad, scopeSelector(scope, rule.selector))
+			writeProperties(sb, props, indent+2)
+			fmt.Fprintf(sb, "%s}\n", pad)
+		}
+	}
+}
+
+// overlapsAny reports whether prop is one of props, or a shorthand or
+// longhand of one, such as border and border-color
+func overlapsAny(prop string, props map[string]bool) bool {
+	for other := range props {
+		if prop == other || strings.HasPrefix(prop, other+"-") || strings.HasPrefix(other, prop+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// scopeSelector places every selector in rule under every selector in
+// scope, as descendants
+func scopeSelector(scope, rule string) string {
+	var scoped []string
+	for _, outer := range splitSelectorList(scope) {
+		for _, inner := range splitSelectorList(rule) {
+			scoped = append(scoped, outer+" "+inner)
+		}
+	}
+	return strings.Join(scoped, ", ")
+}
@@ -0,0 +1,155 @@
+// tokenctl/pkg/generators/themecomponents_test.go
+package generators
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dmoose/tokenctl/pkg/tokens"
+)
+
+func TestComponentOverrides(t *testing.T) {
+	t.Parallel()
+	base := map[string]tokens.ComponentDefinition{
+		"card": {Class: "card", Base: map[string]any{
+			"padding": "1rem",
+			"border":  "1px solid var(--color-border)",
+			"&:hover": map[string]any{"border-color": "var(--color-primary)"},
+		}},
+	}
+	tests := []struct {
+		name  string
+		theme map[string]any // the card's base as the theme resolves it
+		want  map[string]map[string]any
+	}{
+		{"unchanged", base["card"].Base, map[string]map[string]any{}},
+		{
+			"changed and added declarations",
+			map[string]any{"padding": "1rem", "border": "2px solid var(--color-border)", "box-shadow": "none"},
+			map[string]map[string]any{".card": {"border": "2px solid var(--color-border)", "box-shadow": "none"}},
+		},
+		{
+			"nested state",
+			map[string]any{"padding": "1rem", "border": "1px solid var(--color-border)", "&:hover": map[string]any{"border-color": "var(--color-accent)"}},
+			map[string]map[string]any{".card:hover": {"border-color": "var(--color-accent)"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			theme := map[string]tokens.ComponentDefinition{"card": {Class: "card", Base: tt.theme}}
+			if got := ComponentOverrides(theme, base); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ComponentOverrides() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScopeSelector(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		scope, rule, want string
+	}{
+		{`[data-theme="dark"]`, ".card", `[data-theme="dark"] .card`},
+		{`[data-theme="dark"]`, ".btn:hover, .btn:focus", `[data-theme="dark"] .btn:hover, [data-theme="dark"] .btn:focus`},
+		{".dark, :host([theme=dark])", ".card", ".dark .card, :host([theme=dark]) .card"},
+	}
+	for _, tt := range tests {
+		if got := scopeSelector(tt.scope, tt.rule); got != tt.want {
+			t.Errorf("scopeSelector(%q, %q) = %q, want %q", tt.scope, tt.rule, got, tt.want)
+		}
+	}
+}
+
+func TestThemeComponentRules(t *testing.T) {
+	t.Parallel()
+	card := func(border, radius string) map[string]tokens.ComponentDefinition {
+		props := map[string]any{"padding": "1rem", "border": border, "&:hover": map[string]any{"border-color": "var(--color-primary)"}}
+		if radius != "" {
+			props["border-radius"] = radius
+		}
+		return map[string]tokens.ComponentDefinition{"card": {
+			Class: "card", Base: props,
+			Variants: map[string]tokens.VariantDef{"flat": {Class: "card-flat", Properties: map[string]any{"border": "none"}}},
+		}}
+	}
+	base := card("1px solid var(--color-border)", "")
+	themes := map[string]ThemeContext{
+		"light":           {ColorScheme: tokens.ColorSchemeLight, Components: base},
+		"dark":            {ColorScheme: tokens.ColorSchemeDark, Components: card("2px solid var(--color-border)", "")},
+		"brand/acme":      {Components: card("1px solid var(--color-border)", "0")},
+		"dark+brand/acme": {Components: card("2px solid var(--color-border)", "0")},
+	}
+
+	// The dark border is restated on the later hover and variant rules so
+	// they keep winning; the combination adds nothing its parts don't
+	dark := "  [data-theme=\"dark\"] .card {\n    border: 2px solid var(--color-border);\n  }\n" +
+		"  [data-theme=\"dark\"] .card:hover {\n    border-color: var(--color-primary);\n  }\n" +
+		"  [data-theme=\"dark\"] .card-flat {\n    border: none;\n  }\n"
+	acme := "  [data-brand=\"acme\"] .card {\n    border-radius: 0;\n  }\n" +
+		"  [data-brand=\"acme\"] .card-flat {\n    border: none;\n  }\n"
+	osDark := "  @media (prefers-color-scheme: dark) {\n" +
+		"    :root:not([data-theme]) .card {\n      border: 2px solid var(--color-border);\n    }\n" +
+		"    :root:not([data-theme]) .card:hover {\n      border-color: var(--color-primary);\n    }\n" +
+		"    :root:not([data-theme]) .card-flat {\n      border: none;\n    }\n" +
+		"  }\n"
+
+	tests := []struct {
+		colorScheme string
+		want        string
+	}{
+		{"", dark + acme},
+		{ColorSchemeMedia, dark + acme + osDark},
+		{ColorSchemeLightDark, dark + acme + osDark},
+	}
+	for _, tt := range tests {
+		got := themeComponentRules(themes, base, newThemeSelectors(themes, "light", ""), tt.colorScheme)
+		if got != tt.want {
+			t.Errorf("color scheme %q: got:\n%s\nwant:\n%s", tt.colorScheme, got, tt.want)
+		}
+	}
+
+	if got := themeComponentRules(map[string]ThemeContext{"light": themes["light"]}, base, newThemeSelectors(themes, "light", ""), ""); got != "" {
+		t.Errorf("expected no output without overrides, got:\n%s", got)
+	}
+}
+
+func TestGenerators_ThemeComponents(t *testing.T) {
+	t.Parallel()
+	ctx := func() *GenerationContext {
+		base := map[string]tokens.ComponentDefinition{"card": {Class: "card", Base: map[string]any{"border": "1px solid #e5e7eb"}}}
+		return &GenerationContext{
+			ResolvedTokens: map[string]any{"color.border": "#e5e7eb"},
+			Themes: map[string]ThemeContext{
+				"light": {DiffTokens: map[string]any{}, Components: base},
+				"dark": {DiffTokens: map[string]any{}, Components: map[string]tokens.ComponentDefinition{
+					"card": {Class: "card", Base: map[string]any{"border": "2px solid #374151"}},
+				}},
+			},
+			Components: base,
+		}
+	}
+	css, err := NewCSSGenerator().Generate(ctx())
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	tw, err := NewTailwindGenerator().Generate(ctx())
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	scoped := "  [data-theme=\"dark\"] .card {\n    border: 2px solid #374151;\n  }\n}\n"
+	for name, output := range map[string]string{"css": css, "tailwind": tw} {
+		// Written at the end of the one components layer, after the base
+		// rules they modify
+		layer := strings.Index(output, "@layer components {\n")
+		if layer < 0 || strings.Count(output, "@layer components {") != 1 {
+			t.Errorf("%s: expected a single components layer:\n%s", name, output)
+			continue
+		}
+		if !strings.Contains(output[layer:], ".card {\n    border: 1px solid #e5e7eb;\n  }\n") || !strings.Contains(output[layer:], scoped) {
+			t.Errorf("%s: components layer missing base rule or trailing override:\n%s", name, output)
+		}
+	}
+}
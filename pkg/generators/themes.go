@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+
+	"github.com/dmoose/tokenctl/pkg/colors"
+	"github.com/dmoose/tokenctl/pkg/tokens"
 )
 
 // DefaultThemeName is the fallback when no theme declares "$default": true.
@@ -12,7 +15,9 @@ const DefaultThemeName = "light"
 // sortThemeNames sorts theme names with the default theme first,
 // then remaining themes alphabetically. This ensures the default theme's
 // :root selector is emitted first so non-default themes can override it
-// via CSS cascade order (same specificity, later rule wins).
+// via CSS cascade order (same specificity, later rule wins). Mode themes
+// precede themes on other axes, which precede combinations, smallest
+// first, so each combination follows every rule it refines.
 func sortThemeNames(names []string, defaultTheme string) {
 	sort.Slice(names, func(i, j int) bool {
 		if names[i] == defaultTheme {
@@ -21,27 +26,221 @@ func sortThemeNames(names []string, defaultTheme string) {
 		if names[j] == defaultTheme {
 			return false
 		}
+		pi, pj := tokens.ThemeParts(names[i]), tokens.ThemeParts(names[j])
+		if len(pi) != len(pj) {
+			return len(pi) < len(pj)
+		}
+		if mi, mj := isModeTheme(names[i]), isModeTheme(names[j]); mi != mj {
+			return mi
+		}
 		return names[i] < names[j]
 	})
 }
 
-// themeSelector returns the CSS selector for a theme. The default theme
+// isModeTheme reports whether a theme is selected by data-theme alone
+func isModeTheme(name string) bool {
+	parts := tokens.ThemeParts(name)
+	return len(parts) == 1 && parts[0].Axis == tokens.ThemeAxisMode
+}
+
+// DefaultThemeSelector is the selector template mode themes are written
+// under unless CSSOptions.ThemeSelector or a theme's $selector gives
+// another. {name} stands for the theme name.
+const DefaultThemeSelector = `[data-theme="{name}"]`
+
+// themeSelectors decides the selector and media queries each theme's rule
+// is written under
+type themeSelectors struct {
+	themes       map[string]ThemeContext
+	defaultTheme string
+	template     string // for mode themes without $selector
+}
+
+func newThemeSelectors(themes map[string]ThemeContext, defaultTheme, template string) themeSelectors {
+	if template == "" {
+		template = DefaultThemeSelector
+	}
+	return themeSelectors{themes: themes, defaultTheme: defaultTheme, template: template}
+}
+
+// own returns the selectors that pick a single theme: its $selector,
+// else the template for a mode theme or data-<axis> for any other
+func (s themeSelectors) own(name string) []string {
+	if selectors := s.themes[name].Selectors; len(selectors) > 0 {
+		return selectors
+	}
+	part := tokens.ThemeParts(name)[0]
+	if part.Axis != tokens.ThemeAxisMode {
+		return []string{fmt.Sprintf(`[%s="%s"]`, tokens.ThemeAttribute(part.Axis), part.Value)}
+	}
+	return []string{strings.ReplaceAll(s.template, "{name}", name)}
+}
+
+// implicit reports whether the default theme applies without being
+// selected, through :root, which a $selector of its own turns off
+func (s themeSelectors) implicit() bool {
+	return len(s.themes[s.defaultTheme].Selectors) == 0
+}
+
+// selector returns the selector for a theme's rule. The default theme
 // gets `:root, [data-theme="name"]` so it applies without any attribute;
-// all other themes get just `[data-theme="name"]`.
-func themeSelector(themeName, defaultTheme string) string {
-	if themeName == defaultTheme {
-		return fmt.Sprintf(`:root, [data-theme="%s"]`, themeName)
+// all other themes get just `[data-theme="name"]`. Themes on other axes
+// select on data-<axis>, and a combination joins its parts' selectors,
+// sorted, into compound selectors; when it includes the default theme
+// it also matches while no mode theme is selected.
+func (s themeSelectors) selector(name string) string {
+	parts := tokens.ThemeParts(name)
+	if len(parts) == 1 {
+		selectors := s.own(name)
+		if name == s.defaultTheme && s.implicit() {
+			selectors = append([]string{":root"}, selectors...)
+		}
+		return strings.Join(selectors, ", ")
+	}
+
+	var groups, others [][]string
+	implicit := false
+	for _, part := range parts {
+		own := s.own(part.Name())
+		groups = append(groups, own)
+		if part.Axis == tokens.ThemeAxisMode && part.Value == s.defaultTheme && s.implicit() {
+			implicit = true
+			continue
+		}
+		others = append(others, own)
+	}
+
+	selectors := compoundSelectors(groups)
+	if implicit {
+		unset := compoundSelectors(others)
+		for i := range unset {
+			unset[i] += s.unset()
+		}
+		selectors = append(unset, selectors...)
+	}
+	return strings.Join(selectors, ", ")
+}
+
+// compoundSelectors returns every way to pick one selector from each
+// group, joined into a compound selector, groups in sorted order
+func compoundSelectors(groups [][]string) []string {
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i][0] < groups[j][0]
+	})
+	result := []string{""}
+	for _, group := range groups {
+		var next []string
+		for _, prefix := range result {
+			for _, selector := range group {
+				next = append(next, prefix+selector)
+			}
+		}
+		result = next
+	}
+	return result
+}
+
+// modeSelectors returns the own selectors of every mode theme, sorted
+func (s themeSelectors) modeSelectors() []string {
+	var selectors []string
+	for name := range s.themes {
+		if isModeTheme(name) {
+			selectors = append(selectors, s.own(name)...)
+		}
+	}
+	sort.Strings(selectors)
+	return selectors
+}
+
+// stock reports whether every mode theme is selected by data-theme
+func (s themeSelectors) stock() bool {
+	if s.template != DefaultThemeSelector {
+		return false
+	}
+	for name, theme := range s.themes {
+		if isModeTheme(name) && len(theme.Selectors) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// unset returns a condition that holds while no mode theme is selected
+func (s themeSelectors) unset() string {
+	if s.stock() {
+		return ":not([data-theme])"
+	}
+	return ":not(" + strings.Join(s.modeSelectors(), ", ") + ")"
+}
+
+// anyMode returns a selector matching whichever mode theme is selected
+func (s themeSelectors) anyMode() string {
+	if s.stock() {
+		return "[data-theme]"
+	}
+	return strings.Join(s.modeSelectors(), ", ")
+}
+
+// media returns the media queries a theme's rule is nested in: its
+// $media, or for a combination each of its parts'
+func (s themeSelectors) media(name string) []string {
+	if media := s.themes[name].Media; media != "" {
+		return []string{media}
+	}
+	parts := tokens.ThemeParts(name)
+	if len(parts) == 1 {
+		return nil
+	}
+	var media []string
+	for _, part := range parts {
+		if m := s.themes[part.Name()].Media; m != "" {
+			media = append(media, m)
+		}
+	}
+	return media
+}
+
+// writeInMedia nests what write emits in the given media queries,
+// passing it the indent to write at
+func writeInMedia(sb *strings.Builder, media []string, indent int, write func(indent int)) {
+	for i, query := range media {
+		fmt.Fprintf(sb, "%s@media %s {\n", strings.Repeat(" ", indent+2*i), query)
+	}
+	write(indent + 2*len(media))
+	for i := len(media) - 1; i >= 0; i-- {
+		fmt.Fprintf(sb, "%s}\n", strings.Repeat(" ", indent+2*i))
 	}
-	return fmt.Sprintf(`[data-theme="%s"]`, themeName)
 }
 
 // GenerateThemes generates CSS blocks for multiple themes.
 // defaultTheme controls which theme maps to :root; pass "" to use DefaultThemeName.
 func GenerateThemes(themes map[string]map[string]any, defaultTheme string) (string, error) {
+	return GenerateThemesWithOptions(themes, defaultTheme, ThemeOptions{})
+}
+
+// ThemeOptions sets where GenerateThemesWithOptions writes each theme
+type ThemeOptions struct {
+	// SelectorTemplate replaces DefaultThemeSelector for mode themes
+	SelectorTemplate string
+
+	// Selectors and Media hold each theme's $selector and $media
+	Selectors map[string][]string
+	Media     map[string]string
+}
+
+// GenerateThemesWithOptions generates CSS blocks for multiple themes
+// under the selectors and media queries opts gives them
+func GenerateThemesWithOptions(themes map[string]map[string]any, defaultTheme string, opts ThemeOptions) (string, error) {
 	if defaultTheme == "" {
 		defaultTheme = DefaultThemeName
 	}
 
+	contexts := make(map[string]ThemeContext, len(themes))
+	for name := range themes {
+		contexts[name] = ThemeContext{Selectors: opts.Selectors[name], Media: opts.Media[name]}
+	}
+	selectors := newThemeSelectors(contexts, defaultTheme, opts.SelectorTemplate)
+
 	var sb strings.Builder
 	sb.WriteString("@layer base {\n")
 
@@ -55,8 +254,6 @@ func GenerateThemes(themes map[string]map[string]any, defaultTheme string) (stri
 	for _, themeName := range themeNames {
 		tokens := themes[themeName]
 
-		fmt.Fprintf(&sb, "  %s {\n", themeSelector(themeName, defaultTheme))
-
 		// Sort token keys for deterministic output
 		tokenKeys := make([]string, 0, len(tokens))
 		for key := range tokens {
@@ -64,15 +261,269 @@ func GenerateThemes(themes map[string]map[string]any, defaultTheme string) (stri
 		}
 		sort.Strings(tokenKeys)
 
-		for _, key := range tokenKeys {
-			val := tokens[key]
-			cssVar := strings.ReplaceAll(key, ".", "-")
-			fmt.Fprintf(&sb, "    --%s: %v;\n", cssVar, val)
-		}
-
-		sb.WriteString("  }\n")
+		writeInMedia(&sb, selectors.media(themeName), 2, func(indent int) {
+			pad := strings.Repeat(" ", indent)
+			fmt.Fprintf(&sb, "%s%s {\n", pad, selectors.selector(themeName))
+			for _, key := range tokenKeys {
+				val := tokens[key]
+				cssVar := strings.ReplaceAll(key, ".", "-")
+				fmt.Fprintf(&sb, "%s  --%s: %v;\n", pad, cssVar, val)
+			}
+			fmt.Fprintf(&sb, "%s}\n", pad)
+		})
 	}
 
 	sb.WriteString("}\n")
 	return sb.String(), nil
 }
+
+// Theme output modes for CSSOptions.ColorScheme
+const (
+	// ColorSchemeAttribute selects themes with [data-theme] only
+	ColorSchemeAttribute = "attribute"
+
+	// ColorSchemeMedia also applies the theme for the OS color scheme
+	// the default theme does not cover, under prefers-color-scheme,
+	// while no data-theme attribute is set
+	ColorSchemeMedia = "media"
+
+	// ColorSchemeLightDark writes colors that differ between the light
+	// and dark themes once, as light-dark(), and selects between them
+	// with color-scheme
+	ColorSchemeLightDark = "light-dark"
+)
+
+// schemeTheme returns the mode theme that follows the OS preference for
+// scheme: the one named after it if that theme declares it, else the
+// first by name that does. Empty when no theme declares scheme.
+func schemeTheme(themes map[string]ThemeContext, scheme string) string {
+	if themes[scheme].ColorScheme == scheme {
+		return scheme
+	}
+	names := make([]string, 0, len(themes))
+	for name, theme := range themes {
+		if theme.ColorScheme == scheme && isModeTheme(name) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+	return names[0]
+}
+
+// lightDarkPlan is the light-dark() output for a light and a dark theme
+type lightDarkPlan struct {
+	light, dark string
+	root        string               // the theme :root applies, if any
+	pairs       map[string][2]string // token path -> light and dark value
+	types       map[string]string
+}
+
+// planLightDark pairs every color token whose value differs between the
+// light and dark themes. Other tokens have no light-dark() form and stay
+// in their theme's block.
+func planLightDark(selectors themeSelectors) (lightDarkPlan, error) {
+	themes := selectors.themes
+	plan := lightDarkPlan{
+		light: schemeTheme(themes, tokens.ColorSchemeLight),
+		dark:  schemeTheme(themes, tokens.ColorSchemeDark),
+		pairs: make(map[string][2]string),
+	}
+	if selectors.implicit() {
+		plan.root = selectors.defaultTheme
+	}
+	if plan.light == "" || plan.dark == "" {
+		return plan, fmt.Errorf("light-dark output needs a theme with $colorScheme %q and one with %q", tokens.ColorSchemeLight, tokens.ColorSchemeDark)
+	}
+
+	light, dark := themes[plan.light], themes[plan.dark]
+	plan.types = tokenTypes(light.Dict)
+	changed := make(map[string]bool)
+	for path := range light.DiffTokens {
+		changed[path] = true
+	}
+	for path := range dark.DiffTokens {
+		changed[path] = true
+	}
+	for path := range changed {
+		a, aok := light.ResolvedTokens[path].(string)
+		b, bok := dark.ResolvedTokens[path].(string)
+		if !aok || !bok || a == b || !isColorValue(a, plan.types[path]) || !isColorValue(b, plan.types[path]) {
+			continue
+		}
+		plan.pairs[path] = [2]string{a, b}
+	}
+	return plan, nil
+}
+
+// scheme returns the color-scheme a theme's own block declares. A light
+// or dark theme applied by :root is pinned beside the light-dark()
+// declarations instead, so :root keeps following the OS.
+func (p lightDarkPlan) scheme(theme string, themeCtx ThemeContext) string {
+	if theme == p.root && (theme == p.light || theme == p.dark) {
+		return ""
+	}
+	return themeCtx.ColorScheme
+}
+
+// skip returns the paths theme leaves to the light-dark() declarations
+func (p lightDarkPlan) skip(theme string) map[string]bool {
+	if theme != p.light && theme != p.dark {
+		return nil
+	}
+	skip := make(map[string]bool, len(p.pairs))
+	for path := range p.pairs {
+		skip[path] = true
+	}
+	return skip
+}
+
+// covered reports whether the light-dark() declarations hold everything
+// theme changes, leaving its own rule nothing to declare
+func (p lightDarkPlan) covered(theme string, themeCtx ThemeContext) bool {
+	skip := p.skip(theme)
+	if skip == nil {
+		return false
+	}
+	for path := range themeCtx.DiffTokens {
+		if !skip[path] {
+			return false
+		}
+	}
+	return true
+}
+
+// write emits the light-dark() declarations on :root, which follows the
+// OS scheme, and pins the scheme for the light or dark theme :root
+// applies when it is selected explicitly. It must precede the theme blocks so other themes can still override
+// the paired colors. With a fallback mode, a pair holding a wide-gamut
+// color is written with both sides in sRGB hex and restated as authored
+// under the fallback condition, guarded by light-dark() support as well.
+func (p lightDarkPlan) write(sb *strings.Builder, selectors themeSelectors, fallbackMode string) {
+	sb.WriteString("  :root {\n")
+	sb.WriteString("    color-scheme: light dark;\n")
+	paths := make([]string, 0, len(p.pairs))
+	for path := range p.pairs {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	fallbacks := fallbackBlock{mode: fallbackMode, guard: "@supports (color: light-dark(red, red))"}
+	for _, path := range paths {
+		cssVar := strings.ReplaceAll(path, ".", "-")
+		pair := p.pairs[path]
+		authored := fmt.Sprintf("light-dark(%s, %s)", pair[0], pair[1])
+		lightFallback, lightOK := colorFallback(fallbackMode, pair[0], p.types[path])
+		darkFallback, darkOK := colorFallback(fallbackMode, pair[1], p.types[path])
+		if !lightOK && !darkOK {
+			fmt.Fprintf(sb, "    --%s: %s;\n", cssVar, authored)
+			continue
+		}
+		if !lightOK {
+			lightFallback = pair[0]
+		}
+		if !darkOK {
+			darkFallback = pair[1]
+		}
+		fmt.Fprintf(sb, "    --%s: light-dark(%s, %s);\n", cssVar, lightFallback, darkFallback)
+		fallbacks.restate(cssVar, authored)
+	}
+	sb.WriteString("  }\n")
+	fallbacks.write(sb, ":root", 2)
+	if p.root != "" && (p.root == p.light || p.root == p.dark) {
+		fmt.Fprintf(sb, "  %s {\n    color-scheme: %s;\n  }\n", strings.Join(selectors.own(p.root), ", "), selectors.themes[p.root].ColorScheme)
+	}
+}
+
+// writeSchemeMedia restates what the theme for the OS scheme the default
+// theme does not cover changes besides the paired colors, such as its
+// shadows, while no theme is selected, so they follow the OS as well
+func (p lightDarkPlan) writeSchemeMedia(sb *strings.Builder, selectors themeSelectors, fallbackMode string) {
+	scheme, name := schemeMediaTheme(selectors)
+	if name == "" || p.covered(name, selectors.themes[name]) {
+		return
+	}
+	fmt.Fprintf(sb, "  @media (prefers-color-scheme: %s) {\n", scheme)
+	writeThemeRule(sb, ":root"+selectors.unset(), selectors.themes[name], "", p.skip(name), fallbackMode, 4)
+	sb.WriteString("  }\n")
+}
+
+// isColorValue reports whether a token value can go in light-dark(),
+// which takes colors only
+func isColorValue(value, tokenType string) bool {
+	if tokenType == "color" {
+		return true
+	}
+	if tokenType != "" {
+		return false
+	}
+	_, err := colors.Parse(value)
+	return err == nil
+}
+
+// schemeMediaTheme returns the OS scheme the default theme does not
+// cover and the theme that provides it, or "" when there is none
+func schemeMediaTheme(selectors themeSelectors) (scheme, name string) {
+	scheme = tokens.ColorSchemeDark
+	if selectors.themes[selectors.defaultTheme].ColorScheme == tokens.ColorSchemeDark {
+		scheme = tokens.ColorSchemeLight
+	}
+	return scheme, schemeTheme(selectors.themes, scheme)
+}
+
+// writeSchemeMedia applies the theme for the OS scheme the default theme
+// does not cover while no theme is selected explicitly
+func writeSchemeMedia(sb *strings.Builder, selectors themeSelectors, fallbackMode string) error {
+	themes := selectors.themes
+	scheme, name := schemeMediaTheme(selectors)
+	if name == "" {
+		return fmt.Errorf("prefers-color-scheme output needs a theme with $colorScheme %q", scheme)
+	}
+	fmt.Fprintf(sb, "  @media (prefers-color-scheme: %s) {\n", scheme)
+	writeThemeRule(sb, ":root"+selectors.unset(), themes[name], scheme, nil, fallbackMode, 4)
+	sb.WriteString("  }\n")
+	return nil
+}
+
+// themeRuleKeys returns the changed tokens a theme's rule declares, less
+// skip, sorted for deterministic output
+func themeRuleKeys(themeCtx ThemeContext, skip map[string]bool) []string {
+	types := tokenTypes(themeCtx.Dict)
+	tokenKeys := make([]string, 0, len(themeCtx.DiffTokens))
+	for key, value := range themeCtx.DiffTokens {
+		if skip[key] {
+			continue
+		}
+		if _, ok := serializeTokenValue(value, types[key]); ok {
+			tokenKeys = append(tokenKeys, key)
+		}
+	}
+	sort.Strings(tokenKeys)
+	return tokenKeys
+}
+
+// writeThemeRule writes a theme's changed tokens, less skip, as a rule
+// for selector, followed by the restatement of any colors written with
+// a fallback. A non-empty scheme is declared as color-scheme so form
+// controls and scrollbars match the theme.
+func writeThemeRule(sb *strings.Builder, selector string, themeCtx ThemeContext, scheme string, skip map[string]bool, fallbackMode string, indent int) {
+	tokenKeys := themeRuleKeys(themeCtx, skip)
+	pad := strings.Repeat(" ", indent)
+	fmt.Fprintf(sb, "%s%s {\n", pad, selector)
+	if scheme != "" {
+		fmt.Fprintf(sb, "%s  color-scheme: %s;\n", pad, scheme)
+	}
+
+	types := tokenTypes(themeCtx.Dict)
+	fallbacks := fallbackBlock{mode: fallbackMode}
+	for _, key := range tokenKeys {
+		cssValue, _ := serializeTokenValue(themeCtx.DiffTokens[key], types[key])
+		cssVar := strings.ReplaceAll(key, ".", "-")
+		fmt.Fprintf(sb, "%s  --%s: %s;\n", pad, cssVar, fallbacks.value(cssVar, cssValue, types[key]))
+	}
+
+	fmt.Fprintf(sb, "%s}\n", pad)
+	// Per theme, so the next theme's fallbacks still override these
+	fallbacks.write(sb, selector, indent)
+}
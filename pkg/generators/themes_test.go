@@ -3,6 +3,8 @@ package generators
 import (
 	"strings"
 	"testing"
+
+	"github.com/dmoose/tokenctl/pkg/tokens"
 )
 
 func TestGenerateThemes(t *testing.T) {
@@ -199,3 +201,317 @@ func TestGenerateThemes_DeterministicOutput(t *testing.T) {
 		t.Error("default theme (light) should come first so non-default themes override :root via cascade")
 	}
 }
+
+func TestThemeVariations_PrefersColorScheme(t *testing.T) {
+	t.Parallel()
+
+	themes := map[string]ThemeContext{
+		"light": {ColorScheme: tokens.ColorSchemeLight},
+		"dark":  {DiffTokens: map[string]any{"color.base-100": "#1d232a", "radius.box": "4px"}, ColorScheme: tokens.ColorSchemeDark},
+	}
+	want := `  @media (prefers-color-scheme: dark) {
+    :root:not([data-theme]) {
+      color-scheme: dark;
+      --color-base-100: #1d232a;
+      --radius-box: 4px;
+    }
+  }
+`
+	tw, err := (&TailwindGenerator{ColorScheme: ColorSchemeMedia}).generateThemeVariations(themes, "light")
+	if err != nil {
+		t.Fatal(err)
+	}
+	css, err := (&CSSGenerator{ColorScheme: ColorSchemeMedia}).generateThemeVariations(themes, "light")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, output := range []string{tw, css} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing prefers-color-scheme block:\n%s", output)
+		}
+		if !strings.Contains(output, "  [data-theme=\"dark\"] {\n    color-scheme: dark;\n    --color-base-100: #1d232a;\n") {
+			t.Errorf("explicit dark theme no longer emitted:\n%s", output)
+		}
+	}
+
+	// A dark default theme follows a light OS preference instead
+	themes["light"] = ThemeContext{DiffTokens: map[string]any{"color.base-100": "#fafafa"}, ColorScheme: tokens.ColorSchemeLight}
+	css, err = (&CSSGenerator{ColorScheme: ColorSchemeMedia}).generateThemeVariations(themes, "dark")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(css, "@media (prefers-color-scheme: light) {\n    :root:not([data-theme]) {\n      color-scheme: light;\n      --color-base-100: #fafafa;") {
+		t.Errorf("output missing light preference block:\n%s", css)
+	}
+}
+
+func TestThemeVariations_LightDark(t *testing.T) {
+	t.Parallel()
+
+	themes := map[string]ThemeContext{
+		"light": {
+			ResolvedTokens: map[string]any{"color.base-100": "#ffffff", "radius.box": "8px"},
+			ColorScheme:    tokens.ColorSchemeLight,
+		},
+		"dark": {
+			ResolvedTokens: map[string]any{"color.base-100": "#1d232a", "radius.box": "4px"},
+			DiffTokens:     map[string]any{"color.base-100": "#1d232a", "radius.box": "4px"},
+			ColorScheme:    tokens.ColorSchemeDark,
+		},
+		"brand": {DiffTokens: map[string]any{"color.accent": "#ff00aa"}},
+	}
+	want := `  :root {
+    color-scheme: light dark;
+    --color-base-100: light-dark(#ffffff, #1d232a);
+  }
+  [data-theme="light"] {
+    color-scheme: light;
+  }
+`
+	for name, gen := range map[string]func(map[string]ThemeContext, string) (string, error){
+		"tailwind": (&TailwindGenerator{ColorScheme: ColorSchemeLightDark}).generateThemeVariations,
+		"css":      (&CSSGenerator{ColorScheme: ColorSchemeLightDark}).generateThemeVariations,
+	} {
+		output, err := gen(themes, "light")
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !strings.HasPrefix(output[strings.Index(output, "\n")+1:], want) {
+			t.Errorf("%s: light-dark block must open the theme layer:\n%s", name, output)
+		}
+		// Non-color tokens have no light-dark() form and stay in the theme,
+		// beside its scheme, and follow the OS while no theme is selected
+		if !strings.Contains(output, "  [data-theme=\"dark\"] {\n    color-scheme: dark;\n    --radius-box: 4px;\n  }\n") {
+			t.Errorf("%s: dark theme should keep only its scheme and non-color tokens:\n%s", name, output)
+		}
+		if strings.Count(output, "[data-theme=\"dark\"] {") != 1 {
+			t.Errorf("%s: dark theme should be written in one rule:\n%s", name, output)
+		}
+		if !strings.Contains(output, "  @media (prefers-color-scheme: dark) {\n    :root:not([data-theme]) {\n      --radius-box: 4px;\n    }\n  }\n") {
+			t.Errorf("%s: dark theme's non-color tokens should follow the OS:\n%s", name, output)
+		}
+		if !strings.Contains(output, "--color-accent: #ff00aa;") {
+			t.Errorf("%s: other themes should be unchanged:\n%s", name, output)
+		}
+	}
+}
+
+func TestThemeVariations_LightDarkFallback(t *testing.T) {
+	t.Parallel()
+
+	// A dark theme that changes only paired colors, one outside sRGB
+	themes := map[string]ThemeContext{
+		"light": {ResolvedTokens: map[string]any{"color.base-100": "#ffffff"}, ColorScheme: tokens.ColorSchemeLight},
+		"dark": {
+			ResolvedTokens: map[string]any{"color.base-100": "oklch(40% 0.25 300)"},
+			DiffTokens:     map[string]any{"color.base-100": "oklch(40% 0.25 300)"},
+			ColorScheme:    tokens.ColorSchemeDark,
+		},
+	}
+
+	want := `  :root {
+    color-scheme: light dark;
+    --color-base-100: light-dark(#ffffff, #6000af);
+  }
+  @supports (color: light-dark(red, red)) {
+    @media (color-gamut: p3) {
+      :root {
+        --color-base-100: light-dark(#ffffff, oklch(40% 0.25 300));
+      }
+    }
+  }
+`
+	for name, gen := range map[string]func(map[string]ThemeContext, string) (string, error){
+		"tailwind": (&TailwindGenerator{ColorScheme: ColorSchemeLightDark, ColorFallback: ColorFallbackGamut}).generateThemeVariations,
+		"css":      (&CSSGenerator{ColorScheme: ColorSchemeLightDark, ColorFallback: ColorFallbackGamut}).generateThemeVariations,
+	} {
+		output, err := gen(themes, "light")
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !strings.Contains(output, want) {
+			t.Errorf("%s: output missing guarded light-dark() fallback:\n%s", name, output)
+		}
+		// The light and dark themes have nothing left but the scheme
+		// pinned beside the light-dark() block
+		if strings.Contains(output, " {\n  }\n") {
+			t.Errorf("%s: output has an empty rule:\n%s", name, output)
+		}
+	}
+}
+
+func TestThemeVariations_ColorSchemeErrors(t *testing.T) {
+	t.Parallel()
+
+	themes := map[string]ThemeContext{
+		"light": {ColorScheme: tokens.ColorSchemeLight},
+		"brand": {DiffTokens: map[string]any{"color.accent": "#ff00aa"}},
+	}
+	if _, err := (&CSSGenerator{ColorScheme: ColorSchemeMedia}).generateThemeVariations(themes, "light"); err == nil {
+		t.Error("expected an error for prefers-color-scheme output without a dark theme")
+	}
+	if _, err := (&TailwindGenerator{ColorScheme: ColorSchemeLightDark}).generateThemeVariations(themes, "light"); err == nil {
+		t.Error("expected an error for light-dark output without a dark theme")
+	}
+}
+
+func TestSchemeTheme(t *testing.T) {
+	t.Parallel()
+
+	themes := map[string]ThemeContext{
+		"dim":      {ColorScheme: tokens.ColorSchemeDark},
+		"midnight": {ColorScheme: tokens.ColorSchemeDark},
+		"paper":    {ColorScheme: tokens.ColorSchemeLight},
+	}
+	if got := schemeTheme(themes, tokens.ColorSchemeDark); got != "dim" {
+		t.Errorf("schemeTheme(dark) = %q, want dim (first by name)", got)
+	}
+	themes["dark"] = ThemeContext{ColorScheme: tokens.ColorSchemeDark}
+	if got := schemeTheme(themes, tokens.ColorSchemeDark); got != "dark" {
+		t.Errorf("schemeTheme(dark) = %q, want the theme named dark", got)
+	}
+	if got := schemeTheme(themes, tokens.ColorSchemeLight); got != "paper" {
+		t.Errorf("schemeTheme(light) = %q, want paper", got)
+	}
+}
+
+func TestThemeVariations_ColorSchemeProperty(t *testing.T) {
+	t.Parallel()
+
+	themes := map[string]ThemeContext{
+		"light": {ColorScheme: tokens.ColorSchemeLight},
+		"dark":  {DiffTokens: map[string]any{"color.base-100": "#1d232a"}, ColorScheme: tokens.ColorSchemeDark},
+		"brand": {DiffTokens: map[string]any{"color.accent": "#ff00aa"}},
+	}
+	for name, gen := range map[string]func(map[string]ThemeContext, string) (string, error){
+		"tailwind": (&TailwindGenerator{}).generateThemeVariations,
+		"css":      (&CSSGenerator{}).generateThemeVariations,
+	} {
+		output, err := gen(themes, "light")
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		// The default theme carries its scheme to :root even with no
+		// changed tokens; a theme without $colorScheme declares none
+		for _, want := range []string{
+			"  :root, [data-theme=\"light\"] {\n    color-scheme: light;\n  }\n",
+			"  [data-theme=\"dark\"] {\n    color-scheme: dark;\n",
+			"  [data-theme=\"brand\"] {\n    --color-accent: #ff00aa;\n",
+		} {
+			if !strings.Contains(output, want) {
+				t.Errorf("%s: output missing %q:\n%s", name, want, output)
+			}
+		}
+	}
+}
+
+func TestThemeSelectors(t *testing.T) {
+	t.Parallel()
+
+	themes := map[string]ThemeContext{
+		"light":      {},
+		"dark":       {},
+		"hc":         {Selectors: []string{".hc", ":host([theme=hc])"}, Media: "(prefers-contrast: more)"},
+		"brand/acme": {Media: "print"},
+	}
+	custom := map[string]ThemeContext{"light": {Selectors: []string{".light"}}, "dark": {}}
+
+	tests := []struct {
+		name      string
+		themes    map[string]ThemeContext
+		template  string
+		theme     string
+		want      string
+		wantMedia []string
+	}{
+		{"stock default", themes, "", "light", `:root, [data-theme="light"]`, nil},
+		{"template", themes, ".theme-{name}", "dark", ".theme-dark", nil},
+		{"template keeps :root for the default", themes, ".theme-{name}", "light", ":root, .theme-light", nil},
+		{"$selector list with media", themes, ".theme-{name}", "hc", ".hc, :host([theme=hc])", []string{"(prefers-contrast: more)"}},
+		{"combination on the template", themes, ".theme-{name}", "dark+brand/acme", `.theme-dark[data-brand="acme"]`, []string{"print"}},
+		{"combination with the default", themes, ".theme-{name}", "light+brand/acme",
+			`[data-brand="acme"]:not(.hc, .theme-dark, .theme-light, :host([theme=hc])), .theme-light[data-brand="acme"]`, []string{"print"}},
+		{"combination with a $selector list", themes, "", "hc+brand/acme",
+			`.hc[data-brand="acme"], :host([theme=hc])[data-brand="acme"]`, []string{"(prefers-contrast: more)", "print"}},
+		{"default with its own $selector drops :root", custom, "", "light", ".light", nil},
+		{"default with its own $selector applies only when selected", custom, "", "light+brand/acme", `.light[data-brand="acme"]`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			selectors := newThemeSelectors(tt.themes, "light", tt.template)
+			if got := selectors.selector(tt.theme); got != tt.want {
+				t.Errorf("selector(%q) = %s, want %s", tt.theme, got, tt.want)
+			}
+			if got := selectors.media(tt.theme); strings.Join(got, "|") != strings.Join(tt.wantMedia, "|") {
+				t.Errorf("media(%q) = %v, want %v", tt.theme, got, tt.wantMedia)
+			}
+		})
+	}
+}
+
+func TestGenerateThemesWithOptions(t *testing.T) {
+	t.Parallel()
+
+	themes := map[string]map[string]any{
+		"light": {"color.surface": "#ffffff"},
+		"dark":  {"color.surface": "#1e1e1e"},
+		"hc":    {"color.surface": "#000000"},
+	}
+	output, err := GenerateThemesWithOptions(themes, "", ThemeOptions{
+		SelectorTemplate: ".theme-{name}",
+		Selectors:        map[string][]string{"hc": {":host([theme=hc])"}},
+		Media:            map[string]string{"hc": "(prefers-contrast: more)"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateThemesWithOptions failed: %v", err)
+	}
+
+	want := "@layer base {\n" +
+		"  :root, .theme-light {\n    --color-surface: #ffffff;\n  }\n" +
+		"  .theme-dark {\n    --color-surface: #1e1e1e;\n  }\n" +
+		"  @media (prefers-contrast: more) {\n    :host([theme=hc]) {\n      --color-surface: #000000;\n    }\n  }\n" +
+		"}\n"
+	if output != want {
+		t.Errorf("got:\n%s\nwant:\n%s", output, want)
+	}
+}
+
+func TestThemeVariations_Selectors(t *testing.T) {
+	t.Parallel()
+
+	ctx := &GenerationContext{
+		ResolvedTokens: map[string]any{"color.primary": "#000000"},
+		DefaultTheme:   "light",
+		Themes: map[string]ThemeContext{
+			"light": {ColorScheme: tokens.ColorSchemeLight, DiffTokens: map[string]any{"color.primary": "#111111"}},
+			"dark":  {ColorScheme: tokens.ColorSchemeDark, DiffTokens: map[string]any{"color.primary": "#eeeeee"}},
+		},
+	}
+	opts := CSSOptions{ColorScheme: ColorSchemeMedia, ThemeSelector: ".theme-{name}"}
+	css, err := NewCSSGeneratorWithOptions(opts).Generate(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tw, err := NewTailwindGeneratorWithOptions(opts).Generate(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, output := range map[string]string{"css": css, "tailwind": tw} {
+		for _, want := range []string{
+			"  :root, .theme-light {\n",
+			"  .theme-dark {\n",
+			"    :root:not(.theme-dark, .theme-light) {\n",
+		} {
+			if !strings.Contains(output, want) {
+				t.Errorf("%s: output missing %q:\n%s", name, want, output)
+			}
+		}
+		if strings.Contains(output, "data-theme") {
+			t.Errorf("%s: template ignored somewhere:\n%s", name, output)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+// tokenctl/pkg/report/report.go
+
+// Package report writes diagnostics in machine-readable formats: a plain
+// JSON document, SARIF 2.1.0 for code scanning, and JUnit XML for test
+// dashboards. Each format carries the rule ID, severity and source
+// location of every diagnostic so CI can annotate the offending line
+// without parsing human-oriented output.
+package report
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/dmoose/tokenctl/pkg/tokens"
+	"github.com/dmoose/tokenctl/pkg/version"
+)
+
+// Report formats
+const (
+	FormatJSON  = "json"
+	FormatSARIF = "sarif"
+	FormatJUnit = "junit"
+)
+
+// Formats lists the valid --report values
+var Formats = []string{FormatJSON, FormatSARIF, FormatJUnit}
+
+const informationURI = "https://github.com/dmoose/tokenctl"
+
+// Write renders diagnostics from one run of command (validate, build)
+func Write(w io.Writer, format, command string, diags []tokens.Diagnostic) error {
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, command, diags)
+	case FormatSARIF:
+		return writeSARIF(w, diags)
+	case FormatJUnit:
+		return writeJUnit(w, command, diags)
+	default:
+		return fmt.Errorf("unknown report format: %s (valid: %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+// Counts returns the number of errors and warnings
+func Counts(diags []tokens.Diagnostic) (errs, warnings int) {
+	for _, d := range diags {
+		if d.Severity == tokens.SeverityError {
+			errs++
+		} else {
+			warnings++
+		}
+	}
+	return errs, warnings
+}
+
+// location formats a diagnostic's path, and theme when set, as a prefix
+// for message text
+func location(d tokens.Diagnostic) string {
+	switch {
+	case d.Path != "" && d.Theme != "":
+		return fmt.Sprintf("%s (theme %s): ", d.Path, d.Theme)
+	case d.Path != "":
+		return d.Path + ": "
+	case d.Theme != "":
+		return fmt.Sprintf("theme %s: ", d.Theme)
+	}
+	return ""
+}
+
+func writeJSON(w io.Writer, command string, diags []tokens.Diagnostic) error {
+	errs, warnings := Counts(diags)
+	if diags == nil {
+		diags = []tokens.Diagnostic{}
+	}
+	doc := struct {
+		Tool        string              `json:"tool"`
+		Version     string              `json:"version"`
+		Command     string              `json:"command"`
+		Errors      int                 `json:"errors"`
+		Warnings    int                 `json:"warnings"`
+		Diagnostics []tokens.Diagnostic `json:"diagnostics"`
+	}{"tokenctl", version.String(), command, errs, warnings, diags}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// SARIF 2.1.0, limited to the properties code scanning reads
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string       `json:"id"`
+	ShortDescription     sarifMessage `json:"shortDescription"`
+	DefaultConfiguration struct {
+		Level string `json:"level"`
+	} `json:"defaultConfiguration"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogical         `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation struct {
+		URI string `json:"uri"`
+	} `json:"artifactLocation"`
+	Region *sarifRegion `json:"region,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+type sarifLogical struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+func writeSARIF(w io.Writer, diags []tokens.Diagnostic) error {
+	driver := sarifDriver{Name: "tokenctl", Version: version.String(), InformationURI: informationURI}
+	index := make(map[string]int, len(tokens.Rules))
+	for i, rule := range tokens.Rules {
+		r := sarifRule{ID: rule.ID, ShortDescription: sarifMessage{rule.Description}}
+		r.DefaultConfiguration.Level = string(rule.Severity)
+		driver.Rules = append(driver.Rules, r)
+		index[rule.ID] = i
+	}
+
+	results := make([]sarifResult, 0, len(diags))
+	for _, d := range diags {
+		res := sarifResult{
+			RuleID:    d.Rule,
+			RuleIndex: index[d.Rule],
+			Level:     string(d.Severity),
+			Message:   sarifMessage{location(d) + d.Message},
+		}
+		var loc sarifLocation
+		if d.File != "" {
+			phys := &sarifPhysicalLocation{}
+			phys.ArtifactLocation.URI = filepath.ToSlash(d.File)
+			if d.Line > 0 {
+				phys.Region = &sarifRegion{StartLine: d.Line, StartColumn: d.Column}
+			}
+			loc.PhysicalLocation = phys
+		}
+		if d.Path != "" {
+			loc.LogicalLocations = []sarifLogical{{FullyQualifiedName: d.Path}}
+		}
+		if loc.PhysicalLocation != nil || loc.LogicalLocations != nil {
+			res.Locations = []sarifLocation{loc}
+		}
+		results = append(results, res)
+	}
+
+	log := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
+
+// JUnit XML. Every rule is a test case so dashboards track the same
+// tests from run to run: a rule with errors fails, and warnings are
+// attached as output without failing it.
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Errors   int         `xml:"errors,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func writeJUnit(w io.Writer, command string, diags []tokens.Diagnostic) error {
+	name := "tokenctl " + command
+	suite := junitSuite{Name: name}
+	for _, rule := range tokens.Rules {
+		tc := junitCase{Name: rule.ID, Classname: "tokenctl." + command}
+		var failures, warnings []string
+		for _, d := range diags {
+			if d.Rule != rule.ID {
+				continue
+			}
+			line := location(d) + d.Message
+			if d.File != "" {
+				line = fmt.Sprintf("%s: %s", tokens.Position{File: d.File, Line: d.Line, Column: d.Column}, line)
+			}
+			if d.Severity == tokens.SeverityError {
+				failures = append(failures, line)
+			} else {
+				warnings = append(warnings, line)
+			}
+		}
+		if len(failures) > 0 {
+			tc.Failure = &junitFailure{
+				Message: fmt.Sprintf("%d %s error(s)", len(failures), rule.ID),
+				Type:    rule.ID,
+				Text:    strings.Join(failures, "\n"),
+			}
+			suite.Failures++
+		}
+		if len(warnings) > 0 {
+			tc.SystemOut = strings.Join(warnings, "\n")
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	suite.Tests = len(suite.Cases)
+
+	doc := junitSuites{Name: name, Tests: suite.Tests, Failures: suite.Failures, Suites: []junitSuite{suite}}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
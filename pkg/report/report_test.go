@@ -0,0 +1,142 @@
+// tokenctl/pkg/report/report_test.go
+
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/dmoose/tokenctl/pkg/tokens"
+)
+
+func sampleDiagnostics() []tokens.Diagnostic {
+	return []tokens.Diagnostic{
+		{
+			Rule: tokens.RuleReferenceNotFound, Severity: tokens.SeverityError,
+			Message: "reference not found: color.missing", Path: "color.accent",
+			File: "tokens/colors.json", Line: 4, Column: 17,
+		},
+		{
+			Rule: tokens.RuleInvalidValue, Severity: tokens.SeverityError,
+			Message: "invalid color: bad", Path: "color.primary", Theme: "dark",
+			File: "tokens/themes/dark.json", Line: 3, Column: 5,
+		},
+		{
+			Rule: string(tokens.FindingUnknownMetadataKey), Severity: tokens.SeverityWarning,
+			Message: "dropped input: $tpye", Path: "spacing.md.$tpye",
+		},
+	}
+}
+
+func TestWrite_JSON(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatJSON, "validate", sampleDiagnostics()); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Command     string              `json:"command"`
+		Errors      int                 `json:"errors"`
+		Warnings    int                 `json:"warnings"`
+		Diagnostics []tokens.Diagnostic `json:"diagnostics"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if doc.Command != "validate" || doc.Errors != 2 || doc.Warnings != 1 || len(doc.Diagnostics) != 3 {
+		t.Errorf("unexpected report: %s", buf.String())
+	}
+	if d := doc.Diagnostics[0]; d.Line != 4 || d.Column != 17 || d.Rule != tokens.RuleReferenceNotFound {
+		t.Errorf("diagnostic lost its location: %+v", d)
+	}
+
+	buf.Reset()
+	if err := Write(&buf, FormatJSON, "build", nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"diagnostics": []`) {
+		t.Errorf("empty report should list no diagnostics: %s", buf.String())
+	}
+}
+
+func TestWrite_SARIF(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatSARIF, "validate", sampleDiagnostics()); err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid SARIF: %v\n%s", err, buf.String())
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected SARIF envelope: %s", buf.String())
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != len(tokens.Rules) {
+		t.Errorf("driver lists %d rules, want %d", len(run.Tool.Driver.Rules), len(tokens.Rules))
+	}
+	if len(run.Results) != 3 {
+		t.Fatalf("got %d results, want 3", len(run.Results))
+	}
+
+	first := run.Results[0]
+	if first.Level != "error" || run.Tool.Driver.Rules[first.RuleIndex].ID != first.RuleID {
+		t.Errorf("result does not point at its rule: %+v", first)
+	}
+	phys := first.Locations[0].PhysicalLocation
+	if phys == nil || phys.ArtifactLocation.URI != "tokens/colors.json" || phys.Region.StartLine != 4 || phys.Region.StartColumn != 17 {
+		t.Errorf("unexpected location: %+v", first.Locations)
+	}
+	if msg := run.Results[1].Message.Text; msg != "color.primary (theme dark): invalid color: bad" {
+		t.Errorf("message = %q", msg)
+	}
+
+	// A finding without a file keeps its logical location only
+	last := run.Results[2]
+	if last.Level != "warning" || last.Locations[0].PhysicalLocation != nil ||
+		last.Locations[0].LogicalLocations[0].FullyQualifiedName != "spacing.md.$tpye" {
+		t.Errorf("unexpected warning result: %+v", last)
+	}
+}
+
+func TestWrite_JUnit(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatJUnit, "validate", sampleDiagnostics()); err != nil {
+		t.Fatal(err)
+	}
+	var doc junitSuites
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JUnit XML: %v\n%s", err, buf.String())
+	}
+	if doc.Tests != len(tokens.Rules) || doc.Failures != 2 {
+		t.Errorf("tests=%d failures=%d, want %d and 2", doc.Tests, doc.Failures, len(tokens.Rules))
+	}
+
+	cases := make(map[string]junitCase)
+	for _, tc := range doc.Suites[0].Cases {
+		cases[tc.Name] = tc
+	}
+	ref := cases[tokens.RuleReferenceNotFound]
+	if ref.Failure == nil || !strings.Contains(ref.Failure.Text, "tokens/colors.json:4:17: color.accent: reference not found") {
+		t.Errorf("unexpected failure: %+v", ref.Failure)
+	}
+	meta := cases[string(tokens.FindingUnknownMetadataKey)]
+	if meta.Failure != nil || !strings.Contains(meta.SystemOut, "$tpye") {
+		t.Errorf("warnings should not fail their case: %+v", meta)
+	}
+	if cases[tokens.RuleCircularReference].Failure != nil {
+		t.Error("rule without diagnostics should pass")
+	}
+}
+
+func TestWrite_UnknownFormat(t *testing.T) {
+	t.Parallel()
+	if err := Write(&bytes.Buffer{}, "xml", "validate", nil); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
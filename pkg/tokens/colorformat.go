@@ -0,0 +1,230 @@
+// tokenctl/pkg/tokens/colorformat.go
+
+package tokens
+
+import (
+	"strings"
+
+	"github.com/dmoose/tokenctl/pkg/colors"
+)
+
+// ColorFormatter rewrites color values into one output format, so a
+// stylesheet does not mix hex, rgb() and oklch() according to how each
+// source token happened to be written. A group may override the format
+// for everything under it with $colorFormat.
+type ColorFormatter struct {
+	Format    string            // default format; empty leaves colors as written
+	Precision int               // channel decimals, negative for each format's default
+	Overrides map[string]string // group path -> format declared with $colorFormat
+}
+
+// NewColorFormatter reads $colorFormat overrides from d. An invalid
+// $colorFormat is reported by Validate and ignored here.
+func NewColorFormatter(d *Dictionary, format string, precision int) ColorFormatter {
+	f := ColorFormatter{Format: format, Precision: precision, Overrides: make(map[string]string)}
+	collectColorFormats(d.Root, "", f.Overrides)
+	return f
+}
+
+func collectColorFormats(node map[string]any, currentPath string, out map[string]string) {
+	if name, ok := node["$colorFormat"].(string); ok {
+		if format, err := colors.ParseFormat(name); err == nil {
+			out[currentPath] = format
+		}
+	}
+	for key, val := range node {
+		if strings.HasPrefix(key, "$") {
+			continue
+		}
+		child, ok := val.(map[string]any)
+		if !ok {
+			continue
+		}
+		childPath := key
+		if currentPath != "" {
+			childPath = currentPath + "." + key
+		}
+		collectColorFormats(child, childPath, out)
+	}
+}
+
+// formatFor returns the format for path: the nearest $colorFormat on it
+// or an enclosing group, else the default
+func (f ColorFormatter) formatFor(path string) string {
+	for p := path; ; p = parentPath(p) {
+		if format, ok := f.Overrides[p]; ok {
+			return format
+		}
+		if p == "" {
+			return f.Format
+		}
+	}
+}
+
+// Active reports whether the formatter changes anything
+func (f ColorFormatter) Active() bool {
+	return f.Format != "" || len(f.Overrides) > 0
+}
+
+// Tokens converts every color in resolved and returns a new map. Tokens
+// typed "color" are converted whenever they parse, named colors
+// included; untyped tokens only when written as hex or a color function,
+// so a keyword such as "orange" in a non-color token is left alone.
+func (f ColorFormatter) Tokens(resolved map[string]any, types map[string]string) map[string]any {
+	out := make(map[string]any, len(resolved))
+	for path, val := range resolved {
+		out[path] = val
+		t := types[path]
+		if t != "" && t != "color" {
+			continue
+		}
+		s, ok := val.(string)
+		if !ok {
+			continue
+		}
+		if t == "" && !isColorLiteral(s) {
+			continue
+		}
+		if converted, ok := f.convert(s, f.formatFor(path)); ok {
+			out[path] = converted
+		}
+	}
+	return out
+}
+
+// Keyframes converts color literals in keyframe properties. Keyframes sit
+// under the root "keyframes" group, so $colorFormat there applies.
+func (f ColorFormatter) Keyframes(keyframes []KeyframeDefinition) []KeyframeDefinition {
+	out := make([]KeyframeDefinition, len(keyframes))
+	for i, kf := range keyframes {
+		format := f.formatFor("keyframes." + kf.Name)
+		out[i] = KeyframeDefinition{
+			Name:          kf.Name,
+			Frames:        f.frames(kf.Frames, format),
+			ReducedMotion: f.frames(kf.ReducedMotion, format),
+		}
+	}
+	return out
+}
+
+func (f ColorFormatter) frames(frames map[string]map[string]string, format string) map[string]map[string]string {
+	if frames == nil {
+		return nil
+	}
+	out := make(map[string]map[string]string, len(frames))
+	for selector, props := range frames {
+		converted := make(map[string]string, len(props))
+		for prop, val := range props {
+			converted[prop] = val
+			if isColorLiteral(val) {
+				if c, ok := f.convert(val, format); ok {
+					converted[prop] = c
+				}
+			}
+		}
+		out[selector] = converted
+	}
+	return out
+}
+
+// Components converts color literals in component properties: base,
+// variants, sizes, states, container and forced colors overrides.
+// References are left for the generators to turn into var().
+func (f ColorFormatter) Components(components map[string]ComponentDefinition) map[string]ComponentDefinition {
+	out := make(map[string]ComponentDefinition, len(components))
+	for name, comp := range components {
+		format := f.formatFor(name)
+		comp.Base = f.properties(comp.Base, format)
+		comp.Variants = f.variants(comp.Variants, format)
+		comp.Sizes = f.variants(comp.Sizes, format)
+		comp.States = f.variants(comp.States, format)
+		if comp.ContainerOverrides != nil {
+			overrides := make(map[string]map[string]any, len(comp.ContainerOverrides))
+			for query, props := range comp.ContainerOverrides {
+				overrides[query] = f.properties(props, format)
+			}
+			comp.ContainerOverrides = overrides
+		}
+		comp.ForcedColors = f.properties(comp.ForcedColors, format)
+		out[name] = comp
+	}
+	return out
+}
+
+func (f ColorFormatter) variants(defs map[string]VariantDef, format string) map[string]VariantDef {
+	if defs == nil {
+		return nil
+	}
+	out := make(map[string]VariantDef, len(defs))
+	for name, def := range defs {
+		def.Properties = f.properties(def.Properties, format)
+		if def.States != nil {
+			states := make(map[string]State, len(def.States))
+			for sel, st := range def.States {
+				states[sel] = State{Properties: f.properties(st.Properties, format)}
+			}
+			def.States = states
+		}
+		out[name] = def
+	}
+	return out
+}
+
+// properties converts color literals in a property map, descending into
+// nested state blocks ("&:hover": {...})
+func (f ColorFormatter) properties(props map[string]any, format string) map[string]any {
+	if props == nil {
+		return nil
+	}
+	out := make(map[string]any, len(props))
+	for key, val := range props {
+		out[key] = val
+		switch v := val.(type) {
+		case string:
+			if isColorLiteral(v) {
+				if c, ok := f.convert(v, format); ok {
+					out[key] = c
+				}
+			}
+		case map[string]any:
+			out[key] = f.properties(v, format)
+		}
+	}
+	return out
+}
+
+// convert renders s in format, reporting false when s is not a color the
+// build can compute or no format applies. A color already in format is
+// left as written unless a precision is set. A color outside sRGB is
+// gamut mapped for the formats that can only hold sRGB.
+func (f ColorFormatter) convert(s, format string) (string, bool) {
+	if format == "" {
+		return "", false
+	}
+	c, err := colors.Parse(s)
+	if err != nil {
+		return "", false
+	}
+	if c.OriginalFormat() == format && f.Precision < 0 {
+		return s, true
+	}
+	if srgbFormats[format] && !colors.InGamut(c, colors.GamutSRGB) {
+		c = colors.MapToGamut(c, colors.GamutSRGB)
+	}
+	return c.ToCSSPrecision(format, f.Precision), true
+}
+
+// srgbFormats are the output formats limited to the sRGB gamut
+var srgbFormats = map[string]bool{
+	colors.FormatHex: true,
+	colors.FormatRGB: true,
+	colors.FormatHSL: true,
+	colors.FormatHWB: true,
+}
+
+// isColorLiteral reports whether s is written as hex or a color function
+// rather than a keyword
+func isColorLiteral(s string) bool {
+	s = strings.TrimSpace(s)
+	return strings.HasPrefix(s, "#") || (strings.HasSuffix(s, ")") && !strings.HasPrefix(strings.ToLower(s), "var("))
+}
@@ -0,0 +1,196 @@
+// tokenctl/pkg/tokens/colorformat_test.go
+
+package tokens
+
+import (
+	"testing"
+)
+
+func TestColorFormatter_Tokens(t *testing.T) {
+	t.Parallel()
+
+	d := dictFromJSON(t, `{
+		"color": {
+			"$type": "color",
+			"primary": {"$value": "#3b82f6"},
+			"named": {"$value": "red"},
+			"brand": {"$colorFormat": "hex", "main": {"$value": "oklch(62.8% 0.258 29.23)"}}
+		},
+		"font": {"$type": "fontFamily", "hex": {"$value": "#3b82f6"}},
+		"misc": {"keyword": {"$value": "orange"}, "rgb": {"$value": "rgb(255 0 0)"}}
+	}`)
+	resolved := map[string]any{
+		"color.primary":    "#3b82f6",
+		"color.named":      "red",
+		"color.brand.main": "oklch(62.8% 0.258 29.23)",
+		"font.hex":         "#3b82f6",
+		"misc.keyword":     "orange",
+		"misc.rgb":         "rgb(255 0 0)",
+	}
+
+	got := NewColorFormatter(d, "rgb", -1).Tokens(resolved, ExtractTypes(d))
+	want := map[string]any{
+		"color.primary":    "rgb(59, 130, 246)",
+		"color.named":      "rgb(255, 0, 0)",
+		"color.brand.main": "#ff0000",      // $colorFormat on the group wins
+		"font.hex":         "#3b82f6",      // not a color token
+		"misc.keyword":     "orange",       // untyped keyword left alone
+		"misc.rgb":         "rgb(255 0 0)", // already rgb, in modern syntax
+	}
+	for path, w := range want {
+		if got[path] != w {
+			t.Errorf("%s = %v, want %v", path, got[path], w)
+		}
+	}
+	if resolved["color.primary"] != "#3b82f6" {
+		t.Error("Tokens modified its input")
+	}
+}
+
+func TestColorFormatter_GamutMapsSRGBFormats(t *testing.T) {
+	t.Parallel()
+
+	d := NewDictionary()
+	d.Root = map[string]any{"color": map[string]any{"$type": "color"}}
+	resolved := map[string]any{
+		"color.p3":    "color(display-p3 1 0 0)",
+		"color.vivid": "oklch(70% 0.35 150)",
+	}
+
+	tests := []struct {
+		format string
+		want   map[string]string
+	}{
+		{"hex", map[string]string{"color.p3": "#ff0b0c", "color.vivid": "#00c248"}},
+		{"rgb", map[string]string{"color.p3": "rgb(255, 11, 12)", "color.vivid": "rgb(0, 194, 72)"}},
+		{"hsl", map[string]string{"color.p3": "hsl(359.8, 100.0%, 52.2%)", "color.vivid": "hsl(142.2, 100.0%, 38.0%)"}},
+		// P3 holds the color as written
+		{"p3", map[string]string{"color.p3": "color(display-p3 1 0 0)"}},
+	}
+	for _, tt := range tests {
+		got := NewColorFormatter(d, tt.format, -1).Tokens(resolved, map[string]string{"color.p3": "color", "color.vivid": "color"})
+		for path, want := range tt.want {
+			if got[path] != want {
+				t.Errorf("%s: %s = %v, want %v", tt.format, path, got[path], want)
+			}
+		}
+	}
+}
+
+func TestColorFormatter_Precision(t *testing.T) {
+	t.Parallel()
+
+	d := dictFromJSON(t, `{"color": {"$type": "color", "primary": {"$value": "#3b82f6"}}}`)
+	tests := []struct {
+		value     string
+		precision int
+		want      string
+	}{
+		{"#3b82f6", 1, "oklch(62.3% 0.2 259.8)"},
+		// Already oklch: left as written unless a precision is set
+		{"oklch(60% 0.1 200)", -1, "oklch(60% 0.1 200)"},
+		{"OKLCH(60% 0.1 200deg)", -1, "OKLCH(60% 0.1 200deg)"},
+		{"oklch(60% 0.1 200)", 1, "oklch(60.0% 0.1 200.0)"},
+	}
+	for _, tt := range tests {
+		got := NewColorFormatter(d, "oklch", tt.precision).Tokens(map[string]any{"color.primary": tt.value}, ExtractTypes(d))
+		if got["color.primary"] != tt.want {
+			t.Errorf("%s at precision %d = %v, want %s", tt.value, tt.precision, got["color.primary"], tt.want)
+		}
+	}
+}
+
+func TestColorFormatter_Inactive(t *testing.T) {
+	t.Parallel()
+
+	d := NewDictionary()
+	d.Root = map[string]any{"color": map[string]any{"a": map[string]any{"$value": "#fff"}}}
+	f := NewColorFormatter(d, "", -1)
+	if f.Active() {
+		t.Error("formatter with no format or overrides reported active")
+	}
+	if got := f.Tokens(map[string]any{"color.a": "#fff"}, ExtractTypes(d)); got["color.a"] != "#fff" {
+		t.Errorf("color.a = %v, want #fff", got["color.a"])
+	}
+	override := dictFromJSON(t, `{"color": {"$colorFormat": "hex", "a": {"$value": "#fff"}}}`)
+	if !NewColorFormatter(override, "", -1).Active() {
+		t.Error("formatter with a $colorFormat override reported inactive")
+	}
+}
+
+func TestColorFormatter_Keyframes(t *testing.T) {
+	t.Parallel()
+
+	d := dictFromJSON(t, `{
+		"color": {"$type": "color", "primary": {"$value": "#3b82f6"}},
+		"keyframes": {"pulse": {
+			"from": {"background-color": "#ff0000", "opacity": "1"},
+			"to": {"background-color": "{color.primary}"}
+		}}
+	}`)
+	got := NewColorFormatter(d, "hsl", -1).Keyframes(ExtractKeyframes(d))
+	if len(got) != 1 {
+		t.Fatalf("got %d keyframes, want 1", len(got))
+	}
+	if v := got[0].Frames["from"]["background-color"]; v != "hsl(0.0, 100.0%, 50.0%)" {
+		t.Errorf("from background-color = %q", v)
+	}
+	if v := got[0].Frames["from"]["opacity"]; v != "1" {
+		t.Errorf("from opacity = %q, want 1", v)
+	}
+	if v := got[0].Frames["to"]["background-color"]; v != "{color.primary}" {
+		t.Errorf("to background-color = %q, want the reference left in place", v)
+	}
+}
+
+func TestColorFormatter_Components(t *testing.T) {
+	t.Parallel()
+
+	components := map[string]ComponentDefinition{
+		"button": {
+			Base: map[string]any{
+				"color":            "#ffffff",
+				"background-color": "{color.primary}",
+				"&:hover":          map[string]any{"border-color": "rgb(0 0 0)"},
+			},
+			Variants: map[string]VariantDef{
+				"danger": {
+					Class:      "btn-danger",
+					Properties: map[string]any{"background-color": "#ff0000"},
+					States:     map[string]State{":focus": {Properties: map[string]any{"outline-color": "#00ff00"}}},
+				},
+			},
+			ContainerOverrides: map[string]map[string]any{
+				"(min-width: 400px)": {"color": "#000000", "padding": "1rem"},
+			},
+		},
+	}
+
+	got := NewColorFormatter(NewDictionary(), "hex", -1).Components(components)["button"]
+	checks := map[string]any{
+		"base color":       got.Base["color"],
+		"base reference":   got.Base["background-color"],
+		"nested state":     got.Base["&:hover"].(map[string]any)["border-color"],
+		"variant":          got.Variants["danger"].Properties["background-color"],
+		"variant state":    got.Variants["danger"].States[":focus"].Properties["outline-color"],
+		"container":        got.ContainerOverrides["(min-width: 400px)"]["color"],
+		"container length": got.ContainerOverrides["(min-width: 400px)"]["padding"],
+	}
+	want := map[string]any{
+		"base color":       "#ffffff",
+		"base reference":   "{color.primary}",
+		"nested state":     "#000000",
+		"variant":          "#ff0000",
+		"variant state":    "#00ff00",
+		"container":        "#000000",
+		"container length": "1rem",
+	}
+	for name, w := range want {
+		if checks[name] != w {
+			t.Errorf("%s = %v, want %v", name, checks[name], w)
+		}
+	}
+	if components["button"].Base["&:hover"].(map[string]any)["border-color"] != "rgb(0 0 0)" {
+		t.Error("Components modified its input")
+	}
+}
@@ -17,6 +17,7 @@ type ComponentDefinition struct {
 	Sizes              map[string]VariantDef     `json:"sizes"`
 	States             map[string]VariantDef     `json:"states"` // Component states (error, active, etc.)
 	ContainerOverrides map[string]map[string]any `json:"-"`      // $container: query → properties
+	ForcedColors       map[string]any            `json:"-"`      // $forcedColors: properties for forced colors mode
 }
 
 // VariantDef represents a specific variant (primary, outline) or size (sm, lg)
@@ -172,6 +173,11 @@ func walkComponents(node map[string]any, currentPath string, results map[string]
 			}
 		}
 
+		// Extract $forcedColors properties (may nest & and : states)
+		if forced, ok := node["$forcedColors"].(map[string]any); ok {
+			comp.ForcedColors = forced
+		}
+
 		results[currentPath] = comp
 		return nil
 	}
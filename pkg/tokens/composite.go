@@ -0,0 +1,673 @@
+// tokenctl/pkg/tokens/composite.go
+
+package tokens
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Composite token types from the W3C Design Tokens spec. Their $value is
+// an object (or, for shadow, gradient and cubicBezier, an array) rather
+// than a single string, so they need their own validation and their own
+// CSS serialization.
+const (
+	TypeShadow      = "shadow"
+	TypeBorder      = "border"
+	TypeTypography  = "typography"
+	TypeTransition  = "transition"
+	TypeGradient    = "gradient"
+	TypeCubicBezier = "cubicBezier"
+	TypeStrokeStyle = "strokeStyle"
+)
+
+// IsCompositeType reports whether a $type names a composite token type.
+func IsCompositeType(tokenType string) bool {
+	switch tokenType {
+	case TypeShadow, TypeBorder, TypeTypography, TypeTransition,
+		TypeGradient, TypeCubicBezier, TypeStrokeStyle:
+		return true
+	}
+	return false
+}
+
+// strokeStyleKeywords are the string values a strokeStyle may take.
+var strokeStyleKeywords = map[string]bool{
+	"solid":  true,
+	"dashed": true,
+	"dotted": true,
+	"double": true,
+	"groove": true,
+	"ridge":  true,
+	"outset": true,
+	"inset":  true,
+}
+
+// fontWeightKeywords are the named weights the spec allows in place of
+// a numeric fontWeight.
+var fontWeightKeywords = map[string]int{
+	"thin":        100,
+	"hairline":    100,
+	"extra-light": 200,
+	"ultra-light": 200,
+	"light":       300,
+	"normal":      400,
+	"regular":     400,
+	"book":        400,
+	"medium":      500,
+	"semi-bold":   600,
+	"demi-bold":   600,
+	"bold":        700,
+	"extra-bold":  800,
+	"ultra-bold":  800,
+	"black":       900,
+	"heavy":       900,
+	"extra-black": 950,
+	"ultra-black": 950,
+}
+
+// isRawValue reports whether a composite $value was authored as a plain
+// string: either a reference/expression resolved later, or a CSS value
+// written out by hand ("0 1px 2px 0 rgb(0 0 0 / 0.05)"). Both are left to
+// the author; only object and array forms are checked field by field.
+func isRawValue(value any) bool {
+	_, ok := value.(string)
+	return ok
+}
+
+// isReference reports whether a string value contains a {token} reference.
+func isReference(s string) bool {
+	return strings.Contains(s, "{") && strings.Contains(s, "}")
+}
+
+// validateComposite checks a composite token's $value against the
+// sub-field schema for its type.
+func validateComposite(tokenType string, value any) error {
+	if isRawValue(value) {
+		return nil
+	}
+
+	switch tokenType {
+	case TypeShadow:
+		return validateShadow(value)
+	case TypeBorder:
+		return validateBorder(value)
+	case TypeTypography:
+		return validateTypography(value)
+	case TypeTransition:
+		return validateTransition(value)
+	case TypeGradient:
+		return validateGradient(value)
+	case TypeCubicBezier:
+		return validateCubicBezier(value)
+	case TypeStrokeStyle:
+		return validateStrokeStyle(value)
+	}
+	return nil
+}
+
+// validateShadow accepts a single shadow object or an array of them.
+func validateShadow(value any) error {
+	if list, ok := value.([]any); ok {
+		if len(list) == 0 {
+			return fmt.Errorf("shadow array cannot be empty")
+		}
+		for i, item := range list {
+			if err := validateShadowLayer(item); err != nil {
+				return fmt.Errorf("layer %d: %w", i, err)
+			}
+		}
+		return nil
+	}
+	return validateShadowLayer(value)
+}
+
+func validateShadowLayer(value any) error {
+	if s, ok := value.(string); ok && isReference(s) {
+		return nil
+	}
+	obj, ok := value.(map[string]any)
+	if !ok {
+		return fmt.Errorf("expected object, got %T", value)
+	}
+	if err := requireFields(obj, "color", "offsetX", "offsetY"); err != nil {
+		return err
+	}
+	if err := validateColorFormat(obj["color"]); err != nil {
+		return fmt.Errorf("color: %w", err)
+	}
+	for _, field := range []string{"offsetX", "offsetY", "blur", "spread"} {
+		if v, ok := obj[field]; ok {
+			if err := validateDimension(v); err != nil {
+				return fmt.Errorf("%s: %w", field, err)
+			}
+		}
+	}
+	if v, ok := obj["inset"]; ok {
+		if _, isBool := v.(bool); !isBool {
+			return fmt.Errorf("inset: expected boolean, got %T", v)
+		}
+	}
+	return nil
+}
+
+func validateBorder(value any) error {
+	obj, ok := value.(map[string]any)
+	if !ok {
+		return fmt.Errorf("expected object, got %T", value)
+	}
+	if err := requireFields(obj, "color", "width", "style"); err != nil {
+		return err
+	}
+	if err := validateColorFormat(obj["color"]); err != nil {
+		return fmt.Errorf("color: %w", err)
+	}
+	if err := validateDimension(obj["width"]); err != nil {
+		return fmt.Errorf("width: %w", err)
+	}
+	if err := validateStrokeStyle(obj["style"]); err != nil {
+		return fmt.Errorf("style: %w", err)
+	}
+	return nil
+}
+
+func validateTypography(value any) error {
+	obj, ok := value.(map[string]any)
+	if !ok {
+		return fmt.Errorf("expected object, got %T", value)
+	}
+	if err := requireFields(obj, "fontFamily", "fontSize"); err != nil {
+		return err
+	}
+	if err := validateFontFamily(obj["fontFamily"]); err != nil {
+		return fmt.Errorf("fontFamily: %w", err)
+	}
+	if err := validateDimension(obj["fontSize"]); err != nil {
+		return fmt.Errorf("fontSize: %w", err)
+	}
+	if v, ok := obj["fontWeight"]; ok {
+		if err := validateFontWeight(v); err != nil {
+			return fmt.Errorf("fontWeight: %w", err)
+		}
+	}
+	if v, ok := obj["letterSpacing"]; ok {
+		if err := validateDimension(v); err != nil {
+			return fmt.Errorf("letterSpacing: %w", err)
+		}
+	}
+	if v, ok := obj["lineHeight"]; ok {
+		// The spec says number; a dimension is legal CSS and common
+		// in hand-written files, so accept either.
+		if validateNumber(v) != nil && validateDimension(v) != nil {
+			return fmt.Errorf("lineHeight: expected number or dimension, got %v", v)
+		}
+	}
+	return nil
+}
+
+func validateTransition(value any) error {
+	obj, ok := value.(map[string]any)
+	if !ok {
+		return fmt.Errorf("expected object, got %T", value)
+	}
+	if err := requireFields(obj, "duration"); err != nil {
+		return err
+	}
+	for _, field := range []string{"duration", "delay"} {
+		if v, ok := obj[field]; ok {
+			if err := validateDuration(v); err != nil {
+				return fmt.Errorf("%s: %w", field, err)
+			}
+		}
+	}
+	if v, ok := obj["timingFunction"]; ok {
+		if err := validateCubicBezier(v); err != nil {
+			return fmt.Errorf("timingFunction: %w", err)
+		}
+	}
+	return nil
+}
+
+func validateGradient(value any) error {
+	stops, ok := value.([]any)
+	if !ok {
+		return fmt.Errorf("expected array of stops, got %T", value)
+	}
+	if len(stops) < 2 {
+		return fmt.Errorf("gradient needs at least 2 stops, got %d", len(stops))
+	}
+	for i, item := range stops {
+		stop, ok := item.(map[string]any)
+		if !ok {
+			return fmt.Errorf("stop %d: expected object, got %T", i, item)
+		}
+		if err := requireFields(stop, "color", "position"); err != nil {
+			return fmt.Errorf("stop %d: %w", i, err)
+		}
+		if err := validateColorFormat(stop["color"]); err != nil {
+			return fmt.Errorf("stop %d: color: %w", i, err)
+		}
+		if err := validateUnitInterval(stop["position"]); err != nil {
+			return fmt.Errorf("stop %d: position: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// validateCubicBezier checks a [P1x, P1y, P2x, P2y] control-point array.
+// The x coordinates must lie in [0, 1]; y may overshoot.
+func validateCubicBezier(value any) error {
+	if s, ok := value.(string); ok {
+		if isReference(s) {
+			return nil
+		}
+		return fmt.Errorf("expected array of 4 numbers, got string: %s", s)
+	}
+	points, ok := value.([]any)
+	if !ok {
+		return fmt.Errorf("expected array of 4 numbers, got %T", value)
+	}
+	if len(points) != 4 {
+		return fmt.Errorf("expected 4 control points, got %d", len(points))
+	}
+	for i, p := range points {
+		if s, ok := p.(string); ok && isReference(s) {
+			continue
+		}
+		n, ok := toFloat64(p)
+		if !ok {
+			return fmt.Errorf("control point %d: expected number, got %T", i, p)
+		}
+		if (i == 0 || i == 2) && (n < 0 || n > 1) {
+			return fmt.Errorf("control point %d: x must be within [0, 1], got %v", i, n)
+		}
+	}
+	return nil
+}
+
+func validateStrokeStyle(value any) error {
+	switch v := value.(type) {
+	case string:
+		if isReference(v) || strokeStyleKeywords[v] {
+			return nil
+		}
+		return fmt.Errorf("unknown stroke style %q", v)
+	case map[string]any:
+		if err := requireFields(v, "dashArray", "lineCap"); err != nil {
+			return err
+		}
+		dashes, ok := v["dashArray"].([]any)
+		if !ok || len(dashes) == 0 {
+			return fmt.Errorf("dashArray: expected non-empty array of dimensions")
+		}
+		for i, d := range dashes {
+			if err := validateDimension(d); err != nil {
+				return fmt.Errorf("dashArray item %d: %w", i, err)
+			}
+		}
+		switch v["lineCap"] {
+		case "round", "butt", "square":
+		default:
+			return fmt.Errorf("lineCap: expected round, butt or square, got %v", v["lineCap"])
+		}
+		return nil
+	default:
+		return fmt.Errorf("expected string or object, got %T", value)
+	}
+}
+
+// validateDuration ensures a value is a time dimension (ms or s).
+func validateDuration(value any) error {
+	s, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("expected string, got %T", value)
+	}
+	if isReference(s) {
+		return nil
+	}
+	dim, err := ParseDimension(s)
+	if err != nil {
+		return err
+	}
+	if dim.Unit != "ms" && dim.Unit != "s" {
+		return fmt.Errorf("expected ms or s, got %q", s)
+	}
+	return nil
+}
+
+// validateFontWeight accepts a number in [1, 1000] or a named weight.
+func validateFontWeight(value any) error {
+	if s, ok := value.(string); ok {
+		if isReference(s) {
+			return nil
+		}
+		if _, ok := fontWeightKeywords[s]; ok {
+			return nil
+		}
+		return fmt.Errorf("unknown font weight %q", s)
+	}
+	n, ok := toFloat64(value)
+	if !ok {
+		return fmt.Errorf("expected number or keyword, got %T", value)
+	}
+	if n < 1 || n > 1000 {
+		return fmt.Errorf("must be within [1, 1000], got %v", n)
+	}
+	return nil
+}
+
+// validateUnitInterval accepts a number in [0, 1].
+func validateUnitInterval(value any) error {
+	if s, ok := value.(string); ok && isReference(s) {
+		return nil
+	}
+	n, ok := toFloat64(value)
+	if !ok {
+		return fmt.Errorf("expected number, got %T", value)
+	}
+	if n < 0 || n > 1 {
+		return fmt.Errorf("must be within [0, 1], got %v", n)
+	}
+	return nil
+}
+
+// requireFields reports the first missing field, in the order given.
+func requireFields(obj map[string]any, fields ...string) error {
+	for _, f := range fields {
+		if _, ok := obj[f]; !ok {
+			return fmt.Errorf("missing required field %q", f)
+		}
+	}
+	return nil
+}
+
+// InferCompositeType guesses the composite type of a value from its
+// shape, for callers that only hold the resolved value and not the
+// token's $type. Returns "" when the value does not look composite.
+// cubicBezier cannot be told apart from any other 4-number array, so it
+// is never inferred.
+func InferCompositeType(value any) string {
+	switch v := value.(type) {
+	case map[string]any:
+		return inferObjectType(v)
+	case []any:
+		if len(v) == 0 {
+			return ""
+		}
+		first, ok := v[0].(map[string]any)
+		if !ok {
+			return ""
+		}
+		if _, hasPos := first["position"]; hasPos {
+			return TypeGradient
+		}
+		if inferObjectType(first) == TypeShadow {
+			return TypeShadow
+		}
+	}
+	return ""
+}
+
+func inferObjectType(obj map[string]any) string {
+	has := func(k string) bool { _, ok := obj[k]; return ok }
+	switch {
+	case has("offsetX") || has("offsetY"):
+		return TypeShadow
+	case has("fontFamily") || has("fontSize"):
+		return TypeTypography
+	case has("width") && has("style"):
+		return TypeBorder
+	case has("duration") || has("timingFunction"):
+		return TypeTransition
+	case has("dashArray"):
+		return TypeStrokeStyle
+	}
+	return ""
+}
+
+// FormatCompositeCSS serializes a resolved composite value to the CSS
+// value its custom property should carry: a box-shadow list, a font
+// shorthand, a transition shorthand, and so on. When tokenType is empty
+// the type is inferred from the value's shape. The second result is
+// false when the value is not a composite this function understands,
+// in which case the caller should fall back to its own serialization.
+func FormatCompositeCSS(tokenType string, value any) (string, bool) {
+	if s, ok := value.(string); ok {
+		// Hand-written CSS in a composite-typed token passes through.
+		return s, IsCompositeType(tokenType)
+	}
+	if tokenType == "" {
+		tokenType = InferCompositeType(value)
+	}
+
+	switch tokenType {
+	case TypeShadow:
+		return formatShadow(value)
+	case TypeBorder:
+		return formatBorder(value)
+	case TypeTypography:
+		return formatTypography(value)
+	case TypeTransition:
+		return formatTransition(value)
+	case TypeGradient:
+		return formatGradient(value)
+	case TypeCubicBezier:
+		return formatCubicBezier(value)
+	case TypeStrokeStyle:
+		return formatStrokeStyle(value), true
+	}
+	return "", false
+}
+
+func formatShadow(value any) (string, bool) {
+	layers, ok := value.([]any)
+	if !ok {
+		layers = []any{value}
+	}
+	parts := make([]string, 0, len(layers))
+	for _, layer := range layers {
+		if s, ok := layer.(string); ok {
+			parts = append(parts, s)
+			continue
+		}
+		obj, ok := layer.(map[string]any)
+		if !ok {
+			return "", false
+		}
+		var fields []string
+		if inset, _ := obj["inset"].(bool); inset {
+			fields = append(fields, "inset")
+		}
+		for _, f := range []string{"offsetX", "offsetY", "blur", "spread"} {
+			if v, ok := obj[f]; ok {
+				fields = append(fields, formatScalar(v))
+			} else {
+				fields = append(fields, "0")
+			}
+		}
+		if c, ok := obj["color"]; ok {
+			fields = append(fields, formatScalar(c))
+		}
+		parts = append(parts, strings.Join(fields, " "))
+	}
+	return strings.Join(parts, ", "), true
+}
+
+func formatBorder(value any) (string, bool) {
+	obj, ok := value.(map[string]any)
+	if !ok {
+		return "", false
+	}
+	fields := []string{formatScalar(obj["width"]), formatStrokeStyle(obj["style"])}
+	if c, ok := obj["color"]; ok {
+		fields = append(fields, formatScalar(c))
+	}
+	return strings.Join(fields, " "), true
+}
+
+// formatTypography emits the CSS font shorthand:
+// [style] [weight] size[/line-height] family. letterSpacing has no place
+// in the shorthand and is dropped; consumers that need it reference the
+// sub-field directly.
+func formatTypography(value any) (string, bool) {
+	obj, ok := value.(map[string]any)
+	if !ok {
+		return "", false
+	}
+	var fields []string
+	if v, ok := obj["fontStyle"]; ok {
+		fields = append(fields, formatScalar(v))
+	}
+	if v, ok := obj["fontWeight"]; ok {
+		if s, isStr := v.(string); isStr {
+			if n, known := fontWeightKeywords[s]; known {
+				v = n
+			}
+		}
+		fields = append(fields, formatScalar(v))
+	}
+	size := formatScalar(obj["fontSize"])
+	if lh, ok := obj["lineHeight"]; ok {
+		size += "/" + formatScalar(lh)
+	}
+	fields = append(fields, size)
+	fields = append(fields, formatFontFamily(obj["fontFamily"]))
+	return strings.Join(fields, " "), true
+}
+
+// formatTransition emits duration, timing function and delay. The
+// transition property itself is left to the consuming rule so one token
+// can serve several properties.
+func formatTransition(value any) (string, bool) {
+	obj, ok := value.(map[string]any)
+	if !ok {
+		return "", false
+	}
+	fields := []string{formatScalar(obj["duration"])}
+	if tf, ok := obj["timingFunction"]; ok {
+		if css, ok := formatCubicBezier(tf); ok {
+			fields = append(fields, css)
+		}
+	}
+	if d, ok := obj["delay"]; ok {
+		fields = append(fields, formatScalar(d))
+	}
+	return strings.Join(fields, " "), true
+}
+
+// formatGradient emits a color-stop list ("#fff 0%, #000 100%") for use
+// inside linear-gradient()/radial-gradient(), which carry the geometry.
+func formatGradient(value any) (string, bool) {
+	stops, ok := value.([]any)
+	if !ok {
+		return "", false
+	}
+	parts := make([]string, 0, len(stops))
+	for _, item := range stops {
+		stop, ok := item.(map[string]any)
+		if !ok {
+			return "", false
+		}
+		part := formatScalar(stop["color"])
+		if pos, ok := toFloat64(stop["position"]); ok {
+			part += " " + Dimension{Value: pos * 100, Unit: "%"}.String()
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", "), true
+}
+
+func formatCubicBezier(value any) (string, bool) {
+	if s, ok := value.(string); ok {
+		return s, true
+	}
+	points, ok := value.([]any)
+	if !ok || len(points) != 4 {
+		return "", false
+	}
+	parts := make([]string, len(points))
+	for i, p := range points {
+		parts[i] = formatScalar(p)
+	}
+	return "cubic-bezier(" + strings.Join(parts, ", ") + ")", true
+}
+
+// formatStrokeStyle returns the keyword for string styles. CSS has no
+// way to express a custom dash pattern on a border, so object styles
+// fall back to "dashed", as the spec recommends.
+func formatStrokeStyle(value any) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return "dashed"
+}
+
+// formatFontFamily joins a family list, quoting names with spaces.
+func formatFontFamily(value any) string {
+	list, ok := value.([]any)
+	if !ok {
+		return formatScalar(value)
+	}
+	parts := make([]string, len(list))
+	for i, item := range list {
+		name := formatScalar(item)
+		if strings.Contains(name, " ") && !strings.HasPrefix(name, `"`) && !strings.HasPrefix(name, "'") && !strings.HasPrefix(name, "var(") {
+			name = `"` + name + `"`
+		}
+		parts[i] = name
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatScalar renders a leaf sub-field value, dropping the trailing
+// ".0" fmt gives whole floats.
+func formatScalar(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return Dimension{Value: v}.String()
+	case int:
+		return fmt.Sprintf("%d", v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// ExtractTypes returns the effective $type of every token in the
+// dictionary, with group-level $type inherited by descendants. Tokens
+// with no type anywhere up their chain are omitted.
+func ExtractTypes(d *Dictionary) map[string]string {
+	types := make(map[string]string)
+	extractTypesRecursive(d.Root, "", "", types)
+	return types
+}
+
+func extractTypesRecursive(node map[string]any, currentPath, inheritedType string, types map[string]string) {
+	currentType := inheritedType
+	if t, ok := node["$type"].(string); ok {
+		currentType = t
+	}
+
+	if IsToken(node) {
+		if currentType != "" {
+			types[currentPath] = currentType
+		}
+		return
+	}
+
+	for key, val := range node {
+		if strings.HasPrefix(key, "$") {
+			continue
+		}
+		childMap, ok := val.(map[string]any)
+		if !ok {
+			continue
+		}
+		childPath := key
+		if currentPath != "" {
+			childPath = currentPath + "." + key
+		}
+		extractTypesRecursive(childMap, childPath, currentType, types)
+	}
+}
@@ -0,0 +1,330 @@
+// tokenctl/pkg/tokens/composite_test.go
+
+package tokens
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateComposite(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		tokenType string
+		value     any
+		wantErr   string // substring; empty means valid
+	}{
+		{
+			name:      "shadow object",
+			tokenType: TypeShadow,
+			value:     map[string]any{"color": "#00000080", "offsetX": "0", "offsetY": "1px", "blur": "2px", "spread": "0"},
+		},
+		{
+			name:      "shadow layers",
+			tokenType: TypeShadow,
+			value: []any{
+				map[string]any{"color": "{color.shadow}", "offsetX": "0", "offsetY": "4px", "blur": "6px"},
+				map[string]any{"color": "#000", "offsetX": "0", "offsetY": "2px", "blur": "4px", "inset": true},
+			},
+		},
+		{
+			name:      "shadow raw css string",
+			tokenType: TypeShadow,
+			value:     "0 1px 2px 0 rgb(0 0 0 / 0.05)",
+		},
+		{
+			name:      "shadow missing color",
+			tokenType: TypeShadow,
+			value:     map[string]any{"offsetX": "0", "offsetY": "1px"},
+			wantErr:   `missing required field "color"`,
+		},
+		{
+			name:      "shadow bad offset",
+			tokenType: TypeShadow,
+			value:     map[string]any{"color": "#000", "offsetX": "wide", "offsetY": "1px"},
+			wantErr:   "offsetX",
+		},
+		{
+			name:      "border",
+			tokenType: TypeBorder,
+			value:     map[string]any{"color": "#3b82f6", "width": "1px", "style": "solid"},
+		},
+		{
+			name:      "border dashed object style",
+			tokenType: TypeBorder,
+			value: map[string]any{"color": "#3b82f6", "width": "1px", "style": map[string]any{
+				"dashArray": []any{"4px", "2px"}, "lineCap": "round",
+			}},
+		},
+		{
+			name:      "border unknown style",
+			tokenType: TypeBorder,
+			value:     map[string]any{"color": "#3b82f6", "width": "1px", "style": "wavy"},
+			wantErr:   "style",
+		},
+		{
+			name:      "typography",
+			tokenType: TypeTypography,
+			value: map[string]any{
+				"fontFamily": []any{"Inter", "sans-serif"}, "fontSize": "1rem",
+				"fontWeight": "semi-bold", "lineHeight": 1.5, "letterSpacing": "0",
+			},
+		},
+		{
+			name:      "typography weight out of range",
+			tokenType: TypeTypography,
+			value:     map[string]any{"fontFamily": "Inter", "fontSize": "1rem", "fontWeight": 1200.0},
+			wantErr:   "fontWeight",
+		},
+		{
+			name:      "transition",
+			tokenType: TypeTransition,
+			value:     map[string]any{"duration": "200ms", "delay": "0ms", "timingFunction": []any{0.4, 0.0, 0.2, 1.0}},
+		},
+		{
+			name:      "transition bad duration unit",
+			tokenType: TypeTransition,
+			value:     map[string]any{"duration": "200px"},
+			wantErr:   "duration",
+		},
+		{
+			name:      "gradient",
+			tokenType: TypeGradient,
+			value: []any{
+				map[string]any{"color": "#fff", "position": 0.0},
+				map[string]any{"color": "#000", "position": 1.0},
+			},
+		},
+		{
+			name:      "gradient position out of range",
+			tokenType: TypeGradient,
+			value: []any{
+				map[string]any{"color": "#fff", "position": 0.0},
+				map[string]any{"color": "#000", "position": 1.5},
+			},
+			wantErr: "position",
+		},
+		{
+			name:      "cubicBezier x out of range",
+			tokenType: TypeCubicBezier,
+			value:     []any{1.2, 0.0, 0.2, 1.0},
+			wantErr:   "x must be within",
+		},
+		{
+			name:      "cubicBezier y overshoot allowed",
+			tokenType: TypeCubicBezier,
+			value:     []any{0.3, -0.5, 0.7, 1.5},
+		},
+		{
+			name:      "strokeStyle keyword",
+			tokenType: TypeStrokeStyle,
+			value:     "dotted",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := validateComposite(tt.tokenType, tt.value)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want substring %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFormatCompositeCSS(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		tokenType string
+		value     any
+		want      string
+		wantOK    bool
+	}{
+		{
+			name:      "shadow list",
+			tokenType: TypeShadow,
+			value: []any{
+				map[string]any{"color": "#0000001a", "offsetX": "0", "offsetY": "4px", "blur": "6px", "spread": "-1px"},
+				map[string]any{"color": "#000", "offsetX": "0", "offsetY": "2px", "blur": "4px", "inset": true},
+			},
+			want:   "0 4px 6px -1px #0000001a, inset 0 2px 4px 0 #000",
+			wantOK: true,
+		},
+		{
+			name:   "shadow inferred from shape",
+			value:  map[string]any{"color": "#000", "offsetX": "1px", "offsetY": "1px", "blur": "0"},
+			want:   "1px 1px 0 0 #000",
+			wantOK: true,
+		},
+		{
+			name:      "border with dash object falls back to dashed",
+			tokenType: TypeBorder,
+			value: map[string]any{"color": "#ccc", "width": "2px", "style": map[string]any{
+				"dashArray": []any{"4px"}, "lineCap": "butt",
+			}},
+			want:   "2px dashed #ccc",
+			wantOK: true,
+		},
+		{
+			name:      "typography font shorthand",
+			tokenType: TypeTypography,
+			value: map[string]any{
+				"fontFamily": []any{"Inter Variable", "sans-serif"}, "fontSize": "1rem",
+				"fontWeight": "bold", "lineHeight": 1.5, "letterSpacing": "0.01em",
+			},
+			want:   `700 1rem/1.5 "Inter Variable", sans-serif`,
+			wantOK: true,
+		},
+		{
+			name:      "transition shorthand",
+			tokenType: TypeTransition,
+			value:     map[string]any{"duration": "200ms", "delay": "50ms", "timingFunction": []any{0.4, 0.0, 0.2, 1.0}},
+			want:      "200ms cubic-bezier(0.4, 0, 0.2, 1) 50ms",
+			wantOK:    true,
+		},
+		{
+			name:      "gradient stop list",
+			tokenType: TypeGradient,
+			value: []any{
+				map[string]any{"color": "#fff", "position": 0.0},
+				map[string]any{"color": "#000", "position": 0.75},
+			},
+			want:   "#fff 0%, #000 75%",
+			wantOK: true,
+		},
+		{
+			name:      "cubicBezier",
+			tokenType: TypeCubicBezier,
+			value:     []any{0.0, 0.0, 0.58, 1.0},
+			want:      "cubic-bezier(0, 0, 0.58, 1)",
+			wantOK:    true,
+		},
+		{
+			name:      "raw string passes through",
+			tokenType: TypeShadow,
+			value:     "0 1px 2px 0 rgb(0 0 0 / 0.05)",
+			want:      "0 1px 2px 0 rgb(0 0 0 / 0.05)",
+			wantOK:    true,
+		},
+		{
+			name:  "plain string is not composite",
+			value: "#3b82f6",
+		},
+		{
+			name:  "number array is not inferred",
+			value: []any{0.4, 0.0, 0.2, 1.0},
+		},
+		{
+			name:  "unrelated map",
+			value: map[string]any{"should": "skip"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := FormatCompositeCSS(tt.tokenType, tt.value)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v (got %q)", ok, tt.wantOK, got)
+			}
+			if ok && got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolver_CompositeSubFields(t *testing.T) {
+	t.Parallel()
+	dict := NewDictionary()
+	dict.Root = map[string]any{
+		"color": map[string]any{
+			"shadow": map[string]any{"$value": "#0000001a"},
+		},
+		"size": map[string]any{
+			"sm": map[string]any{"$value": "2px"},
+		},
+		"shadow": map[string]any{
+			"$type": "shadow",
+			"sm": map[string]any{
+				"$value": map[string]any{
+					"color":   "{color.shadow}",
+					"offsetX": "0",
+					"offsetY": "{size.sm}",
+					"blur":    "calc({size.sm} * 2)",
+				},
+			},
+			"broken": map[string]any{
+				"$value": []any{
+					map[string]any{"color": "{color.missing}", "offsetX": "0", "offsetY": "1px"},
+				},
+			},
+		},
+	}
+
+	r, err := NewResolver(dict)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := r.ResolveValue("shadow.sm", r.flatTokens["shadow.sm"])
+	if err != nil {
+		t.Fatalf("resolve failed: %v", err)
+	}
+	obj, ok := got.(map[string]any)
+	if !ok {
+		t.Fatalf("expected map, got %T", got)
+	}
+	if obj["color"] != "#0000001a" || obj["offsetY"] != "2px" || obj["blur"] != "4px" {
+		t.Errorf("sub-fields not resolved: %v", obj)
+	}
+
+	// The dictionary itself must not be rewritten by resolution.
+	raw := dict.Root["shadow"].(map[string]any)["sm"].(map[string]any)["$value"].(map[string]any)
+	if raw["color"] != "{color.shadow}" {
+		t.Errorf("resolution mutated the source dictionary: %v", raw)
+	}
+
+	if _, err := r.ResolveValue("shadow.broken", r.flatTokens["shadow.broken"]); err == nil ||
+		!strings.Contains(err.Error(), "reference not found: color.missing") {
+		t.Errorf("expected broken sub-field reference error, got %v", err)
+	}
+}
+
+func TestValidator_TypeValidation_Composite(t *testing.T) {
+	t.Parallel()
+	dict := &Dictionary{
+		Root: map[string]any{
+			"typography": map[string]any{
+				"$type": "typography",
+				"body": map[string]any{
+					"$value": map[string]any{"fontFamily": "Inter", "fontSize": "1rem"},
+				},
+				"broken": map[string]any{
+					"$value": map[string]any{"fontFamily": "Inter"},
+				},
+			},
+		},
+		SourceFiles: map[string]string{},
+	}
+
+	errs, err := Validate(dict)
+	if err != nil {
+		t.Fatalf("Validation failed to run: %v", err)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %d: %v", len(errs), errs)
+	}
+	if errs[0].Path != "typography.broken" || !strings.Contains(errs[0].Message, "invalid typography") {
+		t.Errorf("unexpected error: %v", errs[0])
+	}
+}
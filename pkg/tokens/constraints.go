@@ -15,6 +15,10 @@ type Constraint struct {
 	MinNum   *float64
 	MaxNum   *float64
 	IsNumber bool // true if constraints are pure numbers, false if dimensions
+
+	// RootFontSize converts rem/em values for comparison against px
+	// bounds (and vice versa); zero means DefaultRootFontSize
+	RootFontSize float64
 }
 
 // ParseConstraints extracts $min and $max from a token definition
@@ -192,20 +196,25 @@ func (c *Constraint) checkDimension(value any) error {
 		return fmt.Errorf("expected dimension string, got %T", value)
 	}
 
-	// Check unit compatibility
-	if c.Min != nil && dim.Unit != c.Min.Unit {
-		return fmt.Errorf("value unit %q doesn't match constraint unit %q", dim.Unit, c.Min.Unit)
-	}
-	if c.Max != nil && dim.Unit != c.Max.Unit {
-		return fmt.Errorf("value unit %q doesn't match constraint unit %q", dim.Unit, c.Max.Unit)
-	}
-
-	if c.Min != nil && dim.Value < c.Min.Value {
-		return fmt.Errorf("value %s is less than minimum %s", dim.String(), c.Min.String())
+	// Compare in the constraint's unit, converting px/rem/em/pt as needed
+	if c.Min != nil {
+		v, err := dim.ConvertTo(c.Min.Unit, c.RootFontSize)
+		if err != nil {
+			return fmt.Errorf("value unit %q doesn't match constraint unit %q", dim.Unit, c.Min.Unit)
+		}
+		if v.Value < c.Min.Value {
+			return fmt.Errorf("value %s is less than minimum %s", dim.String(), c.Min.String())
+		}
 	}
 
-	if c.Max != nil && dim.Value > c.Max.Value {
-		return fmt.Errorf("value %s is greater than maximum %s", dim.String(), c.Max.String())
+	if c.Max != nil {
+		v, err := dim.ConvertTo(c.Max.Unit, c.RootFontSize)
+		if err != nil {
+			return fmt.Errorf("value unit %q doesn't match constraint unit %q", dim.Unit, c.Max.Unit)
+		}
+		if v.Value > c.Max.Value {
+			return fmt.Errorf("value %s is greater than maximum %s", dim.String(), c.Max.String())
+		}
 	}
 
 	return nil
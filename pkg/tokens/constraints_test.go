@@ -397,3 +397,39 @@ func TestParseConstraints_UnsupportedType(t *testing.T) {
 		t.Error("expected error for unsupported constraint type")
 	}
 }
+
+func TestConstraint_CheckValue_ConvertsUnits(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		root    float64
+		value   string
+		wantErr bool
+	}{
+		{"rem within px bounds", 0, "1rem", false},
+		{"rem below px minimum", 0, "0.5rem", true},
+		{"custom root moves rem into range", 10, "1.5rem", false},
+		{"custom root moves rem out of range", 10, "1rem", true},
+		{"pt within px bounds", 0, "12pt", false},
+		{"percent cannot be compared", 0, "50%", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			constraint, err := ParseConstraints(map[string]any{"$min": "12px", "$max": "24px"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			constraint.RootFontSize = tt.root
+
+			err = constraint.CheckValue(tt.value)
+			if tt.wantErr && err == nil {
+				t.Errorf("CheckValue(%q) expected error", tt.value)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("CheckValue(%q) unexpected error: %v", tt.value, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,357 @@
+// tokenctl/pkg/tokens/contrast.go
+
+package tokens
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dmoose/tokenctl/pkg/colors"
+)
+
+// WCAG levels accepted by --contrast and $minContrast, either of which
+// may also be a bare ratio or an APCA Lc value ("Lc 60")
+const (
+	ContrastAA      = "AA"
+	ContrastAAA     = "AAA"
+	ContrastAALarge = "AA-large"
+	// AAA for large text needs the same ratio as AA for normal text
+	ContrastAAALarge = "AAA-large"
+)
+
+var contrastLevels = map[string]float64{
+	ContrastAA:       colors.WCAGAANormal,
+	ContrastAAA:      colors.WCAGAAANormal,
+	ContrastAALarge:  colors.WCAGAALarge,
+	ContrastAAALarge: colors.WCAGAAALarge,
+}
+
+// apcaMinimum matches an APCA requirement: "Lc60", "Lc 60", "lc 75"
+var apcaMinimum = regexp.MustCompile(`^(?i:lc)\s*(\d+(?:\.\d+)?)$`)
+
+// MinContrast is a required contrast under one algorithm
+type MinContrast struct {
+	Algorithm string  // colors.AlgorithmWCAG2 or colors.AlgorithmAPCA
+	Value     float64 // ratio for WCAG 2, |Lc| for APCA
+	Level     string  // WCAG level the ratio came from, empty for a bare ratio
+}
+
+func (m MinContrast) String() string {
+	if m.Algorithm == colors.AlgorithmAPCA {
+		return fmt.Sprintf("Lc %.4g", m.Value)
+	}
+	s := fmt.Sprintf("%.4g:1", m.Value)
+	if m.Level != "" {
+		s += " (" + m.Level + ")"
+	}
+	return s
+}
+
+// Measure returns the contrast of fg on bg under m's algorithm: the WCAG 2
+// ratio, or |Lc| for APCA
+func (m MinContrast) Measure(fg, bg colors.Color) float64 {
+	if m.Algorithm == colors.AlgorithmAPCA {
+		return math.Abs(colors.APCAContrast(fg, bg))
+	}
+	return colors.ContrastRatio(fg, bg)
+}
+
+// ContrastOptions selects which pairs CheckContrast examines
+type ContrastOptions struct {
+	// Level checks every X / X-content pair at this WCAG level, ratio or
+	// APCA Lc. Empty checks only pairs declared with $contrastWith.
+	Level string
+
+	// RequireContent reports color roles that have no -content partner
+	RequireContent bool
+}
+
+// ContrastIssue is a foreground/background pair that fails its required
+// contrast, or a color role missing its -content partner
+type ContrastIssue struct {
+	Path       string  // foreground token
+	Against    string  // background token; for a missing partner, the expected -content name
+	Ratio      float64 // measured contrast (|Lc| under APCA), 0 for a missing partner
+	Min        MinContrast
+	Missing    bool   // Path is a color role with no -content partner
+	Theme      string // theme the pair was checked in, empty for the base
+	SourceFile string
+	Line       int
+	Column     int
+}
+
+func (c ContrastIssue) Error() string {
+	msg := c.message()
+	if c.Theme != "" {
+		msg += " in theme " + c.Theme
+	}
+	if c.SourceFile != "" {
+		loc := Position{File: c.SourceFile, Line: c.Line, Column: c.Column}
+		return fmt.Sprintf("%s [%s]: %s", c.Path, loc, msg)
+	}
+	return fmt.Sprintf("%s: %s", c.Path, msg)
+}
+
+func (c ContrastIssue) message() string {
+	if c.Missing {
+		return fmt.Sprintf("no content color %s", c.Against)
+	}
+	if c.Min.Algorithm == colors.AlgorithmAPCA {
+		return fmt.Sprintf("contrast Lc %.1f against %s is below %s", c.Ratio, c.Against, c.Min)
+	}
+	return fmt.Sprintf("contrast %.2f:1 against %s is below %s", c.Ratio, c.Against, c.Min)
+}
+
+// scaleStepSuffix matches DaisyUI's numbered surfaces (base-100,
+// base-200, ...), which share one content color (base-content)
+var scaleStepSuffix = regexp.MustCompile(`-\d+$`)
+
+// ContentPartner returns the -content token that sits on path:
+// color.primary -> color.primary-content, color.base-200 ->
+// color.base-content
+func ContentPartner(path string) string {
+	return scaleStepSuffix.ReplaceAllString(path, "") + "-content"
+}
+
+// CheckContrast measures foreground/background pairs in a dictionary:
+// every token declaring $contrastWith, and with opts.Level set, every
+// X / X-content pair. Pairs whose values are not plain colors (var(),
+// color-mix(), unresolvable references) are skipped; Validate reports
+// those problems. Issues are sorted by path.
+func CheckContrast(d *Dictionary, opts ContrastOptions) ([]ContrastIssue, error) {
+	r, err := NewResolver(d)
+	if err != nil {
+		return nil, err
+	}
+	types := ExtractTypes(d)
+
+	// color returns a token's resolved color, if it has one
+	color := func(path string) (colors.Color, bool) {
+		if _, ok := r.flatTokens[path]; !ok {
+			return colors.Color{}, false
+		}
+		if t := types[path]; t != "" && t != "color" {
+			return colors.Color{}, false
+		}
+		r.stack = []string{}
+		val, err := r.resolveReference(path)
+		if err != nil {
+			return colors.Color{}, false
+		}
+		s, ok := val.(string)
+		if !ok {
+			return colors.Color{}, false
+		}
+		c, err := colors.Parse(s)
+		return c, err == nil
+	}
+
+	// surface returns the color a background shows: a translucent one
+	// composited over its $backdrop, or over the white canvas without one
+	var surface func(path string, depth int) (colors.Color, bool)
+	surface = func(path string, depth int) (colors.Color, bool) {
+		c, ok := color(path)
+		if !ok || c.Alpha() >= 1 {
+			return c, ok
+		}
+		backdrop := colors.White()
+		if target, declared := backdropTarget(lookupToken(d.Root, path)); declared && depth < maxBackdropDepth {
+			if b, ok := surface(target, depth+1); ok {
+				backdrop = b
+			}
+		}
+		return colors.Composite(c, backdrop), true
+	}
+
+	var issues []ContrastIssue
+	check := func(fg, bg string, req MinContrast) {
+		fgColor, ok := color(fg)
+		if !ok {
+			return
+		}
+		bgColor, ok := surface(bg, 0)
+		if !ok {
+			return
+		}
+		ratio := req.Measure(colors.Composite(fgColor, bgColor), bgColor)
+		if ratio >= req.Value {
+			return
+		}
+		pos := d.valuePosition(fg)
+		issues = append(issues, ContrastIssue{
+			Path: fg, Against: bg, Ratio: ratio, Min: req,
+			SourceFile: pos.File, Line: pos.Line, Column: pos.Column,
+		})
+	}
+
+	paths := make([]string, 0, len(r.flatTokens))
+	for path := range r.flatTokens {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	declared := make(map[[2]string]bool)
+	declaresBackground := make(map[string]bool)
+	for _, path := range paths {
+		token := lookupToken(d.Root, path)
+		target, ok := contrastTarget(token)
+		if !ok {
+			continue
+		}
+		declaresBackground[path] = true
+		req, err := ParseMinContrast(token["$minContrast"])
+		if err != nil {
+			continue
+		}
+		declared[[2]string{path, target}] = true
+		check(path, target, req)
+	}
+
+	if opts.Level != "" {
+		req, err := ParseMinContrast(opts.Level)
+		if err != nil {
+			return nil, err
+		}
+		hasContent := make(map[string]bool) // groups following the -content convention
+		for _, path := range paths {
+			if strings.HasSuffix(path, "-content") {
+				hasContent[parentPath(path)] = true
+			}
+		}
+		for _, path := range paths {
+			// Tokens naming their own background are foregrounds, not roles
+			if strings.HasSuffix(path, "-content") || !hasContent[parentPath(path)] || declaresBackground[path] {
+				continue
+			}
+			if _, ok := color(path); !ok {
+				continue
+			}
+			partner := ContentPartner(path)
+			if _, ok := r.flatTokens[partner]; !ok {
+				// An alias of another color shares that color's content
+				// color rather than needing one of its own
+				if opts.RequireContent && !isWholeReference(r.flatTokens[path]) {
+					pos := d.valuePosition(path)
+					issues = append(issues, ContrastIssue{
+						Path: path, Against: partner, Missing: true,
+						SourceFile: pos.File, Line: pos.Line, Column: pos.Column,
+					})
+				}
+				continue
+			}
+			if !declared[[2]string{partner, path}] {
+				check(partner, path, req)
+			}
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Path < issues[j].Path
+	})
+	return issues, nil
+}
+
+// maxBackdropDepth bounds a chain of $backdrop declarations, which a
+// cycle would otherwise follow forever
+const maxBackdropDepth = 8
+
+// contrastTarget reads $contrastWith, accepting "{color.base-100}" or a
+// bare path
+func contrastTarget(token map[string]any) (string, bool) {
+	return tokenPathKey(token, "$contrastWith")
+}
+
+// backdropTarget reads $backdrop, the surface a translucent color is
+// painted over
+func backdropTarget(token map[string]any) (string, bool) {
+	return tokenPathKey(token, "$backdrop")
+}
+
+func tokenPathKey(token map[string]any, key string) (string, bool) {
+	s, ok := token[key].(string)
+	if !ok || s == "" {
+		return "", false
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(s), "{"), "}"), true
+}
+
+// ParseMinContrast reads a required contrast: a WCAG level ("AA", "AAA",
+// "AA-large", "AAA-large"), a WCAG 2 ratio (4.5, "4.5", "4.5:1") or an
+// APCA lightness contrast ("Lc 60", "Lc75"). A missing value means AA.
+func ParseMinContrast(v any) (MinContrast, error) {
+	var ratio float64
+	switch val := v.(type) {
+	case nil:
+		return MinContrast{colors.AlgorithmWCAG2, contrastLevels[ContrastAA], ContrastAA}, nil
+	case float64:
+		ratio = val
+	case int:
+		ratio = float64(val)
+	case string:
+		if r, ok := contrastLevels[val]; ok {
+			return MinContrast{colors.AlgorithmWCAG2, r, val}, nil
+		}
+		if m := apcaMinimum.FindStringSubmatch(strings.TrimSpace(val)); m != nil {
+			lc, _ := strconv.ParseFloat(m[1], 64)
+			if lc <= 0 || lc > 108 {
+				return MinContrast{}, fmt.Errorf("invalid contrast %q: Lc must be between 0 and 108", val)
+			}
+			return MinContrast{Algorithm: colors.AlgorithmAPCA, Value: lc}, nil
+		}
+		var err error
+		ratio, err = strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(val), ":1"), 64)
+		if err != nil {
+			return MinContrast{}, fmt.Errorf("invalid contrast %q: want AA, AAA, AA-large, AAA-large, a ratio or an APCA Lc (\"Lc 60\")", val)
+		}
+	default:
+		return MinContrast{}, fmt.Errorf("invalid contrast: expected level or ratio, got %T", v)
+	}
+	if ratio < 1 || ratio > 21 {
+		return MinContrast{}, fmt.Errorf("invalid contrast %v: ratio must be between 1 and 21", v)
+	}
+	return MinContrast{Algorithm: colors.AlgorithmWCAG2, Value: ratio}, nil
+}
+
+// validateContrastDeclaration checks $contrastWith, $minContrast and
+// $backdrop are well formed; CheckContrast measures the pair itself
+func validateContrastDeclaration(dict *Dictionary, path string, token map[string]any) []ValidationError {
+	var errs []ValidationError
+	target, hasTarget := contrastTarget(token)
+	if raw, ok := token["$contrastWith"]; ok && !hasTarget {
+		errs = append(errs, newValidationError(dict, path, RuleInvalidValue,
+			fmt.Sprintf("invalid $contrastWith: expected a token path, got %v", raw)))
+	}
+	if hasTarget && lookupToken(dict.Root, target) == nil {
+		errs = append(errs, newValidationError(dict, path, RuleReferenceNotFound,
+			fmt.Sprintf("$contrastWith: %v: %s", ErrReferenceNotFound, target)))
+	}
+	if _, ok := token["$minContrast"]; ok {
+		if !hasTarget {
+			errs = append(errs, newValidationError(dict, path, RuleInvalidValue, "$minContrast requires $contrastWith"))
+		}
+		if _, err := ParseMinContrast(token["$minContrast"]); err != nil {
+			errs = append(errs, newValidationError(dict, path, RuleInvalidValue, "$minContrast: "+err.Error()))
+		}
+	}
+	backdrop, hasBackdrop := backdropTarget(token)
+	if raw, ok := token["$backdrop"]; ok && !hasBackdrop {
+		errs = append(errs, newValidationError(dict, path, RuleInvalidValue,
+			fmt.Sprintf("invalid $backdrop: expected a token path, got %v", raw)))
+	}
+	if hasBackdrop && lookupToken(dict.Root, backdrop) == nil {
+		errs = append(errs, newValidationError(dict, path, RuleReferenceNotFound,
+			fmt.Sprintf("$backdrop: %v: %s", ErrReferenceNotFound, backdrop)))
+	}
+	return errs
+}
+
+func parentPath(path string) string {
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		return path[:i]
+	}
+	return ""
+}
@@ -0,0 +1,256 @@
+// tokenctl/pkg/tokens/contrast_test.go
+
+package tokens
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dmoose/tokenctl/pkg/colors"
+)
+
+func TestCheckContrast(t *testing.T) {
+	t.Parallel()
+	const fixture = `{
+		"color": {
+			"$type": "color",
+			"primary": {"$value": "#3b82f6"},
+			"primary-content": {"$value": "#ffffff"},
+			"accent": {"$value": "#f59e0b"},
+			"accent-content": {"$value": "#ffffff"},
+			"base-100": {"$value": "#ffffff"},
+			"base-200": {"$value": "#f2f2f2"},
+			"base-content": {"$value": "#1f2937"},
+			"info": {"$value": "#0ea5e9"},
+			"focus": {"$value": "{color.primary}"},
+			"link": {"$value": "{color.primary}", "$contrastWith": "{color.base-100}", "$minContrast": "AAA"}
+		},
+		"palette": {"$type": "color", "blue-500": {"$value": "#3b82f6"}}
+	}`
+	tests := []struct {
+		name string
+		opts ContrastOptions
+		want []string // "path on against" for each issue
+	}{
+		{"declared pairs only", ContrastOptions{}, []string{
+			"color.link on color.base-100",
+		}},
+		{"AA pairs", ContrastOptions{Level: ContrastAA}, []string{
+			"color.accent-content on color.accent",
+			"color.link on color.base-100",
+			"color.primary-content on color.primary",
+		}},
+		{"AA large", ContrastOptions{Level: ContrastAALarge}, []string{
+			"color.accent-content on color.accent",
+			"color.link on color.base-100",
+		}},
+		{"missing content", ContrastOptions{Level: "3:1", RequireContent: true}, []string{
+			"color.accent-content on color.accent",
+			"color.info missing color.info-content",
+			"color.link on color.base-100",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			issues, err := CheckContrast(dictFromJSON(t, fixture), tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, issue := range issues {
+				if issue.Missing {
+					got = append(got, issue.Path+" missing "+issue.Against)
+				} else {
+					got = append(got, issue.Path+" on "+issue.Against)
+				}
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestCheckContrast_Report(t *testing.T) {
+	t.Parallel()
+	d := dictFromJSON(t, `{"color": {
+		"$type": "color",
+		"primary": {"$value": "#3b82f6"},
+		"base-100": {"$value": "#ffffff"},
+		"link": {"$value": "{color.primary}", "$contrastWith": "{color.base-100}", "$minContrast": "AAA"}
+	}}`)
+	d.Positions = map[string]Position{"color.link.$value": {"colors.json", 12, 15}}
+	issues, err := CheckContrast(d, ContrastOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 {
+		t.Fatalf("got %d issues, want 1", len(issues))
+	}
+	issue := issues[0]
+	issue.Theme = "dark"
+	want := "color.link [colors.json:12:15]: contrast 3.68:1 against color.base-100 is below 7:1 (AAA) in theme dark"
+	if got := issue.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if d := issue.Diagnostic(); d.Rule != RuleContrast || d.Theme != "dark" || d.Line != 12 {
+		t.Errorf("unexpected diagnostic: %+v", d)
+	}
+}
+
+func TestContentPartner(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"color.primary":  "color.primary-content",
+		"color.base-100": "color.base-content",
+		"color.base-300": "color.base-content",
+		"status.error":   "status.error-content",
+	}
+	for path, want := range tests {
+		if got := ContentPartner(path); got != want {
+			t.Errorf("ContentPartner(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestCheckContrast_APCA(t *testing.T) {
+	t.Parallel()
+	d := NewDictionary()
+	d.Root = map[string]any{
+		"color": map[string]any{
+			"$type":          "color",
+			"orange":         map[string]any{"$value": "#f97316"},
+			"orange-content": map[string]any{"$value": "#000000"},
+			"muted":          map[string]any{"$value": "#888888", "$contrastWith": "{color.white}", "$minContrast": "Lc 75"},
+			"white":          map[string]any{"$value": "#ffffff"},
+		},
+	}
+	// Black on orange passes WCAG 2 AA (7.5:1) but is only Lc 51 under APCA
+	issues, err := CheckContrast(d, ContrastOptions{Level: ContrastAA})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 || issues[0].Path != "color.muted" {
+		t.Fatalf("AA issues = %v, want only color.muted", issues)
+	}
+	if want := "color.muted: contrast Lc 63.1 against color.white is below Lc 75"; issues[0].Error() != want {
+		t.Errorf("Error() = %q, want %q", issues[0].Error(), want)
+	}
+
+	issues, err = CheckContrast(d, ContrastOptions{Level: "Lc60"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.Path)
+	}
+	if strings.Join(got, ",") != "color.muted,color.orange-content" {
+		t.Errorf("Lc60 issues = %v", got)
+	}
+}
+
+func TestCheckContrast_Translucent(t *testing.T) {
+	t.Parallel()
+	d := NewDictionary()
+	d.Root = map[string]any{
+		"color": map[string]any{
+			"$type":    "color",
+			"base-100": map[string]any{"$value": "#0f172a"},
+			// Half-transparent white: light gray over the default white
+			// canvas, mid gray over the dark base
+			"scrim":      map[string]any{"$value": "#ffffff80"},
+			"scrim-dark": map[string]any{"$value": "#ffffff80", "$backdrop": "{color.base-100}"},
+			"on-scrim":   map[string]any{"$value": "#ffffff", "$contrastWith": "{color.scrim}"},
+			"on-dark":    map[string]any{"$value": "#000000", "$contrastWith": "{color.scrim-dark}"},
+			// A faint foreground blends into its background
+			"ghost": map[string]any{"$value": "#00000020", "$contrastWith": "{color.base-100}", "$minContrast": 1.1},
+		},
+	}
+	issues, err := CheckContrast(d, ContrastOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]float64)
+	for _, issue := range issues {
+		got[issue.Path] = issue.Ratio
+	}
+	if len(got) != 2 {
+		t.Fatalf("issues = %v, want color.ghost and color.on-scrim", issues)
+	}
+	// White on white-over-white is 1:1
+	if r, ok := got["color.on-scrim"]; !ok || r > 1.01 {
+		t.Errorf("color.on-scrim ratio = %v (reported %v), want 1", r, ok)
+	}
+	if _, ok := got["color.ghost"]; !ok {
+		t.Error("color.ghost should fail: it barely differs from its background")
+	}
+}
+
+func TestParseMinContrast(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		input   any
+		want    MinContrast
+		wantErr bool
+	}{
+		{nil, MinContrast{colors.AlgorithmWCAG2, 4.5, "AA"}, false},
+		{"AAA", MinContrast{colors.AlgorithmWCAG2, 7, "AAA"}, false},
+		{"AA-large", MinContrast{colors.AlgorithmWCAG2, 3, "AA-large"}, false},
+		{4.5, MinContrast{colors.AlgorithmWCAG2, 4.5, ""}, false},
+		{"3:1", MinContrast{colors.AlgorithmWCAG2, 3, ""}, false},
+		{"Lc 60", MinContrast{colors.AlgorithmAPCA, 60, ""}, false},
+		{"lc75", MinContrast{colors.AlgorithmAPCA, 75, ""}, false},
+		{"Lc 120", MinContrast{}, true},
+		{"AAAA", MinContrast{}, true},
+		{0.5, MinContrast{}, true},
+		{true, MinContrast{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMinContrast(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseMinContrast(%v) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseMinContrast(%v) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestValidate_ContrastDeclarations(t *testing.T) {
+	t.Parallel()
+	d := NewDictionary()
+	d.Root = map[string]any{
+		"color": map[string]any{
+			"bg":      map[string]any{"$value": "#fff"},
+			"text":    map[string]any{"$value": "#000", "$contrastWith": "{color.nope}"},
+			"muted":   map[string]any{"$value": "#777", "$contrastWith": "{color.bg}", "$minContrast": "high"},
+			"orphan":  map[string]any{"$value": "#777", "$minContrast": "AA"},
+			"unusual": map[string]any{"$value": "#777", "$contrastWith": 3},
+			"veil":    map[string]any{"$value": "#0008", "$backdrop": "{color.gone}"},
+			"mist":    map[string]any{"$value": "#fff8", "$backdrop": true},
+		},
+	}
+	errs, err := Validate(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]string)
+	for _, e := range errs {
+		got[e.Path] += e.Message + ";"
+	}
+	for path, want := range map[string]string{
+		"color.text":    "reference not found: color.nope",
+		"color.muted":   `$minContrast: invalid contrast "high"`,
+		"color.orphan":  "$minContrast requires $contrastWith",
+		"color.unusual": "invalid $contrastWith",
+		"color.veil":    "$backdrop: reference not found: color.gone",
+		"color.mist":    "invalid $backdrop",
+	} {
+		if !strings.Contains(got[path], want) {
+			t.Errorf("%s: errors %q, want %q", path, got[path], want)
+		}
+	}
+}
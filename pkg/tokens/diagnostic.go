@@ -0,0 +1,243 @@
+// tokenctl/pkg/tokens/diagnostic.go
+
+package tokens
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Severity is how a diagnostic affects the run
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic rule IDs. Report consumers key baselines and suppressions on
+// these, so an ID is never renamed once released. Unknown-key findings
+// use their FindingKind as the rule ID.
+const (
+	RuleReferenceNotFound   = "reference-not-found"
+	RuleCircularReference   = "circular-reference"
+	RuleExpressionError     = "expression-error"
+	RuleInvalidStructure    = "invalid-structure"
+	RuleInvalidValue        = "invalid-value"
+	RuleConstraintViolation = "constraint-violation"
+	RuleLayerViolation      = "layer-violation"
+	RuleContrast            = "contrast"
+	RuleMissingContent      = "missing-content"
+	RuleOutOfGamut          = "out-of-gamut"
+	RuleForcedColors        = "forced-colors"
+	RuleReducedMotion       = "reduced-motion"
+	RuleMergeConflict       = "merge-conflict"
+	RuleFatal               = "fatal"
+)
+
+// Rule describes a diagnostic rule for report formats that list them
+type Rule struct {
+	ID          string
+	Description string
+	Severity    Severity // default severity
+}
+
+// Rules lists every rule a diagnostic can carry, in a stable order
+var Rules = []Rule{
+	{RuleReferenceNotFound, "A {reference} names a token that does not exist", SeverityError},
+	{RuleCircularReference, "Tokens reference each other in a loop", SeverityError},
+	{RuleExpressionError, "An expression such as calc() or darken() could not be evaluated", SeverityError},
+	{RuleInvalidStructure, "A group holds a primitive where a token or group object is expected", SeverityError},
+	{RuleInvalidValue, "A token value does not match its $type", SeverityError},
+	{RuleConstraintViolation, "A token value is outside its $min/$max bounds", SeverityError},
+	{RuleLayerViolation, "A token references a layer it may not depend on", SeverityError},
+	{RuleContrast, "A foreground/background pair is below its required WCAG 2 or APCA contrast", SeverityError},
+	{RuleMissingContent, "A color role has no -content partner", SeverityWarning},
+	{RuleOutOfGamut, "An authored color is outside the target gamut and will be clipped", SeverityWarning},
+	{RuleForcedColors, "An interactive component with a background has no border or outline for forced colors mode", SeverityWarning},
+	{RuleReducedMotion, "A component animates without a token or keyframe that declares $reducedMotion", SeverityWarning},
+	{string(FindingUnknownComponentKey), "A component key the generator does not read", SeverityWarning},
+	{string(FindingUnknownMetadataKey), "A $-prefixed key tokenctl does not read", SeverityWarning},
+	{string(FindingMissingClass), "A variant, size or state without $class is never emitted", SeverityWarning},
+	{RuleMergeConflict, "A token is defined in more than one file; the last one wins", SeverityWarning},
+	{RuleFatal, "tokenctl could not complete the run", SeverityError},
+}
+
+// Diagnostic is one problem in a form report writers share: a stable
+// rule ID, a severity and, when known, where in the input it is.
+type Diagnostic struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	Path     string   `json:"path,omitempty"`
+	Theme    string   `json:"theme,omitempty"` // theme being checked, empty for the base
+	File     string   `json:"file,omitempty"`
+	Line     int      `json:"line,omitempty"`
+	Column   int      `json:"column,omitempty"`
+}
+
+// Diagnostic converts a validation error
+func (v ValidationError) Diagnostic() Diagnostic {
+	rule := v.Rule
+	if rule == "" {
+		rule = RuleInvalidValue
+	}
+	return Diagnostic{
+		Rule:     rule,
+		Severity: SeverityError,
+		Message:  v.Message,
+		Path:     v.Path,
+		File:     v.SourceFile,
+		Line:     v.Line,
+		Column:   v.Column,
+	}
+}
+
+// Diagnostic converts a layer violation
+func (v LayerViolation) Diagnostic() Diagnostic {
+	return Diagnostic{
+		Rule:     RuleLayerViolation,
+		Severity: SeverityError,
+		Message: fmt.Sprintf("%s layer cannot reference %s [%s]",
+			v.TokenLayer, v.RefPath, v.RefLayer),
+		Path:   v.TokenPath,
+		File:   v.SourceFile,
+		Line:   v.Line,
+		Column: v.Column,
+	}
+}
+
+// Diagnostic converts a contrast issue. A missing -content partner is a
+// warning; a failing pair is an error.
+func (c ContrastIssue) Diagnostic() Diagnostic {
+	d := Diagnostic{
+		Rule:     RuleContrast,
+		Severity: SeverityError,
+		Message:  c.message(),
+		Path:     c.Path,
+		Theme:    c.Theme,
+		File:     c.SourceFile,
+		Line:     c.Line,
+		Column:   c.Column,
+	}
+	if c.Missing {
+		d.Rule, d.Severity = RuleMissingContent, SeverityWarning
+	}
+	return d
+}
+
+// Diagnostic converts a gamut issue, which is always a warning
+func (g GamutIssue) Diagnostic() Diagnostic {
+	return Diagnostic{
+		Rule:     RuleOutOfGamut,
+		Severity: SeverityWarning,
+		Message:  g.message(),
+		Path:     g.Path,
+		Theme:    g.Theme,
+		File:     g.SourceFile,
+		Line:     g.Line,
+		Column:   g.Column,
+	}
+}
+
+// Diagnostic converts a forced colors issue, which is always a warning
+func (f ForcedColorsIssue) Diagnostic() Diagnostic {
+	return Diagnostic{
+		Rule:     RuleForcedColors,
+		Severity: SeverityWarning,
+		Message:  f.message(),
+		Path:     f.Component,
+		File:     f.SourceFile,
+		Line:     f.Line,
+		Column:   f.Column,
+	}
+}
+
+// Diagnostic converts a reduced motion issue, which is always a warning
+func (r ReducedMotionIssue) Diagnostic() Diagnostic {
+	return Diagnostic{
+		Rule:     RuleReducedMotion,
+		Severity: SeverityWarning,
+		Message:  r.message(),
+		Path:     r.Component,
+		File:     r.SourceFile,
+		Line:     r.Line,
+		Column:   r.Column,
+	}
+}
+
+// Diagnostic converts an unknown-key finding. Findings warn unless the
+// caller runs with --strict-unknown-keys.
+func (f Finding) Diagnostic(severity Severity) Diagnostic {
+	msg := fmt.Sprintf("dropped input: %s", f.Key)
+	if f.Hint != "" {
+		msg += " — " + f.Hint
+	}
+	return Diagnostic{
+		Rule:     string(f.Kind),
+		Severity: severity,
+		Message:  msg,
+		Path:     f.Path,
+		File:     f.SourceFile,
+		Line:     f.Line,
+		Column:   f.Column,
+	}
+}
+
+// MergeConflictDiagnostics reports every token defined in more than one
+// file, located at the definition that won
+func MergeConflictDiagnostics(d *Dictionary) []Diagnostic {
+	paths := make([]string, 0, len(d.Overrides))
+	for path := range d.Overrides {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var diags []Diagnostic
+	for _, path := range paths {
+		pos := d.valuePosition(path)
+		diags = append(diags, Diagnostic{
+			Rule:     RuleMergeConflict,
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("redefined (overriding %s)", strings.Join(d.Overrides[path], ", ")),
+			Path:     path,
+			File:     pos.File,
+			Line:     pos.Line,
+			Column:   pos.Column,
+		})
+	}
+	return diags
+}
+
+// ErrorDiagnostic converts an error that stopped a run. Resolution
+// failures keep their rule and the failing token's position; anything
+// else is reported as RuleFatal.
+func ErrorDiagnostic(err error) Diagnostic {
+	var rerr *ResolveError
+	if !errors.As(err, &rerr) {
+		return Diagnostic{Rule: RuleFatal, Severity: SeverityError, Message: err.Error()}
+	}
+	return Diagnostic{
+		Rule:     resolveRule(err),
+		Severity: SeverityError,
+		Message:  rerr.Err.Error(),
+		Path:     rerr.Path,
+		File:     rerr.Position.File,
+		Line:     rerr.Position.Line,
+		Column:   rerr.Position.Column,
+	}
+}
+
+// resolveRule classifies a resolver error
+func resolveRule(err error) string {
+	switch {
+	case errors.Is(err, ErrReferenceNotFound):
+		return RuleReferenceNotFound
+	case errors.Is(err, ErrCircularReference):
+		return RuleCircularReference
+	default:
+		return RuleExpressionError
+	}
+}
@@ -0,0 +1,109 @@
+// tokenctl/pkg/tokens/diagnostic_test.go
+
+package tokens
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDiagnostic_Rules(t *testing.T) {
+	t.Parallel()
+	d := NewDictionary()
+	d.Root = map[string]any{
+		"color": map[string]any{
+			"$type":   "color",
+			"primary": map[string]any{"$value": "notacolor"},
+			"accent":  map[string]any{"$value": "{color.missing}"},
+			"a":       map[string]any{"$value": "{color.b}"},
+			"b":       map[string]any{"$value": "{color.a}"},
+			"mix":     map[string]any{"$value": "darken({color.primary}, 10%)"},
+		},
+		"size": map[string]any{
+			"field": map[string]any{"$value": "0.5rem", "$min": "1rem"},
+		},
+		"loose": "string",
+	}
+	errs, err := Validate(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]string)
+	for _, e := range errs {
+		if _, seen := got[e.Path]; !seen {
+			got[e.Path] = e.Diagnostic().Rule
+		}
+	}
+	want := map[string]string{
+		"color.primary": RuleInvalidValue,
+		"color.accent":  RuleReferenceNotFound,
+		"color.a":       RuleCircularReference,
+		"color.mix":     RuleExpressionError,
+		"size.field":    RuleConstraintViolation,
+		"loose":         RuleInvalidStructure,
+	}
+	for path, rule := range want {
+		if got[path] != rule {
+			t.Errorf("%s: rule %q, want %q", path, got[path], rule)
+		}
+	}
+}
+
+func TestMergeConflictDiagnostics(t *testing.T) {
+	t.Parallel()
+	d := NewDictionary()
+	d.SourceFiles = map[string]string{"color.primary": "b.json"}
+	d.Overrides = map[string][]string{"color.primary": {"a.json"}}
+	d.Positions = map[string]Position{"color.primary.$value": {"b.json", 3, 18}}
+
+	diags := MergeConflictDiagnostics(d)
+	if len(diags) != 1 {
+		t.Fatalf("got %d diagnostics, want 1", len(diags))
+	}
+	want := Diagnostic{
+		Rule: RuleMergeConflict, Severity: SeverityWarning,
+		Message: "redefined (overriding a.json)", Path: "color.primary",
+		File: "b.json", Line: 3, Column: 18,
+	}
+	if diags[0] != want {
+		t.Errorf("got %+v, want %+v", diags[0], want)
+	}
+}
+
+func TestErrorDiagnostic(t *testing.T) {
+	t.Parallel()
+	located := &ResolveError{
+		Path:     "color.accent",
+		Position: Position{"colors.json", 4, 17},
+		Err:      fmt.Errorf("%w: color.missing", ErrReferenceNotFound),
+	}
+	tests := []struct {
+		name string
+		err  error
+		want Diagnostic
+	}{
+		{
+			"resolve error",
+			fmt.Errorf("resolution failed: %w", located),
+			Diagnostic{
+				Rule: RuleReferenceNotFound, Severity: SeverityError,
+				Message: "reference not found: color.missing", Path: "color.accent",
+				File: "colors.json", Line: 4, Column: 17,
+			},
+		},
+		{
+			"other error",
+			fmt.Errorf("failed to load base tokens"),
+			Diagnostic{Rule: RuleFatal, Severity: SeverityError, Message: "failed to load base tokens"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := ErrorDiagnostic(tt.err); got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
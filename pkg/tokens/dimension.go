@@ -16,8 +16,12 @@ type Dimension struct {
 	Unit  string
 }
 
+// DefaultRootFontSize is the browser default root font size in px, used
+// to convert between rem and px
+const DefaultRootFontSize = 16.0
+
 // dimensionRegex matches a number followed by an optional unit
-var dimensionRegex = regexp.MustCompile(`^(-?[0-9]*\.?[0-9]+)(px|cm|mm|in|pt|pc|em|rem|ex|ch|vw|vh|vmin|vmax|%|s|ms|deg|rad|turn)?$`)
+var dimensionRegex = regexp.MustCompile(`^(-?[0-9]*\.?[0-9]+)(px|cm|mm|Q|q|in|pt|pc|em|rem|ex|ch|vw|vh|vmin|vmax|%|s|ms|deg|rad|grad|turn)?$`)
 
 // ParseDimension parses a CSS dimension string into a Dimension struct
 // Examples: "10px", "1.5rem", "100%", "0", "2.5"
@@ -68,20 +72,126 @@ func roundTo(val float64, places int) float64 {
 	return math.Round(val*pow) / pow
 }
 
-// Add adds two dimensions (must have same unit)
+// Add adds two dimensions, converting other to d's unit at the default
+// root font size
 func (d Dimension) Add(other Dimension) (Dimension, error) {
-	if d.Unit != other.Unit {
+	return d.AddWithRoot(other, DefaultRootFontSize)
+}
+
+// AddWithRoot adds two dimensions, converting other to d's unit with the
+// given root font size (px per rem)
+func (d Dimension) AddWithRoot(other Dimension, rootFontSize float64) (Dimension, error) {
+	converted, err := other.ConvertTo(d.Unit, rootFontSize)
+	if err != nil {
 		return Dimension{}, fmt.Errorf("cannot add dimensions with different units: %s and %s", d.Unit, other.Unit)
 	}
-	return Dimension{Value: d.Value + other.Value, Unit: d.Unit}, nil
+	return Dimension{Value: d.Value + converted.Value, Unit: d.Unit}, nil
 }
 
-// Subtract subtracts another dimension (must have same unit)
+// Subtract subtracts another dimension, converting it to d's unit at the
+// default root font size
 func (d Dimension) Subtract(other Dimension) (Dimension, error) {
-	if d.Unit != other.Unit {
+	return d.SubtractWithRoot(other, DefaultRootFontSize)
+}
+
+// SubtractWithRoot subtracts another dimension, converting it to d's unit
+// with the given root font size (px per rem)
+func (d Dimension) SubtractWithRoot(other Dimension, rootFontSize float64) (Dimension, error) {
+	converted, err := other.ConvertTo(d.Unit, rootFontSize)
+	if err != nil {
 		return Dimension{}, fmt.Errorf("cannot subtract dimensions with different units: %s and %s", d.Unit, other.Unit)
 	}
-	return Dimension{Value: d.Value - other.Value, Unit: d.Unit}, nil
+	return Dimension{Value: d.Value - converted.Value, Unit: d.Unit}, nil
+}
+
+// unitRatios maps convertible units to a base unit of their category:
+// px for lengths, ms for time, deg for angles. rem and em are absent
+// because their ratio depends on the root font size.
+var unitRatios = map[string]float64{
+	"px": 1, "in": 96, "cm": 96 / 2.54, "mm": 96 / 25.4, "q": 96 / 101.6, "pt": 96.0 / 72, "pc": 16,
+	"ms": 1, "s": 1000,
+	"deg": 1, "rad": 180 / math.Pi, "grad": 0.9, "turn": 360,
+}
+
+// unitCategory groups units that may be combined in arithmetic
+func unitCategory(unit string) string {
+	switch unit {
+	case "":
+		return "number"
+	case "s", "ms":
+		return "time"
+	case "deg", "rad", "grad", "turn":
+		return "angle"
+	default:
+		return "length"
+	}
+}
+
+// unitRatio returns how many base units one unit is worth. rem and em
+// resolve against the root font size, treating em as relative to the
+// root as token math has no element context.
+func unitRatio(unit string, rootFontSize float64) (float64, bool) {
+	switch strings.ToLower(unit) {
+	case "rem", "em":
+		return rootFontSize, true
+	}
+	ratio, ok := unitRatios[strings.ToLower(unit)]
+	return ratio, ok
+}
+
+// ConvertTo converts d to unit. Absolute lengths, rem and em convert via
+// px; time and angle units convert within their category. Layout-relative
+// units (%, vw, ch, ...) only convert to themselves.
+func (d Dimension) ConvertTo(unit string, rootFontSize float64) (Dimension, error) {
+	if d.Unit == unit {
+		return d, nil
+	}
+	if rootFontSize <= 0 {
+		rootFontSize = DefaultRootFontSize
+	}
+	if unitCategory(d.Unit) == unitCategory(unit) {
+		from, ok1 := unitRatio(d.Unit, rootFontSize)
+		to, ok2 := unitRatio(unit, rootFontSize)
+		if ok1 && ok2 {
+			return Dimension{Value: d.Value * from / to, Unit: unit}, nil
+		}
+	}
+	return Dimension{}, fmt.Errorf("cannot convert %s to %s", d.String(), unit)
+}
+
+// NormalizeDimensions rewrites length values in resolved to unit (px or
+// rem) and returns a new map. Only tokens typed "dimension" or untyped
+// are touched; em is left alone since it depends on the element, and
+// layout-relative, time and angle values pass through unchanged.
+func NormalizeDimensions(resolved map[string]any, types map[string]string, unit string, rootFontSize float64) (map[string]any, error) {
+	if _, ok := unitRatio(unit, rootFontSize); !ok || unitCategory(unit) != "length" || unit == "em" {
+		return nil, fmt.Errorf("unsupported dimension unit %q (use px or rem)", unit)
+	}
+
+	out := make(map[string]any, len(resolved))
+	for path, val := range resolved {
+		out[path] = val
+		if t := types[path]; t != "" && t != "dimension" {
+			continue
+		}
+		s, ok := val.(string)
+		if !ok {
+			continue
+		}
+		d, err := ParseDimension(s)
+		if err != nil || d.Unit == "em" || unitCategory(d.Unit) != "length" {
+			continue
+		}
+		if _, ok := unitRatio(d.Unit, rootFontSize); !ok {
+			continue
+		}
+		converted, err := d.ConvertTo(unit, rootFontSize)
+		if err != nil {
+			continue
+		}
+		out[path] = converted.String()
+	}
+	return out, nil
 }
 
 // Multiply multiplies the dimension by a scalar
@@ -103,6 +213,36 @@ func (d Dimension) Divide(scalar float64) (Dimension, error) {
 	return Dimension{Value: result, Unit: d.Unit}, nil
 }
 
+// ParseRootFontSize reads a root font size given as "16px" or 16
+func ParseRootFontSize(v any) (float64, error) {
+	switch n := v.(type) {
+	case float64:
+		if n > 0 {
+			return n, nil
+		}
+	case int:
+		if n > 0 {
+			return float64(n), nil
+		}
+	case string:
+		d, err := ParseDimension(n)
+		if err == nil && (d.Unit == "px" || d.Unit == "") && d.Value > 0 {
+			return d.Value, nil
+		}
+	}
+	return 0, fmt.Errorf("$rootFontSize must be a positive px value, got %v", v)
+}
+
+// RootFontSize returns the dictionary's root-level $rootFontSize in px,
+// or DefaultRootFontSize when unset
+func (d *Dictionary) RootFontSize() (float64, error) {
+	v, ok := d.Root["$rootFontSize"]
+	if !ok {
+		return DefaultRootFontSize, nil
+	}
+	return ParseRootFontSize(v)
+}
+
 // IsZero returns true if the dimension value is zero
 func (d Dimension) IsZero() bool {
 	return d.Value == 0
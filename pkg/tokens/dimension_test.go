@@ -31,6 +31,10 @@ func TestParseDimension(t *testing.T) {
 		{"percent", "50%", 50, "%", false},
 		{"percent decimal", "33.33%", 33.33, "%", false},
 
+		// Quarter-millimeters
+		{"Q", "10Q", 10, "Q", false},
+		{"q lowercase", "10q", 10, "q", false},
+
 		// Viewport units
 		{"vw", "100vw", 100, "vw", false},
 		{"vh", "50vh", 50, "vh", false},
@@ -44,6 +48,7 @@ func TestParseDimension(t *testing.T) {
 		// Angle units
 		{"degrees", "45deg", 45, "deg", false},
 		{"radians", "3.14rad", 3.14, "rad", false},
+		{"gradians", "100grad", 100, "grad", false},
 		{"turns", "0.5turn", 0.5, "turn", false},
 
 		// Unitless
@@ -131,7 +136,10 @@ func TestDimension_Add(t *testing.T) {
 	}{
 		{"same unit", Dimension{10, "px"}, Dimension{5, "px"}, Dimension{15, "px"}, false},
 		{"same unit rem", Dimension{1.5, "rem"}, Dimension{0.5, "rem"}, Dimension{2, "rem"}, false},
-		{"different units", Dimension{10, "px"}, Dimension{1, "rem"}, Dimension{}, true},
+		{"rem converts to px", Dimension{10, "px"}, Dimension{1, "rem"}, Dimension{26, "px"}, false},
+		{"pt converts to px", Dimension{10, "px"}, Dimension{12, "pt"}, Dimension{26, "px"}, false},
+		{"layout-relative units", Dimension{10, "px"}, Dimension{50, "%"}, Dimension{}, true},
+		{"different categories", Dimension{10, "px"}, Dimension{1, "s"}, Dimension{}, true},
 		{"unitless", Dimension{10, ""}, Dimension{5, ""}, Dimension{15, ""}, false},
 	}
 
@@ -169,7 +177,8 @@ func TestDimension_Subtract(t *testing.T) {
 	}{
 		{"same unit", Dimension{10, "px"}, Dimension{3, "px"}, Dimension{7, "px"}, false},
 		{"result negative", Dimension{5, "px"}, Dimension{10, "px"}, Dimension{-5, "px"}, false},
-		{"different units", Dimension{10, "px"}, Dimension{1, "rem"}, Dimension{}, true},
+		{"rem converts to px", Dimension{20, "px"}, Dimension{1, "rem"}, Dimension{4, "px"}, false},
+		{"layout-relative units", Dimension{10, "px"}, Dimension{1, "vw"}, Dimension{}, true},
 	}
 
 	for _, tt := range tests {
@@ -321,3 +330,141 @@ func TestMustParseDimension(t *testing.T) {
 	}()
 	MustParseDimension("invalid")
 }
+
+func TestDimension_ConvertTo(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		d       Dimension
+		unit    string
+		root    float64
+		want    Dimension
+		wantErr bool
+	}{
+		{"px to rem", Dimension{24, "px"}, "rem", 16, Dimension{1.5, "rem"}, false},
+		{"rem to px custom root", Dimension{1.5, "rem"}, "px", 10, Dimension{15, "px"}, false},
+		{"em follows root", Dimension{2, "em"}, "px", 16, Dimension{32, "px"}, false},
+		{"pt to px", Dimension{12, "pt"}, "px", 16, Dimension{16, "px"}, false},
+		{"zero root uses default", Dimension{1, "rem"}, "px", 0, Dimension{16, "px"}, false},
+		{"seconds to ms", Dimension{0.25, "s"}, "ms", 16, Dimension{250, "ms"}, false},
+		{"Q to mm", Dimension{10, "Q"}, "mm", 16, Dimension{2.5, "mm"}, false},
+		{"grad to deg", Dimension{100, "grad"}, "deg", 16, Dimension{90, "deg"}, false},
+		{"same unit", Dimension{50, "%"}, "%", 16, Dimension{50, "%"}, false},
+		{"percent to px", Dimension{50, "%"}, "px", 16, Dimension{}, true},
+		{"time to length", Dimension{1, "s"}, "px", 16, Dimension{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tt.d.ConvertTo(tt.unit, tt.root)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ConvertTo() expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ConvertTo() unexpected error: %v", err)
+			}
+			if got.String() != tt.want.String() {
+				t.Errorf("ConvertTo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDictionary_RootFontSize(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		value   any
+		want    float64
+		wantErr bool
+	}{
+		{"unset", nil, DefaultRootFontSize, false},
+		{"px string", "10px", 10, false},
+		{"number", 18.0, 18, false},
+		{"rem is rejected", "1rem", 0, true},
+		{"zero is rejected", 0.0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			d := NewDictionary()
+			if tt.value != nil {
+				d.Root["$rootFontSize"] = tt.value
+			}
+			got, err := d.RootFontSize()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("RootFontSize() expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RootFontSize() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RootFontSize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeDimensions(t *testing.T) {
+	t.Parallel()
+	resolved := map[string]any{
+		"space.md":    "24px",
+		"space.lg":    "2rem",
+		"space.print": "12pt",
+		"space.em":    "1.5em",
+		"width.half":  "50%",
+		"radius.none": "0",
+		"motion.fast": "150ms",
+		"color.brand": "#3b82f6",
+		"font.body":   "16px",
+		"layer.count": 3.0,
+	}
+	types := map[string]string{"font.body": "fontFamily"}
+
+	got, err := NormalizeDimensions(resolved, types, "rem", 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"space.md":    "1.5rem",
+		"space.lg":    "2rem",
+		"space.print": "1rem",
+		"space.em":    "1.5em",
+		"width.half":  "50%",
+		"radius.none": "0",
+		"motion.fast": "150ms",
+		"color.brand": "#3b82f6",
+		"font.body":   "16px",
+		"layer.count": 3.0,
+	}
+	for path, w := range want {
+		if got[path] != w {
+			t.Errorf("%s = %v, want %v", path, got[path], w)
+		}
+	}
+	if resolved["space.md"] != "24px" {
+		t.Error("NormalizeDimensions mutated its input")
+	}
+
+	px, err := NormalizeDimensions(resolved, nil, "px", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if px["space.lg"] != "20px" {
+		t.Errorf("space.lg = %v, want 20px", px["space.lg"])
+	}
+
+	for _, unit := range []string{"em", "%", "ms", "furlong"} {
+		if _, err := NormalizeDimensions(resolved, nil, unit, 16); err == nil {
+			t.Errorf("expected error for unit %q", unit)
+		}
+	}
+}
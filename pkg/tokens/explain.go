@@ -0,0 +1,319 @@
+// tokenctl/pkg/tokens/explain.go
+
+package tokens
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Resolution step kinds
+const (
+	StepLiteral       = "literal"       // plain value, nothing to resolve
+	StepAlias         = "alias"         // value is exactly one {reference}
+	StepInterpolation = "interpolation" // text with embedded {references}
+	StepExpression    = "expression"    // calc(), mix(), fluid(), ...
+	StepComposite     = "composite"     // object or array with references
+)
+
+// TraceNode is one hop in resolving a token: the authored value, what it
+// resolved to, and a child for every reference it read
+type TraceNode struct {
+	Path     string       `json:"path"`
+	Kind     string       `json:"kind"`
+	Authored any          `json:"authored"`
+	Resolved any          `json:"resolved,omitempty"`
+	Error    string       `json:"error,omitempty"`
+	Refs     []*TraceNode `json:"refs,omitempty"`
+}
+
+// Trace returns the resolution tree of path. Failures (missing or
+// circular references) are recorded on the node rather than returned,
+// since showing where resolution breaks is the point of a trace.
+func (r *Resolver) Trace(path string) (*TraceNode, error) {
+	if _, ok := r.flatTokens[path]; !ok {
+		return nil, fmt.Errorf("token not found: %s", path)
+	}
+	return r.trace(path, nil), nil
+}
+
+func (r *Resolver) trace(path string, visiting []string) *TraceNode {
+	raw, ok := r.flatTokens[path]
+	if !ok {
+		return &TraceNode{Path: path, Error: "reference not found"}
+	}
+	node := &TraceNode{Path: path, Authored: raw, Kind: stepKind(raw)}
+	if slices.Contains(visiting, path) {
+		node.Error = "circular reference"
+		return node
+	}
+
+	r.stack = []string{}
+	resolved, err := r.resolveReference(path)
+	if err != nil {
+		node.Error = err.Error()
+	} else {
+		node.Resolved = resolved
+	}
+
+	visiting = append(visiting, path)
+	seen := make(map[string]bool)
+	for _, ref := range referencesIn(raw) {
+		if seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		node.Refs = append(node.Refs, r.trace(ref, visiting))
+	}
+	return node
+}
+
+// stepKind classifies how an authored value is resolved
+func stepKind(raw any) string {
+	switch v := raw.(type) {
+	case string:
+		switch {
+		case IsExpression(v):
+			return StepExpression
+		case isWholeReference(v):
+			return StepAlias
+		case strings.Contains(v, "{"):
+			return StepInterpolation
+		}
+	case map[string]any, []any:
+		if len(referencesIn(v)) > 0 {
+			return StepComposite
+		}
+	}
+	return StepLiteral
+}
+
+// ThemeValue is a token's resolved value under one theme
+type ThemeValue struct {
+	Theme      string `json:"theme"`
+	Value      any    `json:"value"`
+	Changed    bool   `json:"changed"`               // differs from the base value
+	SourceFile string `json:"source_file,omitempty"` // theme file that overrides it, if any
+}
+
+// Explanation describes where a token comes from and how it resolves
+type Explanation struct {
+	Path        string         `json:"path"`
+	Type        string         `json:"type,omitempty"`
+	Authored    any            `json:"authored"`
+	Resolved    any            `json:"resolved,omitempty"`
+	Trace       *TraceNode     `json:"trace"`
+	SourceFile  string         `json:"source_file,omitempty"`
+	Overrides   []string       `json:"overrides,omitempty"` // earlier definitions, oldest first
+	Layer       string         `json:"layer,omitempty"`
+	Constraints map[string]any `json:"constraints,omitempty"`
+	Metadata    *TokenMetadata `json:"metadata,omitempty"`
+	Themes      []ThemeValue   `json:"themes,omitempty"`
+}
+
+// Explain gathers provenance, resolution and per-theme values for one
+// token. themes are the raw theme dictionaries; inheritance is applied
+// here so each value matches what the build emits. It returns nil when
+// base has no token at path.
+func Explain(base *Dictionary, themes map[string]*Dictionary, path string) (*Explanation, error) {
+	resolver, err := NewResolver(base)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := resolver.flatTokens[path]; !ok {
+		return nil, nil
+	}
+	trace, err := resolver.Trace(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ex := &Explanation{
+		Path:       path,
+		Type:       ExtractTypes(base)[path],
+		Authored:   trace.Authored,
+		Resolved:   trace.Resolved,
+		Trace:      trace,
+		SourceFile: base.SourceFiles[path],
+		Overrides:  base.Overrides[path],
+		Layer:      string(NewLayerValidator(base).GetLayer(path)),
+		Metadata:   ExtractMetadata(base)[path],
+	}
+	if node := lookupToken(base.Root, path); node != nil {
+		for _, key := range []string{"$min", "$max"} {
+			if v, ok := node[key]; ok {
+				if ex.Constraints == nil {
+					ex.Constraints = make(map[string]any)
+				}
+				ex.Constraints[key] = v
+			}
+		}
+	}
+
+	if len(themes) == 0 {
+		return ex, nil
+	}
+	inherited, err := ResolveThemeInheritance(base, themes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve theme inheritance: %w", err)
+	}
+	names := make([]string, 0, len(inherited))
+	for name := range inherited {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		themeResolver, err := NewResolver(inherited[name])
+		if err != nil {
+			return nil, fmt.Errorf("theme %s: %w", name, err)
+		}
+		tv := ThemeValue{Theme: name, SourceFile: themeSourceFile(themes, name, path)}
+		if val, err := themeResolver.resolveReference(path); err != nil {
+			tv.Value = "error: " + err.Error()
+			tv.Changed = true
+		} else {
+			tv.Value = val
+			tv.Changed = fmt.Sprint(val) != fmt.Sprint(ex.Resolved)
+		}
+		ex.Themes = append(ex.Themes, tv)
+	}
+	return ex, nil
+}
+
+// themeSourceFile returns the theme file that sets path in a theme. A
+// combination of axes has no file of its own; the last of its parts to
+// set the path provides it, as it does the value.
+func themeSourceFile(themes map[string]*Dictionary, name, path string) string {
+	var file string
+	for _, part := range ThemeParts(name) {
+		if theme, ok := themes[part.Name()]; ok && theme.SourceFiles[path] != "" {
+			file = theme.SourceFiles[path]
+		}
+	}
+	return file
+}
+
+// lookupToken returns the token node at a dot path, or nil
+func lookupToken(root map[string]any, path string) map[string]any {
+	node := root
+	for part := range strings.SplitSeq(path, ".") {
+		child, ok := node[part].(map[string]any)
+		if !ok {
+			return nil
+		}
+		node = child
+	}
+	if !IsToken(node) {
+		return nil
+	}
+	return node
+}
+
+// ClassReference is one token a component property reads
+type ClassReference struct {
+	Path  string `json:"path"`
+	Value any    `json:"value,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// ClassProperty is a component property and the tokens it references
+type ClassProperty struct {
+	Selector   string           `json:"selector"` // "" for the class itself, or a state such as "&:hover"
+	Property   string           `json:"property"`
+	Value      any              `json:"value"`
+	References []ClassReference `json:"references,omitempty"`
+}
+
+// ClassExplanation lists the token references a component class consumes
+type ClassExplanation struct {
+	Class      string          `json:"class"`
+	Component  string          `json:"component"`
+	Role       string          `json:"role"` // base, variant, size or state
+	Properties []ClassProperty `json:"properties"`
+}
+
+// ExplainClass finds the component class (with or without a leading
+// dot) and resolves every token reference in its properties. It returns
+// nil when no component defines the class.
+func ExplainClass(base *Dictionary, class string) (*ClassExplanation, error) {
+	class = strings.TrimPrefix(class, ".")
+	components, err := base.ExtractComponents()
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract components: %w", err)
+	}
+	resolver, err := NewResolver(base)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(components))
+	for name := range components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		comp := components[name]
+		if comp.Class == class {
+			props, states := SplitProperties(comp.Base)
+			ex := &ClassExplanation{Class: class, Component: name, Role: "base"}
+			ex.Properties = classProperties(resolver, props, states)
+			return ex, nil
+		}
+		for _, group := range []struct {
+			role string
+			defs map[string]VariantDef
+		}{{"variant", comp.Variants}, {"size", comp.Sizes}, {"state", comp.States}} {
+			for _, def := range group.defs {
+				if def.Class != class {
+					continue
+				}
+				ex := &ClassExplanation{Class: class, Component: name, Role: group.role}
+				ex.Properties = classProperties(resolver, def.Properties, def.States)
+				return ex, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// classProperties lists the class's own properties, then each state's
+func classProperties(r *Resolver, props map[string]any, states map[string]State) []ClassProperty {
+	out := propertyReferences(r, "", props)
+	selectors := make([]string, 0, len(states))
+	for sel := range states {
+		selectors = append(selectors, sel)
+	}
+	sort.Strings(selectors)
+	for _, sel := range selectors {
+		out = append(out, propertyReferences(r, sel, states[sel].Properties)...)
+	}
+	return out
+}
+
+func propertyReferences(r *Resolver, selector string, props map[string]any) []ClassProperty {
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var out []ClassProperty
+	for _, key := range keys {
+		prop := ClassProperty{Selector: selector, Property: key, Value: props[key]}
+		for _, ref := range referencesIn(props[key]) {
+			r.stack = []string{}
+			cr := ClassReference{Path: ref}
+			if val, err := r.resolveReference(ref); err != nil {
+				cr.Error = err.Error()
+			} else {
+				cr.Value = val
+			}
+			prop.References = append(prop.References, cr)
+		}
+		out = append(out, prop)
+	}
+	return out
+}
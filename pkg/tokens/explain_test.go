@@ -0,0 +1,187 @@
+// tokenctl/pkg/tokens/explain_test.go
+
+package tokens
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	t.Parallel()
+	base := dictFromJSON(t, `{
+		"color": {"$layer": "brand", "blue": {"$value": "#3b82f6"}, "sky": {"$value": "#0ea5e9"}},
+		"semantic": {
+			"$layer": "semantic",
+			"primary": {"$type": "color", "$value": "{color.blue}", "$description": "Main brand action color"}
+		}
+	}`)
+	base.SourceFiles["semantic.primary"] = "b/semantic.json"
+	base.Overrides["semantic.primary"] = []string{"a/semantic.json"}
+	contrast := dictFromJSON(t, `{"semantic": {"primary": {"$value": "{color.sky}"}}}`)
+	contrast.SourceFiles["semantic.primary"] = "themes/contrast.json"
+	themes := map[string]*Dictionary{
+		"dark":     dictFromJSON(t, `{"color": {"blue": {"$value": "#1e40af"}}}`),
+		"contrast": contrast,
+	}
+
+	ex, err := Explain(base, themes, "semantic.primary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ex.Type != "color" || ex.Authored != "{color.blue}" || ex.Resolved != "#3b82f6" {
+		t.Errorf("unexpected header: %+v", ex)
+	}
+	if ex.SourceFile != "b/semantic.json" || !reflect.DeepEqual(ex.Overrides, []string{"a/semantic.json"}) {
+		t.Errorf("provenance = %q %v", ex.SourceFile, ex.Overrides)
+	}
+	if ex.Layer != "semantic" {
+		t.Errorf("Layer = %q, want semantic", ex.Layer)
+	}
+	if ex.Metadata == nil || ex.Metadata.Description != "Main brand action color" {
+		t.Errorf("Metadata = %+v", ex.Metadata)
+	}
+	if ex.Trace.Kind != StepAlias || len(ex.Trace.Refs) != 1 || ex.Trace.Refs[0].Path != "color.blue" {
+		t.Errorf("Trace = %+v", ex.Trace)
+	}
+
+	want := []ThemeValue{
+		{Theme: "contrast", Value: "#0ea5e9", Changed: true, SourceFile: "themes/contrast.json"},
+		{Theme: "dark", Value: "#1e40af", Changed: true},
+	}
+	if !reflect.DeepEqual(ex.Themes, want) {
+		t.Errorf("Themes = %+v, want %+v", ex.Themes, want)
+	}
+
+	missing, err := Explain(base, themes, "semantic.nope")
+	if err != nil || missing != nil {
+		t.Errorf("Explain(unknown) = %v, %v; want nil, nil", missing, err)
+	}
+}
+
+func TestExplain_ThemeAxes(t *testing.T) {
+	t.Parallel()
+	base := NewDictionary()
+	base.Root = map[string]any{"color": map[string]any{"primary": map[string]any{"$value": "#3b82f6"}}}
+	light := NewDictionary()
+	light.Root = map[string]any{"$default": true}
+	acme := NewDictionary()
+	acme.Root = map[string]any{"color": map[string]any{"primary": map[string]any{"$value": "#e11d48"}}}
+	acme.SourceFiles["color.primary"] = "themes/brand/acme.json"
+	themes := map[string]*Dictionary{"light": light, "brand/acme": acme}
+
+	ex, err := Explain(base, themes, "color.primary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ThemeValue{
+		{Theme: "brand/acme", Value: "#e11d48", Changed: true, SourceFile: "themes/brand/acme.json"},
+		{Theme: "light", Value: "#3b82f6"},
+		{Theme: "light+brand/acme", Value: "#e11d48", Changed: true, SourceFile: "themes/brand/acme.json"},
+	}
+	if !reflect.DeepEqual(ex.Themes, want) {
+		t.Errorf("Themes = %+v, want %+v", ex.Themes, want)
+	}
+}
+
+func TestResolver_Trace(t *testing.T) {
+	t.Parallel()
+	base := dictFromJSON(t, `{
+		"color": {"blue": {"$value": "#3b82f6"}},
+		"semantic": {
+			"primary": {"$value": "{color.blue}"},
+			"border": {"$value": "1px solid {semantic.primary}"},
+			"broken": {"$value": "{color.missing}"}
+		},
+		"size": {"field": {"$value": "calc({size.base} * 2)"}, "base": {"$value": "1.25rem"}}
+	}`)
+	r, err := NewResolver(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path      string
+		kind      string
+		resolved  any
+		refs      []string
+		wantError bool
+	}{
+		{"semantic.border", StepInterpolation, "1px solid #3b82f6", []string{"semantic.primary"}, false},
+		{"size.field", StepExpression, "2.5rem", []string{"size.base"}, false},
+		{"color.blue", StepLiteral, "#3b82f6", nil, false},
+		{"semantic.broken", StepAlias, nil, []string{"color.missing"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			node, err := r.Trace(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if node.Kind != tt.kind {
+				t.Errorf("Kind = %q, want %q", node.Kind, tt.kind)
+			}
+			if node.Resolved != tt.resolved {
+				t.Errorf("Resolved = %v, want %v", node.Resolved, tt.resolved)
+			}
+			if (node.Error != "") != tt.wantError {
+				t.Errorf("Error = %q, wantError %v", node.Error, tt.wantError)
+			}
+			var refs []string
+			for _, ref := range node.Refs {
+				refs = append(refs, ref.Path)
+			}
+			if !reflect.DeepEqual(refs, tt.refs) {
+				t.Errorf("Refs = %v, want %v", refs, tt.refs)
+			}
+		})
+	}
+
+	if _, err := r.Trace("nope"); err == nil {
+		t.Error("expected error tracing unknown token")
+	}
+}
+
+func TestExplainClass(t *testing.T) {
+	t.Parallel()
+	base := dictFromJSON(t, `{
+		"color": {"blue": {"$value": "#3b82f6"}},
+		"semantic": {"primary": {"$value": "{color.blue}"}, "border": {"$value": "1px solid {semantic.primary}"}},
+		"components": {
+			"button": {
+				"$type": "component",
+				"$class": "btn",
+				"base": {"background": "{semantic.primary}", "&:hover": {"border": "{semantic.border}"}},
+				"variants": {"ghost": {"$class": "btn-ghost", "color": "{semantic.missing}"}}
+			}
+		}
+	}`)
+
+	ex, err := ExplainClass(base, ".btn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ex == nil || ex.Role != "base" || ex.Component != "components.button" {
+		t.Fatalf("ExplainClass(.btn) = %+v", ex)
+	}
+	want := []ClassProperty{
+		{Property: "background", Value: "{semantic.primary}", References: []ClassReference{{Path: "semantic.primary", Value: "#3b82f6"}}},
+		{Selector: "&:hover", Property: "border", Value: "{semantic.border}", References: []ClassReference{{Path: "semantic.border", Value: "1px solid #3b82f6"}}},
+	}
+	if !reflect.DeepEqual(ex.Properties, want) {
+		t.Errorf("Properties = %+v, want %+v", ex.Properties, want)
+	}
+
+	ghost, err := ExplainClass(base, "btn-ghost")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ghost == nil || ghost.Role != "variant" || len(ghost.Properties) != 1 || ghost.Properties[0].References[0].Error == "" {
+		t.Errorf("ExplainClass(btn-ghost) = %+v", ghost)
+	}
+
+	if none, err := ExplainClass(base, "card"); none != nil || err != nil {
+		t.Errorf("ExplainClass(card) = %v, %v; want nil, nil", none, err)
+	}
+}
@@ -0,0 +1,353 @@
+// tokenctl/pkg/tokens/expression_parser.go
+
+package tokens
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Expression grammar:
+//
+//	expr    := term (('+' | '-') term)*
+//	term    := unary (('*' | '/') unary)*
+//	unary   := '-' unary | primary
+//	primary := number | dimension | {ref} | #hex | ident | call | '(' expr ')'
+//	call    := ident '(' [expr (',' expr)*] ')'
+//
+// CSS color functions (rgb(), oklch(), ...) and var() are kept as raw
+// text since their arguments are space separated CSS, not expressions.
+
+type exprTokenKind int
+
+const (
+	exprTokEOF exprTokenKind = iota
+	exprTokNumber
+	exprTokRef
+	exprTokHash
+	exprTokIdent
+	exprTokRaw
+	exprTokOp
+	exprTokLParen
+	exprTokRParen
+	exprTokComma
+)
+
+type exprToken struct {
+	kind exprTokenKind
+	text string
+	dim  Dimension // for exprTokNumber
+	pos  int
+}
+
+// rawCSSFunctions are captured verbatim by the tokenizer
+var rawCSSFunctions = map[string]bool{
+	"rgb": true, "rgba": true, "hsl": true, "hsla": true, "hwb": true,
+	"lab": true, "lch": true, "oklab": true, "oklch": true, "color": true,
+	"var": true, "env": true,
+}
+
+// tokenizeExpression splits an expression into tokens
+func tokenizeExpression(src string) ([]exprToken, error) {
+	var toks []exprToken
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '{':
+			end := strings.IndexByte(src[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated reference at position %d", i)
+			}
+			path := strings.TrimSpace(src[i+1 : i+end])
+			if path == "" {
+				return nil, fmt.Errorf("empty reference at position %d", i)
+			}
+			toks = append(toks, exprToken{kind: exprTokRef, text: path, pos: i})
+			i += end + 1
+		case isDigit(c) || (c == '.' && i+1 < len(src) && isDigit(src[i+1])):
+			start := i
+			for i < len(src) && (isDigit(src[i]) || src[i] == '.') {
+				i++
+			}
+			value, err := strconv.ParseFloat(src[start:i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q at position %d", src[start:i], start)
+			}
+			unitStart := i
+			if i < len(src) && src[i] == '%' {
+				i++
+			} else {
+				for i < len(src) && isLetter(src[i]) {
+					i++
+				}
+			}
+			toks = append(toks, exprToken{
+				kind: exprTokNumber,
+				text: src[start:i],
+				dim:  Dimension{Value: value, Unit: src[unitStart:i]},
+				pos:  start,
+			})
+		case c == '#':
+			start := i
+			i++
+			for i < len(src) && isHexDigit(src[i]) {
+				i++
+			}
+			toks = append(toks, exprToken{kind: exprTokHash, text: src[start:i], pos: start})
+		case isLetter(c) || (c == '-' && i+1 < len(src) && src[i+1] == '-'):
+			start := i
+			for i < len(src) && (isLetter(src[i]) || isDigit(src[i]) || src[i] == '-' || src[i] == '_') {
+				i++
+			}
+			name := src[start:i]
+			if i < len(src) && src[i] == '(' && rawCSSFunctions[strings.ToLower(name)] {
+				end, err := matchingParen(src, i)
+				if err != nil {
+					return nil, err
+				}
+				toks = append(toks, exprToken{kind: exprTokRaw, text: src[start : end+1], pos: start})
+				i = end + 1
+				continue
+			}
+			toks = append(toks, exprToken{kind: exprTokIdent, text: name, pos: start})
+		case c == '+' || c == '-' || c == '*' || c == '/':
+			toks = append(toks, exprToken{kind: exprTokOp, text: string(c), pos: i})
+			i++
+		case c == '(':
+			toks = append(toks, exprToken{kind: exprTokLParen, text: "(", pos: i})
+			i++
+		case c == ')':
+			toks = append(toks, exprToken{kind: exprTokRParen, text: ")", pos: i})
+			i++
+		case c == ',':
+			toks = append(toks, exprToken{kind: exprTokComma, text: ",", pos: i})
+			i++
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %d", c, i)
+		}
+	}
+	toks = append(toks, exprToken{kind: exprTokEOF, pos: len(src)})
+	return toks, nil
+}
+
+// matchingParen returns the index of the ')' closing the '(' at open
+func matchingParen(src string, open int) (int, error) {
+	depth := 0
+	for i := open; i < len(src); i++ {
+		switch src[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unbalanced parentheses at position %d", open)
+}
+
+func isDigit(c byte) bool    { return c >= '0' && c <= '9' }
+func isLetter(c byte) bool   { return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') }
+func isHexDigit(c byte) bool { return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F') }
+
+// AST nodes
+
+type exprNode interface{}
+
+// numberNode is a literal number or dimension (10, 1.5rem, 50%)
+type numberNode struct {
+	dim Dimension
+}
+
+// refNode is a {token.path} reference
+type refNode struct {
+	path string
+}
+
+// textNode is a literal that only functions interpret: hex colors,
+// CSS color functions, keywords
+type textNode struct {
+	text string
+}
+
+// cssNode is opaque CSS resolved by the browser, such as var(--x)
+type cssNode struct {
+	text string
+}
+
+type unaryNode struct {
+	operand exprNode
+}
+
+type binaryNode struct {
+	op          byte
+	left, right exprNode
+}
+
+type callNode struct {
+	name string
+	args []exprNode
+}
+
+// exprParser is a recursive descent parser over tokenizeExpression output
+type exprParser struct {
+	toks []exprToken
+	pos  int
+}
+
+// parseExpression parses src into an AST
+func parseExpression(src string) (exprNode, error) {
+	toks, err := tokenizeExpression(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{toks: toks}
+	node, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != exprTokEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+	return node, nil
+}
+
+func (p *exprParser) peek() exprToken {
+	return p.toks[p.pos]
+}
+
+func (p *exprParser) next() exprToken {
+	tok := p.toks[p.pos]
+	if tok.kind != exprTokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *exprParser) parseSum() (exprNode, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		if tok.kind != exprTokOp || (tok.text != "+" && tok.text != "-") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: tok.text[0], left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseProduct() (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		if tok.kind != exprTokOp || (tok.text != "*" && tok.text != "/") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: tok.text[0], left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	tok := p.peek()
+	if tok.kind == exprTokOp && tok.text == "-" {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		// Fold negative literals so "-2px" stays a plain dimension
+		if num, ok := operand.(numberNode); ok {
+			num.dim.Value = -num.dim.Value
+			return num, nil
+		}
+		return unaryNode{operand: operand}, nil
+	}
+	if tok.kind == exprTokOp && tok.text == "+" {
+		p.next()
+		return p.parseUnary()
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case exprTokNumber:
+		return numberNode{dim: tok.dim}, nil
+	case exprTokRef:
+		return refNode{path: tok.text}, nil
+	case exprTokHash:
+		return textNode{text: tok.text}, nil
+	case exprTokRaw:
+		name := strings.ToLower(tok.text[:strings.IndexByte(tok.text, '(')])
+		if name == "var" || name == "env" {
+			return cssNode{text: tok.text}, nil
+		}
+		return textNode{text: tok.text}, nil
+	case exprTokIdent:
+		if p.peek().kind != exprTokLParen {
+			return textNode{text: tok.text}, nil
+		}
+		p.next()
+		return p.parseCall(tok.text)
+	case exprTokLParen:
+		node, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != exprTokRParen {
+			return nil, fmt.Errorf("expected ) at position %d", closing.pos)
+		}
+		return node, nil
+	case exprTokEOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+	default:
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+}
+
+// parseCall parses the argument list after "name("
+func (p *exprParser) parseCall(name string) (exprNode, error) {
+	call := callNode{name: strings.ToLower(name)}
+	if p.peek().kind == exprTokRParen {
+		p.next()
+		return call, nil
+	}
+	for {
+		arg, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		call.args = append(call.args, arg)
+
+		tok := p.next()
+		switch tok.kind {
+		case exprTokComma:
+			continue
+		case exprTokRParen:
+			return call, nil
+		default:
+			return nil, fmt.Errorf("%s: expected , or ) at position %d", name, tok.pos)
+		}
+	}
+}
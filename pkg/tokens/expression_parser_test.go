@@ -0,0 +1,119 @@
+// tokenctl/pkg/tokens/expression_parser_test.go
+
+package tokens
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseExpression(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		src  string
+		want exprNode
+	}{
+		{
+			name: "precedence",
+			src:  "1px + 2px * 3",
+			want: binaryNode{op: '+',
+				left: numberNode{dim: Dimension{Value: 1, Unit: "px"}},
+				right: binaryNode{op: '*',
+					left:  numberNode{dim: Dimension{Value: 2, Unit: "px"}},
+					right: numberNode{dim: Dimension{Value: 3}},
+				},
+			},
+		},
+		{
+			name: "parentheses override precedence",
+			src:  "(1px + 2px) * 3",
+			want: binaryNode{op: '*',
+				left: binaryNode{op: '+',
+					left:  numberNode{dim: Dimension{Value: 1, Unit: "px"}},
+					right: numberNode{dim: Dimension{Value: 2, Unit: "px"}},
+				},
+				right: numberNode{dim: Dimension{Value: 3}},
+			},
+		},
+		{
+			name: "negative literal is folded",
+			src:  "-0.5rem",
+			want: numberNode{dim: Dimension{Value: -0.5, Unit: "rem"}},
+		},
+		{
+			name: "unary minus on reference",
+			src:  "-{space.md}",
+			want: unaryNode{operand: refNode{path: "space.md"}},
+		},
+		{
+			name: "nested call",
+			src:  "calc(scale({size.md}, 2) - 1px)",
+			want: callNode{name: "calc", args: []exprNode{
+				binaryNode{op: '-',
+					left: callNode{name: "scale", args: []exprNode{
+						refNode{path: "size.md"},
+						numberNode{dim: Dimension{Value: 2}},
+					}},
+					right: numberNode{dim: Dimension{Value: 1, Unit: "px"}},
+				},
+			}},
+		},
+		{
+			name: "color literals",
+			src:  "darken(oklch(50% 0.1 200), 10%)",
+			want: callNode{name: "darken", args: []exprNode{
+				textNode{text: "oklch(50% 0.1 200)"},
+				numberNode{dim: Dimension{Value: 10, Unit: "%"}},
+			}},
+		},
+		{
+			name: "var is opaque css",
+			src:  "var(--gap, 1rem) * 2",
+			want: binaryNode{op: '*',
+				left:  cssNode{text: "var(--gap, 1rem)"},
+				right: numberNode{dim: Dimension{Value: 2}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseExpression(tt.src)
+			if err != nil {
+				t.Fatalf("parseExpression(%q) error: %v", tt.src, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseExpression(%q) = %#v, want %#v", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseExpression_Errors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		src     string
+		wantErr string
+	}{
+		{"calc(1px +)", "unexpected"},
+		{"calc(1px", "expected , or )"},
+		{"(1px + 2px", "expected )"},
+		{"1px 2px", "unexpected"},
+		{"calc({size.md)", "unterminated reference"},
+		{"calc(1px ; 2px)", "unexpected character"},
+		{"oklch(50% 0.1 200", "unbalanced parentheses"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			t.Parallel()
+			_, err := parseExpression(tt.src)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseExpression(%q) error = %v, want substring %q", tt.src, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -4,20 +4,31 @@ package tokens
 
 import (
 	"fmt"
-	"regexp"
-	"strconv"
 	"strings"
 
 	"github.com/dmoose/tokenctl/pkg/colors"
 )
 
 // ExpressionEvaluator evaluates expressions in token values
+// Expressions are parsed into an AST (see expression_parser.go), so
+// functions nest freely: calc(scale({size.md}, 2) - 1px).
 // Supported expressions:
-//   - calc({token} * 0.5) - arithmetic with dimensions
-//   - contrast({color.primary}) - generate content color
+//   - calc({token} * 0.5) - arithmetic with dimensions; units that cannot be
+//     combined at build time (100% - 2rem) are left to a browser-side calc()
+//   - min(), max(), clamp() - folded when all arguments share a unit
+//   - contrast({color.primary}[, apca][, {color.base-100}]) - generate
+//     content color, white or black by WCAG 2 contrast (default) or APCA;
+//     a translucent color is measured over the backdrop (default white)
 //   - darken({color.primary}, 10%) - darken a color
 //   - lighten({color.primary}, 10%) - lighten a color
 //   - shade({color.base}, 1) - derive color shade (1=slightly darker, 2=more darker, etc.)
+//   - scale({size.base}, 1.5) - multiply a dimension by a factor
+//   - fluid({size.sm}, {size.xl}, 320px, 1440px) - viewport-interpolated clamp()
+//   - mix({color.a}, {color.b}, 20%[, srgb]) - blend, 20% of a, in OKLCH or sRGB
+//   - alpha({color.primary}, 0.5) - set opacity
+//   - saturate({color.primary}, 20%) / desaturate(...) - scale OKLCH chroma
+//   - rotate-hue({color.primary}, 30deg) - rotate OKLCH hue
+//   - complement({color.primary}) - opposite hue
 type ExpressionEvaluator struct {
 	resolver *Resolver
 }
@@ -27,31 +38,6 @@ func NewExpressionEvaluator(r *Resolver) *ExpressionEvaluator {
 	return &ExpressionEvaluator{resolver: r}
 }
 
-// Expression patterns
-var (
-	// calcRegex matches calc(...) expressions
-	calcRegex = regexp.MustCompile(`^calc\((.+)\)$`)
-
-	// contrastRegex matches contrast({token}) expressions
-	contrastRegex = regexp.MustCompile(`^contrast\(\s*\{([^}]+)\}\s*\)$`)
-
-	// darkenRegex matches darken({token}, amount) expressions
-	darkenRegex = regexp.MustCompile(`^darken\(\s*\{([^}]+)\}\s*,\s*([0-9.]+)%?\s*\)$`)
-
-	// lightenRegex matches lighten({token}, amount) expressions
-	lightenRegex = regexp.MustCompile(`^lighten\(\s*\{([^}]+)\}\s*,\s*([0-9.]+)%?\s*\)$`)
-
-	// scaleRegex matches scale({token}, factor) expressions
-	scaleRegex = regexp.MustCompile(`^scale\(\s*\{([^}]+)\}\s*,\s*([0-9.]+)\s*\)$`)
-
-	// shadeRegex matches shade({token}, level) expressions
-	// level is typically 1, 2, 3... where higher = darker
-	shadeRegex = regexp.MustCompile(`^shade\(\s*\{([^}]+)\}\s*,\s*([0-9.]+)\s*\)$`)
-
-	// tokenRefRegex matches {token.path} in expressions
-	tokenRefRegex = regexp.MustCompile(`\{([^}]+)\}`)
-)
-
 // IsExpression checks if a value contains an expression that needs evaluation
 func IsExpression(value string) bool {
 	value = strings.TrimSpace(value)
@@ -60,256 +46,755 @@ func IsExpression(value string) bool {
 		strings.HasPrefix(value, "darken(") ||
 		strings.HasPrefix(value, "lighten(") ||
 		strings.HasPrefix(value, "scale(") ||
-		strings.HasPrefix(value, "shade(")
+		strings.HasPrefix(value, "fluid(") ||
+		strings.HasPrefix(value, "shade(") ||
+		strings.HasPrefix(value, "mix(") ||
+		strings.HasPrefix(value, "alpha(") ||
+		strings.HasPrefix(value, "saturate(") ||
+		strings.HasPrefix(value, "desaturate(") ||
+		strings.HasPrefix(value, "rotate-hue(") ||
+		strings.HasPrefix(value, "complement(")
 }
 
 // Evaluate processes an expression string and returns the computed value
 func (e *ExpressionEvaluator) Evaluate(expr string) (any, error) {
 	expr = strings.TrimSpace(expr)
 
-	// Try each expression type
-	if matches := calcRegex.FindStringSubmatch(expr); matches != nil {
-		return e.evaluateCalc(matches[1])
+	node, err := parseExpression(expr)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", expr, err)
+	}
+	if _, ok := node.(callNode); !ok {
+		return nil, fmt.Errorf("unrecognized expression: %s", expr)
 	}
 
-	if matches := contrastRegex.FindStringSubmatch(expr); matches != nil {
-		return e.evaluateContrast(matches[1])
+	val, err := e.eval(node)
+	if err != nil {
+		return nil, err
 	}
+	return val.String(), nil
+}
 
-	if matches := darkenRegex.FindStringSubmatch(expr); matches != nil {
-		amount, err := strconv.ParseFloat(matches[2], 64)
-		if err != nil {
-			return nil, fmt.Errorf("darken: invalid amount %q: %w", matches[2], err)
+// exprValueKind classifies intermediate expression values
+type exprValueKind int
+
+const (
+	// exprNumeric is a number or dimension known at build time
+	exprNumeric exprValueKind = iota
+	// exprText is a color or keyword, only meaningful as a function argument
+	exprText
+	// exprCSS is arithmetic the browser has to finish, e.g. 100% - 2rem
+	exprCSS
+)
+
+// Operator precedence, used to parenthesize CSS fragments
+const (
+	precSum = iota + 1
+	precProduct
+	precAtom
+)
+
+// exprValue is the result of evaluating an expression node
+type exprValue struct {
+	kind exprValueKind
+	dim  Dimension
+	text string
+	prec int // exprCSS only
+}
+
+func numericValue(d Dimension) exprValue { return exprValue{kind: exprNumeric, dim: d} }
+func textValue(s string) exprValue       { return exprValue{kind: exprText, text: s} }
+
+// String renders the value as a token value. Deferred arithmetic is wrapped
+// in calc() unless it is already a single CSS function such as min().
+func (v exprValue) String() string {
+	switch v.kind {
+	case exprNumeric:
+		return v.dim.String()
+	case exprCSS:
+		if v.prec == precAtom {
+			return v.text
 		}
-		return e.evaluateDarken(matches[1], amount/100)
+		return "calc(" + v.text + ")"
+	default:
+		return v.text
 	}
+}
 
-	if matches := lightenRegex.FindStringSubmatch(expr); matches != nil {
-		amount, err := strconv.ParseFloat(matches[2], 64)
-		if err != nil {
-			return nil, fmt.Errorf("lighten: invalid amount %q: %w", matches[2], err)
+// cssFragment renders the value for embedding in a larger CSS expression,
+// adding parentheses when its operator binds looser than minPrec
+func (v exprValue) cssFragment(minPrec int) string {
+	switch v.kind {
+	case exprNumeric:
+		return v.dim.String()
+	case exprCSS:
+		if v.prec < minPrec {
+			return "(" + v.text + ")"
 		}
-		return e.evaluateLighten(matches[1], amount/100)
+		return v.text
+	default:
+		return v.text
 	}
+}
 
-	if matches := scaleRegex.FindStringSubmatch(expr); matches != nil {
-		factor, err := strconv.ParseFloat(matches[2], 64)
-		if err != nil {
-			return nil, fmt.Errorf("scale: invalid factor %q: %w", matches[2], err)
-		}
-		return e.evaluateScale(matches[1], factor)
+// describe formats a value for error messages
+func (v exprValue) describe() string {
+	if v.kind == exprNumeric && v.dim.Unit == "" {
+		return "number " + v.dim.String()
 	}
+	return v.String()
+}
 
-	if matches := shadeRegex.FindStringSubmatch(expr); matches != nil {
-		level, err := strconv.ParseFloat(matches[2], 64)
+func (e *ExpressionEvaluator) eval(node exprNode) (exprValue, error) {
+	switch n := node.(type) {
+	case numberNode:
+		return numericValue(n.dim), nil
+	case textNode:
+		return e.evalText(n.text)
+	case cssNode:
+		return exprValue{kind: exprCSS, text: n.text, prec: precAtom}, nil
+	case refNode:
+		return e.evalReference(n.path)
+	case unaryNode:
+		operand, err := e.eval(n.operand)
+		if err != nil {
+			return exprValue{}, err
+		}
+		return e.multiply(numericValue(Dimension{Value: -1}), operand)
+	case binaryNode:
+		left, err := e.eval(n.left)
 		if err != nil {
-			return nil, fmt.Errorf("shade: invalid level %q: %w", matches[2], err)
+			return exprValue{}, err
+		}
+		right, err := e.eval(n.right)
+		if err != nil {
+			return exprValue{}, err
+		}
+		switch n.op {
+		case '+', '-':
+			return e.addSubtract(n.op, left, right)
+		case '*':
+			return e.multiply(left, right)
+		default:
+			return e.divide(left, right)
+		}
+	case callNode:
+		if !exprFunctions[n.name] {
+			return exprValue{}, fmt.Errorf("unrecognized function %s()", n.name)
 		}
-		return e.evaluateShade(matches[1], level)
+		val, err := e.evalCall(n)
+		if err != nil {
+			return exprValue{}, fmt.Errorf("%s: %w", n.name, err)
+		}
+		return val, nil
 	}
-
-	return nil, fmt.Errorf("unrecognized expression: %s", expr)
+	return exprValue{}, fmt.Errorf("unsupported expression node %T", node)
 }
 
-// evaluateCalc evaluates a calc() expression
-// Supports: +, -, *, / with dimensions and token references
-func (e *ExpressionEvaluator) evaluateCalc(inner string) (any, error) {
-	// First, resolve all token references in the expression
-	resolved, err := e.resolveTokensInExpression(inner)
+// evalReference resolves a {token} and classifies its value
+func (e *ExpressionEvaluator) evalReference(path string) (exprValue, error) {
+	resolved, err := e.resolver.resolveReference(path)
 	if err != nil {
-		return nil, fmt.Errorf("calc: %w", err)
+		return exprValue{}, fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+
+	switch v := resolved.(type) {
+	case float64:
+		return numericValue(Dimension{Value: v}), nil
+	case int:
+		return numericValue(Dimension{Value: float64(v)}), nil
+	case string:
+		s := strings.TrimSpace(v)
+		if dim, err := ParseDimension(s); err == nil {
+			return numericValue(dim), nil
+		}
+		// A token that already fell back to browser-side calc()
+		if strings.HasPrefix(s, "calc(") && strings.HasSuffix(s, ")") {
+			return exprValue{kind: exprCSS, text: s[len("calc(") : len(s)-1], prec: precSum}, nil
+		}
+		return textValue(s), nil
 	}
+	return exprValue{}, fmt.Errorf("%s is not a scalar value", path)
+}
 
-	// Parse and evaluate the arithmetic expression
-	result, err := e.evaluateArithmetic(resolved)
-	if err != nil {
-		return nil, fmt.Errorf("calc: %w", err)
+// evalText substitutes references inside a literal, as in the relative
+// color oklch(from {color.primary} l c h)
+func (e *ExpressionEvaluator) evalText(text string) (exprValue, error) {
+	for _, match := range refRegex.FindAllStringSubmatch(text, -1) {
+		resolved, err := e.resolver.resolveReference(match[1])
+		if err != nil {
+			return exprValue{}, fmt.Errorf("failed to resolve %s: %w", match[1], err)
+		}
+		text = strings.Replace(text, match[0], fmt.Sprintf("%v", resolved), 1)
 	}
+	return textValue(text), nil
+}
 
-	return result, nil
+// evalCall dispatches a function call
+func (e *ExpressionEvaluator) evalCall(call callNode) (exprValue, error) {
+	switch call.name {
+	case "calc":
+		args, err := e.evalArgs(call, 1)
+		if err != nil {
+			return exprValue{}, err
+		}
+		return args[0], nil
+	case "min", "max", "clamp":
+		return e.evalMinMax(call)
+	case "scale":
+		args, err := e.evalArgs(call, 2)
+		if err != nil {
+			return exprValue{}, err
+		}
+		if args[0].kind == exprText {
+			return exprValue{}, fmt.Errorf("invalid dimension %s", args[0].text)
+		}
+		if !isNumber(args[1]) {
+			return exprValue{}, fmt.Errorf("factor must be a number, got %s", args[1].describe())
+		}
+		return e.multiply(args[0], args[1])
+	case "fluid":
+		return e.evalFluid(call)
+	case "contrast":
+		return e.evalContrast(call)
+	case "darken", "lighten", "shade":
+		args, err := e.evalArgs(call, 2)
+		if err != nil {
+			return exprValue{}, err
+		}
+		c, err := colorArg(args[0])
+		if err != nil {
+			return exprValue{}, err
+		}
+		if args[1].kind != exprNumeric || (args[1].dim.Unit != "" && args[1].dim.Unit != "%") {
+			return exprValue{}, fmt.Errorf("invalid amount %s", args[1].describe())
+		}
+		amount := args[1].dim.Value
+		switch call.name {
+		case "darken":
+			return textValue(e.evaluateDarken(c, amount/100)), nil
+		case "lighten":
+			return textValue(e.evaluateLighten(c, amount/100)), nil
+		default:
+			return textValue(e.evaluateShade(c, amount)), nil
+		}
+	case "mix":
+		return e.evalMix(call)
+	case "alpha":
+		args, err := e.evalArgs(call, 2)
+		if err != nil {
+			return exprValue{}, err
+		}
+		c, err := colorArg(args[0])
+		if err != nil {
+			return exprValue{}, err
+		}
+		a, err := fractionArg(args[1])
+		if err != nil {
+			return exprValue{}, err
+		}
+		return textValue(formatColorLike(c.WithAlpha(a), c)), nil
+	case "saturate", "desaturate":
+		args, err := e.evalArgs(call, 2)
+		if err != nil {
+			return exprValue{}, err
+		}
+		c, err := colorArg(args[0])
+		if err != nil {
+			return exprValue{}, err
+		}
+		amount, err := fractionArg(args[1])
+		if err != nil {
+			return exprValue{}, err
+		}
+		if call.name == "desaturate" {
+			amount = -amount
+		}
+		return textValue(formatColorLike(colors.Saturate(c, amount), c)), nil
+	case "rotate-hue":
+		args, err := e.evalArgs(call, 2)
+		if err != nil {
+			return exprValue{}, err
+		}
+		c, err := colorArg(args[0])
+		if err != nil {
+			return exprValue{}, err
+		}
+		deg, err := angleArg(args[1])
+		if err != nil {
+			return exprValue{}, err
+		}
+		return textValue(formatColorLike(colors.RotateHue(c, deg), c)), nil
+	case "complement":
+		args, err := e.evalArgs(call, 1)
+		if err != nil {
+			return exprValue{}, err
+		}
+		c, err := colorArg(args[0])
+		if err != nil {
+			return exprValue{}, err
+		}
+		return textValue(formatColorLike(colors.Complement(c), c)), nil
+	}
+	return exprValue{}, fmt.Errorf("unrecognized function %s()", call.name)
 }
 
-// resolveTokensInExpression replaces all {token} references with their resolved values
-func (e *ExpressionEvaluator) resolveTokensInExpression(expr string) (string, error) {
-	result := expr
-	matches := tokenRefRegex.FindAllStringSubmatch(expr, -1)
+// Default viewport range for fluid()
+var (
+	defaultFluidMinViewport = Dimension{Value: 320, Unit: "px"}
+	defaultFluidMaxViewport = Dimension{Value: 1440, Unit: "px"}
+)
 
-	for _, match := range matches {
-		fullMatch := match[0] // {foo.bar}
-		tokenPath := match[1] // foo.bar
+// evalFluid handles fluid(min, max[, minViewport, maxViewport[, rootFontSize]]).
+// It returns clamp(min, intercept + slope*vw, max) where the preferred value
+// equals min at minViewport and max at maxViewport. Sizes may be any length
+// convertible to px (px, rem, em, pt, ...); the output uses min's unit.
+func (e *ExpressionEvaluator) evalFluid(call callNode) (exprValue, error) {
+	if n := len(call.args); n != 2 && n != 4 && n != 5 {
+		return exprValue{}, fmt.Errorf("expected 2, 4 or 5 arguments, got %d", n)
+	}
+	args := make([]exprValue, len(call.args))
+	for i, arg := range call.args {
+		val, err := e.eval(arg)
+		if err != nil {
+			return exprValue{}, err
+		}
+		if val.kind != exprNumeric {
+			return exprValue{}, fmt.Errorf("argument %d must be a dimension, got %s", i+1, val.describe())
+		}
+		args[i] = val
+	}
 
-		// Resolve the token
-		resolved, err := e.resolver.resolveReference(tokenPath)
+	root := e.resolver.RootFontSize()
+	if len(args) == 5 {
+		r := args[4].dim
+		if r.Unit != "px" || r.Value <= 0 {
+			return exprValue{}, fmt.Errorf("root font size must be a positive px value, got %s", r.String())
+		}
+		root = r.Value
+	}
+
+	minVw, maxVw := defaultFluidMinViewport, defaultFluidMaxViewport
+	if len(args) >= 4 {
+		minVw, maxVw = args[2].dim, args[3].dim
+	}
+
+	toPx := func(d Dimension, what string) (float64, error) {
+		px, err := d.ConvertTo("px", root)
 		if err != nil {
-			return "", fmt.Errorf("failed to resolve %s: %w", tokenPath, err)
+			return 0, fmt.Errorf("%s must be an absolute length or rem, got %s", what, d.String())
 		}
+		return px.Value, nil
+	}
 
-		// Convert to string
-		resolvedStr := fmt.Sprintf("%v", resolved)
-		result = strings.Replace(result, fullMatch, resolvedStr, 1)
+	minSize, err := toPx(args[0].dim, "min size")
+	if err != nil {
+		return exprValue{}, err
+	}
+	maxSize, err := toPx(args[1].dim, "max size")
+	if err != nil {
+		return exprValue{}, err
+	}
+	minView, err := toPx(minVw, "min viewport")
+	if err != nil {
+		return exprValue{}, err
+	}
+	maxView, err := toPx(maxVw, "max viewport")
+	if err != nil {
+		return exprValue{}, err
+	}
+	if maxView <= minView {
+		return exprValue{}, fmt.Errorf("max viewport %s must be larger than min viewport %s", maxVw.String(), minVw.String())
+	}
+	if minSize == maxSize {
+		return numericValue(args[0].dim), nil
 	}
 
-	return result, nil
-}
+	slope := (maxSize - minSize) / (maxView - minView)
+	intercept := minSize - slope*minView
 
-// evaluateArithmetic evaluates a simple arithmetic expression with dimensions
-// This is a simplified parser that handles: dimension op number or dimension op dimension
-func (e *ExpressionEvaluator) evaluateArithmetic(expr string) (string, error) {
-	expr = strings.TrimSpace(expr)
+	unit := args[0].dim.Unit
+	fromPx := func(px float64) Dimension {
+		d, _ := Dimension{Value: px, Unit: "px"}.ConvertTo(unit, root)
+		return d
+	}
 
-	// Try to parse as multiplication/division first (higher precedence in our simple case)
-	// Pattern: <dimension> * <number> or <dimension> / <number>
-	if idx := strings.LastIndex(expr, "*"); idx > 0 {
-		left := strings.TrimSpace(expr[:idx])
-		right := strings.TrimSpace(expr[idx+1:])
-		return e.evaluateMultiply(left, right)
+	lower, upper := fromPx(minSize), fromPx(maxSize)
+	if lower.Value > upper.Value {
+		lower, upper = upper, lower
 	}
 
-	if idx := strings.LastIndex(expr, "/"); idx > 0 {
-		left := strings.TrimSpace(expr[:idx])
-		right := strings.TrimSpace(expr[idx+1:])
-		return e.evaluateDivide(left, right)
+	preferred := fromPx(intercept).String()
+	vw := Dimension{Value: slope * 100, Unit: "vw"}
+	if vw.Value < 0 {
+		vw.Value = -vw.Value
+		preferred += " - " + vw.String()
+	} else {
+		preferred += " + " + vw.String()
 	}
 
-	// Try addition/subtraction
-	// Need to be careful not to match negative numbers
-	for i := len(expr) - 1; i > 0; i-- {
-		if expr[i] == '+' {
-			left := strings.TrimSpace(expr[:i])
-			right := strings.TrimSpace(expr[i+1:])
-			return e.evaluateAdd(left, right)
+	return exprValue{
+		kind: exprCSS,
+		text: fmt.Sprintf("clamp(%s, %s, %s)", lower.String(), preferred, upper.String()),
+		prec: precAtom,
+	}, nil
+}
+
+// evalMix handles mix(a, b, weight[, space]) where weight is the share
+// of a and space is oklch (default) or srgb
+func (e *ExpressionEvaluator) evalMix(call callNode) (exprValue, error) {
+	if len(call.args) != 3 && len(call.args) != 4 {
+		return exprValue{}, fmt.Errorf("expected 3 or 4 arguments, got %d", len(call.args))
+	}
+	args := make([]exprValue, len(call.args))
+	for i, arg := range call.args {
+		val, err := e.eval(arg)
+		if err != nil {
+			return exprValue{}, err
 		}
-		if expr[i] == '-' && i > 0 && expr[i-1] != '*' && expr[i-1] != '/' && expr[i-1] != '(' {
-			left := strings.TrimSpace(expr[:i])
-			right := strings.TrimSpace(expr[i+1:])
-			return e.evaluateSubtract(left, right)
+		args[i] = val
+	}
+
+	a, err := colorArg(args[0])
+	if err != nil {
+		return exprValue{}, err
+	}
+	b, err := colorArg(args[1])
+	if err != nil {
+		return exprValue{}, err
+	}
+	weight, err := fractionArg(args[2])
+	if err != nil {
+		return exprValue{}, err
+	}
+	space := colors.SpaceOKLCH
+	if len(args) == 4 {
+		if args[3].kind != exprText {
+			return exprValue{}, fmt.Errorf("invalid color space %s", args[3].describe())
 		}
+		space = strings.ToLower(args[3].text)
 	}
 
-	// No operators, return as-is (should be a dimension or number)
-	return expr, nil
+	mixed, err := colors.Mix(a, b, weight, space)
+	if err != nil {
+		return exprValue{}, err
+	}
+	return textValue(formatColorLike(mixed, a)), nil
 }
 
-func (e *ExpressionEvaluator) evaluateMultiply(left, right string) (string, error) {
-	// Left should be a dimension, right should be a number (or vice versa)
-	leftDim, leftErr := ParseDimension(left)
-	rightNum, rightErr := strconv.ParseFloat(right, 64)
-
-	if leftErr == nil && rightErr == nil {
-		// dimension * number
-		result := leftDim.Multiply(rightNum)
-		// Round to avoid floating point artifacts
-		result.Value = roundTo(result.Value, 4)
-		return result.String(), nil
+// fractionArg reads a 0-1 amount given either as a percentage (20%) or a
+// plain fraction (0.2)
+func fractionArg(v exprValue) (float64, error) {
+	if v.kind != exprNumeric {
+		return 0, fmt.Errorf("invalid amount %s", v.describe())
+	}
+	switch v.dim.Unit {
+	case "%":
+		v.dim.Value /= 100
+	case "":
+	default:
+		return 0, fmt.Errorf("invalid amount %s: use a percentage or a 0-1 fraction", v.describe())
+	}
+	if v.dim.Value < 0 || v.dim.Value > 1 {
+		return 0, fmt.Errorf("amount %s is outside 0-1", v.describe())
 	}
+	return v.dim.Value, nil
+}
 
-	// Try the reverse: number * dimension
-	leftNum, leftNumErr := strconv.ParseFloat(left, 64)
-	rightDim, rightDimErr := ParseDimension(right)
+// angleArg reads a hue angle in degrees; unitless values are degrees
+func angleArg(v exprValue) (float64, error) {
+	if v.kind != exprNumeric {
+		return 0, fmt.Errorf("invalid angle %s", v.describe())
+	}
+	if v.dim.Unit == "" {
+		return v.dim.Value, nil
+	}
+	deg, err := v.dim.ConvertTo("deg", 0)
+	if err != nil {
+		return 0, fmt.Errorf("invalid angle %s", v.describe())
+	}
+	return deg.Value, nil
+}
 
-	if leftNumErr == nil && rightDimErr == nil {
-		result := rightDim.Multiply(leftNum)
-		// Round to avoid floating point artifacts
-		result.Value = roundTo(result.Value, 4)
-		return result.String(), nil
+// colorOutputFormat is the format derived colors are emitted in: inputs
+// in formats that reach beyond sRGB (OKLCH, OKLab, Lab, LCH, Display-P3)
+// keep their format, everything else becomes hex
+func colorOutputFormat(input colors.Color) string {
+	switch f := input.OriginalFormat(); f {
+	case colors.FormatOKLCH, colors.FormatOKLab, colors.FormatLab, colors.FormatLCH, colors.FormatP3:
+		return f
 	}
+	return colors.FormatHex
+}
 
-	return "", fmt.Errorf("cannot multiply: %s * %s", left, right)
+// formatColorLike renders result in the output format for input
+func formatColorLike(result, input colors.Color) string {
+	return result.ToCSS(colorOutputFormat(input))
 }
 
-func (e *ExpressionEvaluator) evaluateDivide(left, right string) (string, error) {
-	leftDim, leftErr := ParseDimension(left)
-	rightNum, rightErr := strconv.ParseFloat(right, 64)
+// exprFunctions lists the functions evalCall understands
+var exprFunctions = map[string]bool{
+	"calc": true, "min": true, "max": true, "clamp": true, "scale": true, "fluid": true,
+	"contrast": true, "darken": true, "lighten": true, "shade": true,
+	"mix": true, "alpha": true, "saturate": true, "desaturate": true,
+	"rotate-hue": true, "complement": true,
+}
 
-	if leftErr == nil && rightErr == nil {
-		result, err := leftDim.Divide(rightNum)
+// evalArgs evaluates call arguments, requiring exactly want of them
+func (e *ExpressionEvaluator) evalArgs(call callNode, want int) ([]exprValue, error) {
+	if len(call.args) != want {
+		return nil, fmt.Errorf("expected %d argument(s), got %d", want, len(call.args))
+	}
+	args := make([]exprValue, len(call.args))
+	for i, arg := range call.args {
+		val, err := e.eval(arg)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
-		// Round to avoid floating point artifacts
-		result.Value = roundTo(result.Value, 4)
-		return result.String(), nil
+		args[i] = val
 	}
-
-	return "", fmt.Errorf("cannot divide: %s / %s", left, right)
+	return args, nil
 }
 
-func (e *ExpressionEvaluator) evaluateAdd(left, right string) (string, error) {
-	leftDim, leftErr := ParseDimension(left)
-	rightDim, rightErr := ParseDimension(right)
-
-	if leftErr == nil && rightErr == nil {
-		result, err := leftDim.Add(rightDim)
+// evalContrast evaluates contrast(bg[, wcag2|apca][, backdrop]), where
+// backdrop is what a translucent bg is painted over (default white)
+func (e *ExpressionEvaluator) evalContrast(call callNode) (exprValue, error) {
+	if len(call.args) < 1 || len(call.args) > 3 {
+		return exprValue{}, fmt.Errorf("expected 1 to 3 arguments, got %d", len(call.args))
+	}
+	args := make([]exprValue, len(call.args))
+	for i, arg := range call.args {
+		val, err := e.eval(arg)
 		if err != nil {
-			return "", err
+			return exprValue{}, err
 		}
-		return result.String(), nil
+		args[i] = val
 	}
 
-	return "", fmt.Errorf("cannot add: %s + %s", left, right)
+	c, err := colorArg(args[0])
+	if err != nil {
+		return exprValue{}, err
+	}
+	algorithm := colors.AlgorithmWCAG2
+	backdrop := colors.White()
+	for i, arg := range args[1:] {
+		if i == 0 && arg.kind == exprText {
+			if alg, err := colors.ParseAlgorithm(strings.ToLower(arg.text)); err == nil {
+				algorithm = alg
+				continue
+			}
+		}
+		if backdrop, err = colorArg(arg); err != nil {
+			return exprValue{}, fmt.Errorf("invalid contrast algorithm or backdrop %s", arg.describe())
+		}
+	}
+	return textValue(e.evaluateContrast(c, algorithm, backdrop)), nil
 }
 
-func (e *ExpressionEvaluator) evaluateSubtract(left, right string) (string, error) {
-	leftDim, leftErr := ParseDimension(left)
-	rightDim, rightErr := ParseDimension(right)
+// evalMinMax folds min(), max() and clamp() when every argument converts
+// to a common unit, as addSubtract does, and otherwise leaves the
+// function for the browser. The argument that wins is kept as written.
+func (e *ExpressionEvaluator) evalMinMax(call callNode) (exprValue, error) {
+	if call.name == "clamp" && len(call.args) != 3 {
+		return exprValue{}, fmt.Errorf("expected 3 arguments, got %d", len(call.args))
+	}
+	if len(call.args) == 0 {
+		return exprValue{}, fmt.Errorf("expected at least 1 argument")
+	}
 
-	if leftErr == nil && rightErr == nil {
-		result, err := leftDim.Subtract(rightDim)
+	args := make([]exprValue, len(call.args))
+	values := make([]float64, len(call.args)) // in the first argument's unit
+	foldable := true
+	for i, arg := range call.args {
+		val, err := e.eval(arg)
+		if err != nil {
+			return exprValue{}, err
+		}
+		if val.kind == exprText {
+			return exprValue{}, fmt.Errorf("cannot use %s in arithmetic", val.text)
+		}
+		args[i] = val
+		if !foldable || val.kind != exprNumeric {
+			foldable = false
+			continue
+		}
+		converted, err := val.dim.ConvertTo(args[0].dim.Unit, e.resolver.RootFontSize())
 		if err != nil {
-			return "", err
+			foldable = false
+			continue
 		}
-		return result.String(), nil
+		values[i] = converted.Value
+	}
+
+	if foldable {
+		pick := 0
+		switch call.name {
+		case "min":
+			for i, v := range values {
+				if v < values[pick] {
+					pick = i
+				}
+			}
+		case "max":
+			for i, v := range values {
+				if v > values[pick] {
+					pick = i
+				}
+			}
+		default:
+			// clamp(lower, value, upper) = max(lower, min(value, upper))
+			pick = 1
+			if values[2] < values[pick] {
+				pick = 2
+			}
+			if values[0] > values[pick] {
+				pick = 0
+			}
+		}
+		return numericValue(args[pick].dim), nil
+	}
+
+	parts := make([]string, len(args))
+	for i, a := range args {
+		parts[i] = a.cssFragment(precSum)
 	}
+	return exprValue{kind: exprCSS, text: call.name + "(" + strings.Join(parts, ", ") + ")", prec: precAtom}, nil
+}
 
-	return "", fmt.Errorf("cannot subtract: %s - %s", left, right)
+func isNumber(v exprValue) bool {
+	return v.kind == exprNumeric && v.dim.Unit == ""
 }
 
-// evaluateContrast generates a content color for the given color token
-func (e *ExpressionEvaluator) evaluateContrast(tokenPath string) (string, error) {
-	// Resolve the color token
-	resolved, err := e.resolver.resolveReference(tokenPath)
+// colorArg parses a color argument
+func colorArg(v exprValue) (colors.Color, error) {
+	if v.kind != exprText {
+		return colors.Color{}, fmt.Errorf("%s is not a color value", v.describe())
+	}
+	c, err := colors.Parse(v.text)
 	if err != nil {
-		return "", fmt.Errorf("contrast: failed to resolve %s: %w", tokenPath, err)
+		return colors.Color{}, fmt.Errorf("invalid color %s: %w", v.text, err)
 	}
+	return c, nil
+}
 
-	colorStr, ok := resolved.(string)
-	if !ok {
-		return "", fmt.Errorf("contrast: %s is not a string color value", tokenPath)
+// addSubtract adds or subtracts two values. Units that convert (px, rem,
+// em, pt, ..., s/ms, deg/rad/turn) are folded at build time using the root
+// font size; lengths that depend on layout (%, vw, ch, ...) fall back to a
+// browser-side calc().
+func (e *ExpressionEvaluator) addSubtract(op byte, left, right exprValue) (exprValue, error) {
+	verb := "add"
+	if op == '-' {
+		verb = "subtract"
+	}
+	if left.kind == exprText || right.kind == exprText {
+		return exprValue{}, fmt.Errorf("cannot %s %s and %s", verb, left.describe(), right.describe())
 	}
 
-	// Parse the color
-	bgColor, err := colors.Parse(colorStr)
-	if err != nil {
-		return "", fmt.Errorf("contrast: invalid color %s: %w", colorStr, err)
+	if left.kind == exprNumeric && right.kind == exprNumeric {
+		l, r := left.dim, right.dim
+		if (l.Unit == "") != (r.Unit == "") {
+			return exprValue{}, fmt.Errorf("cannot %s %s and %s: mixing numbers and dimensions", verb, left.describe(), right.describe())
+		}
+		if unitCategory(l.Unit) != unitCategory(r.Unit) {
+			return exprValue{}, fmt.Errorf("cannot %s %s and %s: incompatible units", verb, left.describe(), right.describe())
+		}
+		if converted, err := r.ConvertTo(l.Unit, e.resolver.RootFontSize()); err == nil {
+			if op == '-' {
+				converted.Value = -converted.Value
+			}
+			return numericValue(Dimension{Value: l.Value + converted.Value, Unit: l.Unit}), nil
+		}
+	} else if left.kind == exprNumeric && left.dim.Unit == "" || right.kind == exprNumeric && right.dim.Unit == "" {
+		return exprValue{}, fmt.Errorf("cannot %s %s and %s: mixing numbers and dimensions", verb, left.describe(), right.describe())
 	}
 
-	// Generate content color
-	contentColor := colors.ContentColor(bgColor)
+	// Leave it to the browser
+	rightPrec := precSum
+	if op == '-' {
+		rightPrec = precProduct
+	}
+	return exprValue{
+		kind: exprCSS,
+		text: left.cssFragment(precSum) + " " + string(op) + " " + right.cssFragment(rightPrec),
+		prec: precSum,
+	}, nil
+}
 
-	// Return in the same format as input, or OKLCH for oklch inputs
-	if bgColor.OriginalFormat() == colors.FormatOKLCH {
-		return contentColor.ToOKLCH(), nil
+// multiply requires at least one side to be a plain number
+func (e *ExpressionEvaluator) multiply(left, right exprValue) (exprValue, error) {
+	if left.kind == exprText || right.kind == exprText {
+		return exprValue{}, fmt.Errorf("cannot multiply %s by %s", left.describe(), right.describe())
 	}
-	return contentColor.Hex(), nil
+	if !isNumber(left) && !isNumber(right) {
+		return exprValue{}, fmt.Errorf("cannot multiply %s by %s: one side must be a number", left.describe(), right.describe())
+	}
+	if left.kind == exprNumeric && right.kind == exprNumeric {
+		unit := left.dim.Unit
+		if unit == "" {
+			unit = right.dim.Unit
+		}
+		return numericValue(Dimension{Value: roundTo(left.dim.Value*right.dim.Value, 4), Unit: unit}), nil
+	}
+	return exprValue{
+		kind: exprCSS,
+		text: left.cssFragment(precProduct) + " * " + right.cssFragment(precProduct),
+		prec: precProduct,
+	}, nil
 }
 
-// evaluateDarken darkens a color by the given amount (0-1)
-func (e *ExpressionEvaluator) evaluateDarken(tokenPath string, amount float64) (string, error) {
-	resolved, err := e.resolver.resolveReference(tokenPath)
-	if err != nil {
-		return "", fmt.Errorf("darken: failed to resolve %s: %w", tokenPath, err)
+// divide requires a number on the right, or two dimensions of the same
+// unit (which yields a ratio)
+func (e *ExpressionEvaluator) divide(left, right exprValue) (exprValue, error) {
+	if left.kind == exprText || right.kind == exprText {
+		return exprValue{}, fmt.Errorf("cannot divide %s by %s", left.describe(), right.describe())
 	}
+	if right.kind != exprNumeric {
+		return exprValue{}, fmt.Errorf("cannot divide by %s: divisor must be known at build time", right.describe())
+	}
+	if right.dim.Value == 0 {
+		return exprValue{}, fmt.Errorf("division by zero")
+	}
+	if right.dim.Unit != "" {
+		if left.kind != exprNumeric || left.dim.Unit != right.dim.Unit {
+			return exprValue{}, fmt.Errorf("cannot divide %s by %s", left.describe(), right.describe())
+		}
+		return numericValue(Dimension{Value: roundTo(left.dim.Value/right.dim.Value, 4)}), nil
+	}
+	if left.kind == exprNumeric {
+		return numericValue(Dimension{Value: roundTo(left.dim.Value/right.dim.Value, 4), Unit: left.dim.Unit}), nil
+	}
+	return exprValue{
+		kind: exprCSS,
+		text: left.cssFragment(precProduct) + " / " + right.cssFragment(precAtom),
+		prec: precProduct,
+	}, nil
+}
 
-	colorStr, ok := resolved.(string)
-	if !ok {
-		return "", fmt.Errorf("darken: %s is not a string color value", tokenPath)
+// evaluateContrast generates a content color for the given background;
+// a translucent background is measured as it shows over backdrop
+func (e *ExpressionEvaluator) evaluateContrast(bgColor colors.Color, algorithm string, backdrop colors.Color) string {
+	surface := colors.Composite(bgColor, colors.Composite(backdrop, colors.White()))
+	contentColor := colors.ContentColor(surface)
+	if algorithm == colors.AlgorithmAPCA {
+		contentColor = colors.APCAContentColor(surface)
 	}
 
-	c, err := colors.Parse(colorStr)
-	if err != nil {
-		return "", fmt.Errorf("darken: invalid color %s: %w", colorStr, err)
+	// Return in the same format as input, or OKLCH for oklch inputs
+	return formatColorLike(contentColor, bgColor)
+}
+
+// mapLike maps a color derived from src into the gamut src is in: sRGB,
+// or Display-P3 for a wide-gamut source, so deriving from a P3 color
+// keeps its chroma
+func mapLike(result, src colors.Color) colors.Color {
+	if colors.InGamut(src, colors.GamutSRGB) {
+		return colors.MapToGamut(result, colors.GamutSRGB)
 	}
+	return colors.MapToGamut(result, colors.GamutDisplayP3)
+}
 
+// evaluateDarken darkens a color by the given amount (0-1)
+func (e *ExpressionEvaluator) evaluateDarken(c colors.Color, amount float64) string {
 	// Darken by reducing lightness in OKLCH space
 	l, ch, h := c.OkLch()
 	newL := l * (1 - amount)
@@ -317,31 +802,12 @@ func (e *ExpressionEvaluator) evaluateDarken(tokenPath string, amount float64) (
 		newL = 0
 	}
 
-	result := colors.FromOkLch(newL, ch, h).Clamped()
-
-	if c.OriginalFormat() == colors.FormatOKLCH {
-		return result.ToOKLCH(), nil
-	}
-	return result.Hex(), nil
+	result := mapLike(colors.FromOkLch(newL, ch, h).WithAlpha(c.Alpha()), c)
+	return formatColorLike(result, c)
 }
 
 // evaluateLighten lightens a color by the given amount (0-1)
-func (e *ExpressionEvaluator) evaluateLighten(tokenPath string, amount float64) (string, error) {
-	resolved, err := e.resolver.resolveReference(tokenPath)
-	if err != nil {
-		return "", fmt.Errorf("lighten: failed to resolve %s: %w", tokenPath, err)
-	}
-
-	colorStr, ok := resolved.(string)
-	if !ok {
-		return "", fmt.Errorf("lighten: %s is not a string color value", tokenPath)
-	}
-
-	c, err := colors.Parse(colorStr)
-	if err != nil {
-		return "", fmt.Errorf("lighten: invalid color %s: %w", colorStr, err)
-	}
-
+func (e *ExpressionEvaluator) evaluateLighten(c colors.Color, amount float64) string {
 	// Lighten by increasing lightness in OKLCH space
 	l, ch, h := c.OkLch()
 	newL := l + (1-l)*amount
@@ -349,56 +815,14 @@ func (e *ExpressionEvaluator) evaluateLighten(tokenPath string, amount float64)
 		newL = 1
 	}
 
-	result := colors.FromOkLch(newL, ch, h).Clamped()
-
-	if c.OriginalFormat() == colors.FormatOKLCH {
-		return result.ToOKLCH(), nil
-	}
-	return result.Hex(), nil
-}
-
-// evaluateScale multiplies a dimension by a factor
-func (e *ExpressionEvaluator) evaluateScale(tokenPath string, factor float64) (string, error) {
-	resolved, err := e.resolver.resolveReference(tokenPath)
-	if err != nil {
-		return "", fmt.Errorf("scale: failed to resolve %s: %w", tokenPath, err)
-	}
-
-	dimStr, ok := resolved.(string)
-	if !ok {
-		return "", fmt.Errorf("scale: %s is not a string dimension value", tokenPath)
-	}
-
-	dim, err := ParseDimension(dimStr)
-	if err != nil {
-		return "", fmt.Errorf("scale: invalid dimension %s: %w", dimStr, err)
-	}
-
-	result := dim.Multiply(factor)
-	// Round to avoid floating point artifacts
-	result.Value = roundTo(result.Value, 4)
-	return result.String(), nil
+	result := mapLike(colors.FromOkLch(newL, ch, h).WithAlpha(c.Alpha()), c)
+	return formatColorLike(result, c)
 }
 
 // evaluateShade derives a shade from a base color
 // level: 1 = slightly darker (~4% lightness reduction), 2 = more darker (~8%), etc.
 // This is useful for generating base-200, base-300 from base-100
-func (e *ExpressionEvaluator) evaluateShade(tokenPath string, level float64) (string, error) {
-	resolved, err := e.resolver.resolveReference(tokenPath)
-	if err != nil {
-		return "", fmt.Errorf("shade: failed to resolve %s: %w", tokenPath, err)
-	}
-
-	colorStr, ok := resolved.(string)
-	if !ok {
-		return "", fmt.Errorf("shade: %s is not a string color value", tokenPath)
-	}
-
-	c, err := colors.Parse(colorStr)
-	if err != nil {
-		return "", fmt.Errorf("shade: invalid color %s: %w", colorStr, err)
-	}
-
+func (e *ExpressionEvaluator) evaluateShade(c colors.Color, level float64) string {
 	// Each shade level reduces lightness by ~4% (0.04 in OKLCH)
 	// This matches DaisyUI's base color progression
 	l, ch, h := c.OkLch()
@@ -408,10 +832,6 @@ func (e *ExpressionEvaluator) evaluateShade(tokenPath string, level float64) (st
 		newL = 0
 	}
 
-	result := colors.FromOkLch(newL, ch, h).Clamped()
-
-	if c.OriginalFormat() == colors.FormatOKLCH {
-		return result.ToOKLCH(), nil
-	}
-	return result.Hex(), nil
+	result := mapLike(colors.FromOkLch(newL, ch, h).WithAlpha(c.Alpha()), c)
+	return formatColorLike(result, c)
 }
@@ -3,8 +3,11 @@
 package tokens
 
 import (
+	"math"
 	"strings"
 	"testing"
+
+	"github.com/dmoose/tokenctl/pkg/colors"
 )
 
 func TestIsExpression(t *testing.T) {
@@ -115,11 +118,155 @@ func TestExpressionEvaluator_Calc(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name:    "incompatible units for add",
-			tokens:  map[string]any{"a": "10px", "b": "1rem"},
+			name:   "rem converts at the root font size",
+			tokens: map[string]any{"a": "10px", "b": "1rem"},
+			expr:   "calc({a} + {b})",
+			want:   "26px",
+		},
+		{
+			name:   "layout-dependent units fall back to browser calc",
+			tokens: map[string]any{"a": "10px", "b": "2vw"},
+			expr:   "calc({a} + {b})",
+			want:   "calc(10px + 2vw)",
+		},
+		{
+			name:   "percentage minus rem",
+			tokens: map[string]any{"gap": "2rem"},
+			expr:   "calc(100% - {gap})",
+			want:   "calc(100% - 2rem)",
+		},
+		{
+			name:   "fallback keeps grouping",
+			tokens: map[string]any{"gap": "1rem"},
+			expr:   "calc((100% - {gap}) / 2)",
+			want:   "calc((100% - 1rem) / 2)",
+		},
+		{
+			name:   "absolute units are converted",
+			tokens: map[string]any{"a": "10px", "b": "6pt"},
+			expr:   "calc({a} + {b})",
+			want:   "18px",
+		},
+		{
+			name:   "time units are converted",
+			tokens: map[string]any{"a": "1s", "b": "250ms"},
+			expr:   "calc({a} - {b})",
+			want:   "0.75s",
+		},
+		{
+			name:   "operator precedence",
+			tokens: map[string]any{"a": "4px", "b": "2px"},
+			expr:   "calc({a} + {b} * 2)",
+			want:   "8px",
+		},
+		{
+			name:   "parentheses",
+			tokens: map[string]any{"a": "4px", "b": "2px"},
+			expr:   "calc(({a} + {b}) * 2)",
+			want:   "12px",
+		},
+		{
+			name:   "left associative subtraction",
+			tokens: map[string]any{},
+			expr:   "calc(10px - 4px - 2px)",
+			want:   "4px",
+		},
+		{
+			name:   "unary minus",
+			tokens: map[string]any{"space": "1rem"},
+			expr:   "calc(-{space})",
+			want:   "-1rem",
+		},
+		{
+			name:   "unary minus on group",
+			tokens: map[string]any{"space": "1rem"},
+			expr:   "calc(-({space} * 2))",
+			want:   "-2rem",
+		},
+		{
+			name:   "nested function",
+			tokens: map[string]any{"size.md": "8px"},
+			expr:   "calc(scale({size.md}, 2) - 1px)",
+			want:   "15px",
+		},
+		{
+			name:   "same-unit division yields a ratio",
+			tokens: map[string]any{"a": "24px", "b": "16px"},
+			expr:   "calc({a} / {b})",
+			want:   "1.5",
+		},
+		{
+			name:   "max folds when units agree",
+			tokens: map[string]any{"a": "4px"},
+			expr:   "calc(max({a}, 6px) + 1px)",
+			want:   "7px",
+		},
+		{
+			name:   "min folds convertible units",
+			tokens: map[string]any{"a": "1rem"},
+			expr:   "calc(min(10px, {a}) + 2px)",
+			want:   "12px",
+		},
+		{
+			name:   "max keeps the winning argument's unit",
+			tokens: map[string]any{"a": "1rem"},
+			expr:   "max(10px, {a})",
+			want:   "1rem",
+		},
+		{
+			name:   "clamp folds convertible units",
+			tokens: map[string]any{"a": "40px"},
+			expr:   "clamp(1rem, {a}, 2rem)",
+			want:   "2rem",
+		},
+		{
+			name:   "min with mixed units stays in css",
+			tokens: map[string]any{"a": "40rem"},
+			expr:   "calc(min(100%, {a}) - 2rem)",
+			want:   "calc(min(100%, 40rem) - 2rem)",
+		},
+		{
+			name:   "var passes through",
+			tokens: map[string]any{"gap": "1rem"},
+			expr:   "calc(var(--offset) + {gap})",
+			want:   "calc(var(--offset) + 1rem)",
+		},
+		{
+			name:   "reference to a deferred calc",
+			tokens: map[string]any{"inner": "calc(100% - 2rem)"},
+			expr:   "calc({inner} * 2)",
+			want:   "calc((100% - 2rem) * 2)",
+		},
+		{
+			name:    "number plus dimension",
+			tokens:  map[string]any{"a": "10px"},
+			expr:    "calc({a} + 2)",
+			wantErr: true,
+		},
+		{
+			name:    "time plus length",
+			tokens:  map[string]any{"a": "1s", "b": "2px"},
 			expr:    "calc({a} + {b})",
 			wantErr: true,
 		},
+		{
+			name:    "multiply two dimensions",
+			tokens:  map[string]any{"a": "2px", "b": "3px"},
+			expr:    "calc({a} * {b})",
+			wantErr: true,
+		},
+		{
+			name:    "color in arithmetic",
+			tokens:  map[string]any{"c": "#fff"},
+			expr:    "calc({c} * 2)",
+			wantErr: true,
+		},
+		{
+			name:    "unbalanced parentheses",
+			tokens:  map[string]any{"a": "2px"},
+			expr:    "calc(({a} * 2)",
+			wantErr: true,
+		},
 		{
 			name:    "divide by zero",
 			tokens:  map[string]any{"size": "10px"},
@@ -225,6 +372,134 @@ func TestExpressionEvaluator_Contrast(t *testing.T) {
 	}
 }
 
+func TestExpressionEvaluator_ContrastAlgorithm(t *testing.T) {
+	t.Parallel()
+	resolver := createTestResolver(map[string]any{"color.orange": "#f97316"})
+	eval := NewExpressionEvaluator(resolver)
+	tests := []struct {
+		expr    string
+		want    string
+		wantErr bool
+	}{
+		// WCAG 2 rates black higher on mid-tone orange; APCA rates white higher
+		{expr: "contrast({color.orange})", want: "#000000"},
+		{expr: "contrast({color.orange}, wcag2)", want: "#000000"},
+		{expr: "contrast({color.orange}, apca)", want: "#ffffff"},
+		{expr: "contrast({color.orange}, APCA)", want: "#ffffff"},
+		{expr: "contrast({color.orange}, wcag3)", wantErr: true},
+		{expr: "contrast({color.orange}, 2)", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := eval.Evaluate(tt.expr)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Evaluate(%q) error = %v, wantErr %v", tt.expr, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("Evaluate(%q) = %v, want %s", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestExpressionEvaluator_CSSColor4Inputs(t *testing.T) {
+	t.Parallel()
+	resolver := createTestResolver(map[string]any{"color.blue": "#3b82f6", "color.navy": "#1e3a8a"})
+	eval := NewExpressionEvaluator(resolver)
+	tests := []struct {
+		expr string
+		want string
+	}{
+		// Wide-gamut inputs keep their format, others become hex
+		{expr: "darken(lab(54.29% 80.8 69.89), 10%)", want: "lab(48.83% 74.53 64.46)"},
+		{expr: "contrast(hwb(0 100% 0%))", want: "#000000"},
+		{expr: "contrast(rgb(from {color.navy} r g b))", want: "#ffffff"},
+		{expr: "darken(oklch(from {color.blue} calc(l - 0.1) c h), 10%)", want: "oklch(47.08% 0.188 259.84)"},
+	}
+	for _, tt := range tests {
+		got, err := eval.Evaluate(tt.expr)
+		if err != nil {
+			t.Errorf("Evaluate(%q): %v", tt.expr, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Evaluate(%q) = %v, want %s", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestExpressionEvaluator_KeepsAlpha(t *testing.T) {
+	t.Parallel()
+	resolver := createTestResolver(map[string]any{
+		"color.glass":   "#3b82f680",
+		"color.scrim":   "#00000080",
+		"color.night":   "#0f172a",
+		"color.overlay": "oklch(62.31% 0.188 259.82 / 0.5)",
+	})
+	eval := NewExpressionEvaluator(resolver)
+	tests := []struct {
+		expr    string
+		want    string
+		wantErr bool
+	}{
+		{expr: "darken({color.glass}, 10%)", want: "#276ee080"},
+		{expr: "lighten({color.overlay}, 10%)", want: "oklch(65.80% 0.183 259.30 / 0.5)"},
+		{expr: "complement({color.glass})", want: "#c1740080"},
+		{expr: "mix({color.glass}, transparent, 50%, srgb)", want: "#3b82f640"},
+		{expr: "alpha({color.glass}, 1)", want: "#3b82f6"},
+		// Half-transparent black reads as mid gray over white, but as
+		// near-black over a dark backdrop
+		{expr: "contrast({color.scrim})", want: "#000000"},
+		{expr: "contrast({color.scrim}, {color.night})", want: "#ffffff"},
+		{expr: "contrast({color.scrim}, apca, {color.night})", want: "#ffffff"},
+		{expr: "contrast({color.scrim}, 2)", wantErr: true},
+		{expr: "contrast({color.scrim}, {color.night}, apca)", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := eval.Evaluate(tt.expr)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Evaluate(%q) error = %v, wantErr %v", tt.expr, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("Evaluate(%q) = %v, want %s", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestExpressionEvaluator_DarkenMapsGamut(t *testing.T) {
+	t.Parallel()
+	resolver := createTestResolver(map[string]any{"color.neon": "oklch(70% 0.35 150)"})
+	eval := NewExpressionEvaluator(resolver)
+	for _, expr := range []string{"darken({color.neon}, 10%)", "lighten({color.neon}, 10%)", "shade({color.neon}, 1)"} {
+		got, err := eval.Evaluate(expr)
+		if err != nil {
+			t.Fatalf("Evaluate(%q): %v", expr, err)
+		}
+		c := colors.MustParse(got.(string))
+		// Chroma reduction holds the hue within a few degrees; clipping
+		// swings it to about 142
+		if _, _, h := c.OkLch(); math.Abs(h-150) > 4 {
+			t.Errorf("Evaluate(%q) = %s, hue %.2f drifted from 150", expr, got, h)
+		}
+	}
+}
+
+func TestExpressionEvaluator_DarkenKeepsP3(t *testing.T) {
+	t.Parallel()
+	resolver := createTestResolver(map[string]any{"color.red": "color(display-p3 1 0 0)"})
+	eval := NewExpressionEvaluator(resolver)
+	for _, expr := range []string{"darken({color.red}, 5%)", "lighten({color.red}, 5%)", "shade({color.red}, 1)"} {
+		got, err := eval.Evaluate(expr)
+		if err != nil {
+			t.Fatalf("Evaluate(%q): %v", expr, err)
+		}
+		c := colors.MustParse(got.(string))
+		if colors.InGamut(c, colors.GamutSRGB) || !colors.InGamut(c, colors.GamutDisplayP3) {
+			t.Errorf("Evaluate(%q) = %s, want a color in Display-P3 but outside sRGB", expr, got)
+		}
+	}
+}
+
 func TestExpressionEvaluator_Darken(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -550,3 +825,281 @@ func TestExpressionEvaluator_Shade(t *testing.T) {
 		})
 	}
 }
+
+// Expected OKLCH strings carry the small hue drift of converting through
+// sRGB, which is why they are not round numbers.
+func TestExpressionEvaluator_ColorFunctions(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		tokens  map[string]any
+		expr    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "mix in srgb matches color-mix",
+			tokens: map[string]any{"color.a": "#ff0000", "color.b": "#0000ff"},
+			expr:   "mix({color.a}, {color.b}, 50%, srgb)",
+			want:   "#800080",
+		},
+		{
+			name:   "mix weight is the share of the first color",
+			tokens: map[string]any{"color.primary": "#3b82f6", "color.base": "#ffffff"},
+			expr:   "mix({color.primary}, {color.base}, 0%)",
+			want:   "#ffffff",
+		},
+		{
+			name:   "mix in oklch takes the shorter hue arc",
+			tokens: map[string]any{"color.a": "oklch(70% 0.1 350)", "color.b": "oklch(70% 0.1 10)"},
+			expr:   "mix({color.a}, {color.b}, 50%)",
+			want:   "oklch(70.00% 0.100 359.90)",
+		},
+		{
+			name:   "mix with white keeps the hue",
+			tokens: map[string]any{"color.a": "oklch(60% 0.15 250)", "color.b": "oklch(100% 0 0)"},
+			expr:   "mix({color.a}, {color.b}, 20%)",
+			want:   "oklch(92.00% 0.030 250.06)",
+		},
+		{
+			name:   "alpha on hex",
+			tokens: map[string]any{"color.primary": "#3b82f6"},
+			expr:   "alpha({color.primary}, 0.5)",
+			want:   "#3b82f680",
+		},
+		{
+			name:   "alpha on oklch",
+			tokens: map[string]any{"color.primary": "oklch(60% 0.15 250)"},
+			expr:   "alpha({color.primary}, 25%)",
+			want:   "oklch(60% 0.15 250 / 0.25)",
+		},
+		{
+			name:   "opaque alpha leaves color unchanged",
+			tokens: map[string]any{"color.primary": "#3b82f6"},
+			expr:   "alpha({color.primary}, 1)",
+			want:   "#3b82f6",
+		},
+		{
+			name:   "desaturate fully yields gray",
+			tokens: map[string]any{"color.primary": "oklch(60% 0.15 250)"},
+			expr:   "desaturate({color.primary}, 100%)",
+			want:   "oklch(60.00% 0.000 none)",
+		},
+		{
+			name:   "saturate scales chroma",
+			tokens: map[string]any{"color.primary": "oklch(60% 0.1 250)"},
+			expr:   "saturate({color.primary}, 20%)",
+			want:   "oklch(60.00% 0.120 250.03)",
+		},
+		{
+			name:   "rotate-hue",
+			tokens: map[string]any{"color.primary": "oklch(60% 0.1 350)"},
+			expr:   "rotate-hue({color.primary}, 30deg)",
+			want:   "oklch(60.00% 0.100 19.92)",
+		},
+		{
+			name:   "rotate-hue half turn equals complement",
+			tokens: map[string]any{"color.primary": "oklch(60% 0.1 40)"},
+			expr:   "rotate-hue({color.primary}, 0.5turn)",
+			want:   "oklch(60.00% 0.100 220.02)",
+		},
+		{
+			name:   "complement",
+			tokens: map[string]any{"color.primary": "oklch(60% 0.1 40)"},
+			expr:   "complement({color.primary})",
+			want:   "oklch(60.00% 0.100 220.02)",
+		},
+		{
+			name:   "nested color functions",
+			tokens: map[string]any{"color.primary": "#3b82f6"},
+			expr:   "alpha(mix({color.primary}, #ffffff, 100%), 50%)",
+			want:   "#3b82f680",
+		},
+		{
+			name:    "mix weight out of range",
+			tokens:  map[string]any{"color.a": "#fff", "color.b": "#000"},
+			expr:    "mix({color.a}, {color.b}, 150%)",
+			wantErr: true,
+		},
+		{
+			name:    "mix unknown space",
+			tokens:  map[string]any{"color.a": "#fff", "color.b": "#000"},
+			expr:    "mix({color.a}, {color.b}, 50%, hsl)",
+			wantErr: true,
+		},
+		{
+			name:    "alpha with dimension",
+			tokens:  map[string]any{"color.a": "#fff"},
+			expr:    "alpha({color.a}, 2px)",
+			wantErr: true,
+		},
+		{
+			name:    "complement of non-color",
+			tokens:  map[string]any{"size.md": "1rem"},
+			expr:    "complement({size.md})",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			resolver := createTestResolver(tt.tokens)
+			eval := NewExpressionEvaluator(resolver)
+
+			result, err := eval.Evaluate(tt.expr)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Evaluate(%q) expected error, got nil", tt.expr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Evaluate(%q) unexpected error: %v", tt.expr, err)
+			}
+
+			if result != tt.want {
+				t.Errorf("Evaluate(%q) = %q, want %q", tt.expr, result, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpressionEvaluator_Fluid(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		tokens  map[string]any
+		expr    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "rem endpoints",
+			tokens: map[string]any{"font.size.sm": "1rem", "font.size.xl": "1.5rem"},
+			expr:   "fluid({font.size.sm}, {font.size.xl}, 320px, 1440px)",
+			want:   "clamp(1rem, 0.8571rem + 0.7143vw, 1.5rem)",
+		},
+		{
+			name:   "default viewports",
+			tokens: map[string]any{"a": "1rem", "b": "1.5rem"},
+			expr:   "fluid({a}, {b})",
+			want:   "clamp(1rem, 0.8571rem + 0.7143vw, 1.5rem)",
+		},
+		{
+			name:   "px endpoints",
+			tokens: map[string]any{"a": "16px", "b": "24px"},
+			expr:   "fluid({a}, {b}, 320px, 1440px)",
+			want:   "clamp(16px, 13.7143px + 0.7143vw, 24px)",
+		},
+		{
+			name:   "mixed units use the min unit",
+			tokens: map[string]any{"a": "1rem", "b": "24px"},
+			expr:   "fluid({a}, {b}, 20rem, 90rem)",
+			want:   "clamp(1rem, 0.8571rem + 0.7143vw, 1.5rem)",
+		},
+		{
+			name:   "shrinking value",
+			tokens: map[string]any{"a": "2rem", "b": "1rem"},
+			expr:   "fluid({a}, {b}, 320px, 1440px)",
+			want:   "clamp(1rem, 2.2857rem - 1.4286vw, 2rem)",
+		},
+		{
+			name:   "custom root font size",
+			tokens: map[string]any{"a": "1rem", "b": "1.5rem"},
+			expr:   "fluid({a}, {b}, 320px, 1440px, 10px)",
+			want:   "clamp(1rem, 0.8571rem + 0.4464vw, 1.5rem)",
+		},
+		{
+			name:   "equal endpoints collapse",
+			tokens: map[string]any{"a": "1rem"},
+			expr:   "fluid({a}, {a})",
+			want:   "1rem",
+		},
+		{
+			name:   "computed endpoints",
+			tokens: map[string]any{"base": "1rem"},
+			expr:   "fluid({base} * 0.875, {base} * 1.125, 320px, 1440px)",
+			want:   "clamp(0.875rem, 0.8036rem + 0.3571vw, 1.125rem)",
+		},
+		{
+			name:    "unsupported unit",
+			tokens:  map[string]any{"a": "1vw", "b": "2vw"},
+			expr:    "fluid({a}, {b})",
+			wantErr: true,
+		},
+		{
+			name:    "inverted viewport range",
+			tokens:  map[string]any{"a": "1rem", "b": "2rem"},
+			expr:    "fluid({a}, {b}, 1440px, 320px)",
+			wantErr: true,
+		},
+		{
+			name:    "wrong argument count",
+			tokens:  map[string]any{"a": "1rem"},
+			expr:    "fluid({a})",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			resolver := createTestResolver(tt.tokens)
+			eval := NewExpressionEvaluator(resolver)
+
+			result, err := eval.Evaluate(tt.expr)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Evaluate(%q) expected error, got nil", tt.expr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Evaluate(%q) unexpected error: %v", tt.expr, err)
+			}
+
+			if result != tt.want {
+				t.Errorf("Evaluate(%q) = %q, want %q", tt.expr, result, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpressionEvaluator_RootFontSize(t *testing.T) {
+	t.Parallel()
+	dict := NewDictionary()
+	dict.Root = map[string]any{
+		"$rootFontSize": "10px",
+		"size": map[string]any{
+			"base":  map[string]any{"$value": "1.5rem"},
+			"sum":   map[string]any{"$value": "calc({size.base} + 5px)"},
+			"fluid": map[string]any{"$value": "fluid(1rem, 2rem, 320px, 1440px)"},
+		},
+	}
+
+	r, err := NewResolver(dict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolved, err := r.ResolveAll()
+	if err != nil {
+		t.Fatalf("ResolveAll failed: %v", err)
+	}
+
+	if got := resolved["size.sum"]; got != "2rem" {
+		t.Errorf("size.sum = %v, want 2rem", got)
+	}
+	if got := resolved["size.fluid"]; got != "clamp(1rem, 0.7143rem + 0.8929vw, 2rem)" {
+		t.Errorf("size.fluid = %v", got)
+	}
+
+	dict.Root["$rootFontSize"] = "large"
+	if _, err := NewResolver(dict); err == nil {
+		t.Error("expected error for invalid $rootFontSize")
+	}
+}
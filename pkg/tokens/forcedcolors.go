@@ -0,0 +1,224 @@
+// tokenctl/pkg/tokens/forcedcolors.go
+
+package tokens
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// systemColors are the CSS system color keywords, keyed by lower case.
+// In forced colors mode the browser replaces authored colors with these,
+// so they are the only values a forced colors override may use.
+var systemColors = map[string]string{
+	"accentcolor":      "AccentColor",
+	"accentcolortext":  "AccentColorText",
+	"activetext":       "ActiveText",
+	"buttonborder":     "ButtonBorder",
+	"buttonface":       "ButtonFace",
+	"buttontext":       "ButtonText",
+	"canvas":           "Canvas",
+	"canvastext":       "CanvasText",
+	"field":            "Field",
+	"fieldtext":        "FieldText",
+	"graytext":         "GrayText",
+	"highlight":        "Highlight",
+	"highlighttext":    "HighlightText",
+	"linktext":         "LinkText",
+	"mark":             "Mark",
+	"marktext":         "MarkText",
+	"selecteditem":     "SelectedItem",
+	"selecteditemtext": "SelectedItemText",
+	"visitedtext":      "VisitedText",
+}
+
+// ForcedColors returns the dictionary's $forcedColors: token path to the
+// system color it takes in forced colors mode, or nil when it declares
+// none. Keywords are returned in their canonical case. A theme inherits
+// the base's entries and may replace or add to them.
+func (d *Dictionary) ForcedColors() (map[string]string, error) {
+	raw, ok := d.Root["$forcedColors"]
+	if !ok {
+		return nil, nil
+	}
+	entries, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("$forcedColors must be an object mapping token paths to system colors, got %T", raw)
+	}
+
+	paths := make([]string, 0, len(entries))
+	for path := range entries {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	out := make(map[string]string, len(entries))
+	for _, path := range paths {
+		if lookupToken(d.Root, path) == nil {
+			return nil, fmt.Errorf("$forcedColors: %s is not a token", path)
+		}
+		name, _ := entries[path].(string)
+		keyword, ok := systemColors[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("$forcedColors: %s maps to %v, which is not a system color (e.g. CanvasText, ButtonFace, Highlight)", path, entries[path])
+		}
+		out[path] = keyword
+	}
+	return out, nil
+}
+
+// ForcedColorsIssue is an interactive component that paints a background
+// but draws no border or outline. Forced colors mode removes backgrounds,
+// leaving such a control with no visible edge.
+type ForcedColorsIssue struct {
+	Component  string // component path
+	Class      string
+	SourceFile string
+	Line       int
+	Column     int
+}
+
+func (f ForcedColorsIssue) Error() string {
+	if f.SourceFile != "" {
+		loc := Position{File: f.SourceFile, Line: f.Line, Column: f.Column}
+		return fmt.Sprintf("%s [%s]: %s", f.Component, loc, f.message())
+	}
+	return fmt.Sprintf("%s: %s", f.Component, f.message())
+}
+
+func (f ForcedColorsIssue) message() string {
+	return fmt.Sprintf(".%s is interactive and has a background but no border or outline; it has no visible edge in forced colors mode (add a transparent border or a $forcedColors block)", f.Class)
+}
+
+// CheckForcedColors reports interactive components with a background and
+// no border or outline fallback. A component is interactive when it sets
+// cursor: pointer or styles :focus, :focus-visible or :active; :hover
+// alone is often decoration, such as a highlighted table row. A
+// fallback is a border or outline that is not none or zero anywhere in
+// the component, its $forcedColors block included. Issues are sorted by
+// component path.
+func CheckForcedColors(d *Dictionary) ([]ForcedColorsIssue, error) {
+	components, err := d.ExtractComponents()
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []ForcedColorsIssue
+	for name, comp := range components {
+		blocks := componentPropertyBlocks(comp)
+		if !isInteractive(blocks) || !hasBackground(blocks) {
+			continue
+		}
+		if hasEdge(blocks) || hasEdge(propertyBlocks("", comp.ForcedColors)) {
+			continue
+		}
+		pos := d.valuePosition(name)
+		issues = append(issues, ForcedColorsIssue{
+			Component: name, Class: comp.Class,
+			SourceFile: pos.File, Line: pos.Line, Column: pos.Column,
+		})
+	}
+
+	sort.Slice(issues, func(i, j int) bool {
+		return issues[i].Component < issues[j].Component
+	})
+	return issues, nil
+}
+
+// componentPropertyBlocks lists every property map a component emits,
+// with nested state blocks flattened out and each block's selector key
+// recorded under "$selector"
+func componentPropertyBlocks(comp ComponentDefinition) []map[string]any {
+	blocks := propertyBlocks("", comp.Base)
+	for _, group := range []map[string]VariantDef{comp.Variants, comp.Sizes, comp.States} {
+		for _, v := range group {
+			blocks = append(blocks, propertyBlocks("", v.Properties)...)
+			for selector, st := range v.States {
+				blocks = append(blocks, propertyBlocks(selector, st.Properties)...)
+			}
+		}
+	}
+	return blocks
+}
+
+func propertyBlocks(selector string, props map[string]any) []map[string]any {
+	block := map[string]any{"$selector": selector}
+	blocks := []map[string]any{block}
+	for key, val := range props {
+		if nested, ok := val.(map[string]any); ok && isNestedSelector(key) {
+			blocks = append(blocks, propertyBlocks(key, nested)...)
+			continue
+		}
+		block[key] = val
+	}
+	return blocks
+}
+
+// interactiveStates are the pseudo-classes that mark a component as
+// something the user operates
+var interactiveStates = []string{":focus", ":active"}
+
+func isInteractive(blocks []map[string]any) bool {
+	for _, block := range blocks {
+		if cursor, ok := block["cursor"].(string); ok && strings.TrimSpace(cursor) == "pointer" {
+			return true
+		}
+		selector, _ := block["$selector"].(string)
+		for _, state := range interactiveStates {
+			if strings.Contains(selector, state) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasBackground(blocks []map[string]any) bool {
+	for _, block := range blocks {
+		for _, prop := range []string{"background", "background-color"} {
+			if v, ok := block[prop]; ok && !isEmptyPaint(v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// edgeProperties are the border and outline properties that draw an edge.
+// border-color and outline-color alone draw nothing.
+var edgeProperties = map[string]bool{
+	"border": true, "border-width": true, "border-style": true,
+	"border-top": true, "border-right": true, "border-bottom": true, "border-left": true,
+	"border-block": true, "border-inline": true,
+	"border-block-start": true, "border-block-end": true,
+	"border-inline-start": true, "border-inline-end": true,
+	"outline": true, "outline-width": true, "outline-style": true,
+}
+
+func hasEdge(blocks []map[string]any) bool {
+	for _, block := range blocks {
+		for prop, v := range block {
+			if edgeProperties[prop] && !isEmptyPaint(v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isEmptyPaint reports whether a property value draws nothing
+func isEmptyPaint(v any) bool {
+	if m, ok := v.(map[string]any); ok {
+		v = m["$value"]
+	}
+	s, ok := v.(string)
+	if !ok {
+		return v == nil
+	}
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "none", "0", "0px", "transparent":
+		return true
+	}
+	return false
+}
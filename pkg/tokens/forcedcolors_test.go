@@ -0,0 +1,177 @@
+// tokenctl/pkg/tokens/forcedcolors_test.go
+
+package tokens
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDictionary_ForcedColors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		forced  any
+		want    map[string]string
+		wantErr string
+	}{
+		{"none", nil, nil, ""},
+		{"canonical case", map[string]any{"color.primary": "highlight", "color.base-100": "Canvas"},
+			map[string]string{"color.primary": "Highlight", "color.base-100": "Canvas"}, ""},
+		{"not a system color", map[string]any{"color.primary": "#3b82f6"}, nil, "not a system color"},
+		{"not a token", map[string]any{"color.missing": "Canvas"}, nil, "color.missing is not a token"},
+		{"not an object", "Canvas", nil, "must be an object"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			d := NewDictionary()
+			d.Root = map[string]any{
+				"color": map[string]any{
+					"$type":    "color",
+					"primary":  map[string]any{"$value": "#3b82f6"},
+					"base-100": map[string]any{"$value": "#ffffff"},
+				},
+			}
+			if tt.forced != nil {
+				d.Root["$forcedColors"] = tt.forced
+			}
+
+			got, err := d.ForcedColors()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for path, w := range tt.want {
+				if got[path] != w {
+					t.Errorf("%s = %q, want %q", path, got[path], w)
+				}
+			}
+		})
+	}
+}
+
+func TestValidator_ForcedColors(t *testing.T) {
+	t.Parallel()
+	d := NewDictionary()
+	d.Root = map[string]any{
+		"$forcedColors": map[string]any{"color.primary": "Blue"},
+		"color":         map[string]any{"primary": map[string]any{"$value": "#3b82f6", "$type": "color"}},
+	}
+	errs, err := Validate(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 1 || errs[0].Path != "$forcedColors" || !strings.HasPrefix(errs[0].Message, "color.primary maps to Blue") {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+}
+
+func TestCheckForcedColors(t *testing.T) {
+	t.Parallel()
+	component := func(fields map[string]any) map[string]any {
+		node := map[string]any{"$type": "component", "$class": "x"}
+		for k, v := range fields {
+			node[k] = v
+		}
+		return node
+	}
+	tests := []struct {
+		name string
+		node map[string]any
+		want bool
+	}{
+		{"pointer with background", component(map[string]any{
+			"base": map[string]any{"cursor": "pointer", "background-color": "{color.primary}"},
+		}), true},
+		{"focus state in a variant", component(map[string]any{
+			"variants": map[string]any{"primary": map[string]any{
+				"$class": "x-primary", "background": "blue",
+				"&:focus-visible": map[string]any{"color": "white"},
+			}},
+		}), true},
+		{"border none is no fallback", component(map[string]any{
+			"base": map[string]any{"cursor": "pointer", "background-color": "blue", "border": "none"},
+		}), true},
+		{"border", component(map[string]any{
+			"base": map[string]any{"cursor": "pointer", "background-color": "blue", "border": "1px solid transparent"},
+		}), false},
+		{"outline on focus", component(map[string]any{
+			"base": map[string]any{
+				"background-color": "blue",
+				"&:focus-visible":  map[string]any{"outline": "2px solid blue"},
+			},
+		}), false},
+		{"$forcedColors border", component(map[string]any{
+			"base":          map[string]any{"cursor": "pointer", "background-color": "blue"},
+			"$forcedColors": map[string]any{"border": "1px solid ButtonText"},
+		}), false},
+		{"hover only", component(map[string]any{
+			"base": map[string]any{"&:hover": map[string]any{"background-color": "gray"}},
+		}), false},
+		{"no background", component(map[string]any{
+			"base": map[string]any{"cursor": "pointer", "color": "blue"},
+		}), false},
+		{"transparent background", component(map[string]any{
+			"base": map[string]any{"cursor": "pointer", "background": "transparent"},
+		}), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			d := NewDictionary()
+			d.Root = map[string]any{"components": map[string]any{"x": tt.node}}
+
+			issues, err := CheckForcedColors(d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := len(issues) > 0; got != tt.want {
+				t.Errorf("flagged = %v, want %v (%v)", got, tt.want, issues)
+			}
+		})
+	}
+}
+
+func TestForcedColorsIssue_Diagnostic(t *testing.T) {
+	t.Parallel()
+	issue := ForcedColorsIssue{Component: "components.button", Class: "btn", SourceFile: "button.json", Line: 3, Column: 5}
+	if !strings.HasPrefix(issue.Error(), "components.button [button.json:3:5]: .btn is interactive") {
+		t.Errorf("Error() = %q", issue.Error())
+	}
+	if d := issue.Diagnostic(); d.Rule != RuleForcedColors || d.Severity != SeverityWarning || d.Path != "components.button" {
+		t.Errorf("unexpected diagnostic: %+v", d)
+	}
+}
+
+func TestExtractComponents_ForcedColors(t *testing.T) {
+	t.Parallel()
+	d := NewDictionary()
+	d.Root = map[string]any{"button": map[string]any{
+		"$type":  "component",
+		"$class": "btn",
+		"$forcedColors": map[string]any{
+			"border":          "1px solid ButtonText",
+			"&:focus-visible": map[string]any{"outline": "2px solid Highlight"},
+		},
+	}}
+	components, err := d.ExtractComponents()
+	if err != nil {
+		t.Fatal(err)
+	}
+	forced := components["button"].ForcedColors
+	if forced["border"] != "1px solid ButtonText" {
+		t.Errorf("border = %v", forced["border"])
+	}
+	if _, ok := forced["&:focus-visible"].(map[string]any); !ok {
+		t.Errorf("state block not kept: %v", forced)
+	}
+}
@@ -0,0 +1,115 @@
+// tokenctl/pkg/tokens/gamut.go
+
+package tokens
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/dmoose/tokenctl/pkg/colors"
+)
+
+// GamutIssue is a color token whose authored value lies outside the
+// gamut being checked. Browsers clip such a color on screens that cannot
+// show it, shifting its hue and lightness.
+type GamutIssue struct {
+	Path       string
+	Value      string // authored value
+	Gamut      string // colors.GamutSRGB or colors.GamutDisplayP3
+	InP3       bool   // outside sRGB but displayable on Display-P3 screens
+	Theme      string // theme the token was checked in, empty for the base
+	SourceFile string
+	Line       int
+	Column     int
+}
+
+func (g GamutIssue) Error() string {
+	msg := g.message()
+	if g.Theme != "" {
+		msg += " in theme " + g.Theme
+	}
+	if g.SourceFile != "" {
+		loc := Position{File: g.SourceFile, Line: g.Line, Column: g.Column}
+		return fmt.Sprintf("%s [%s]: %s", g.Path, loc, msg)
+	}
+	return fmt.Sprintf("%s: %s", g.Path, msg)
+}
+
+func (g GamutIssue) message() string {
+	msg := fmt.Sprintf("%s is outside %s", g.Value, colors.GamutName(g.Gamut))
+	if g.Gamut == colors.GamutSRGB {
+		if g.InP3 {
+			msg += " (within Display-P3)"
+		} else {
+			msg += " and Display-P3"
+		}
+	}
+	return msg
+}
+
+// CheckGamut reports color tokens whose authored values lie outside
+// gamut. Only literal colors are checked: references inherit their
+// target's issue, and darken(), lighten() and shade() map their results
+// into sRGB. Issues are sorted by path.
+func CheckGamut(d *Dictionary, gamut string) ([]GamutIssue, error) {
+	flat := make(map[string]any)
+	if err := flatten(d.Root, "", flat); err != nil {
+		return nil, err
+	}
+	types := ExtractTypes(d)
+
+	var issues []GamutIssue
+	for path, raw := range flat {
+		if t := types[path]; t != "" && t != "color" {
+			continue
+		}
+		s, ok := raw.(string)
+		if !ok {
+			continue
+		}
+		c, err := colors.Parse(s)
+		if err != nil || colors.InGamut(c, gamut) {
+			continue
+		}
+		pos := d.valuePosition(path)
+		issues = append(issues, GamutIssue{
+			Path: path, Value: s, Gamut: gamut, InP3: colors.InGamut(c, colors.GamutDisplayP3),
+			SourceFile: pos.File, Line: pos.Line, Column: pos.Column,
+		})
+	}
+
+	sort.Slice(issues, func(i, j int) bool {
+		return issues[i].Path < issues[j].Path
+	})
+	return issues, nil
+}
+
+// MapGamut maps every out-of-gamut color in resolved into gamut with
+// colors.MapToGamut and returns a new map. Only tokens typed "color" or
+// untyped are touched. A mapped color keeps its format unless the result
+// is still outside sRGB (mapping into Display-P3), which only oklch() can
+// express.
+func MapGamut(resolved map[string]any, types map[string]string, gamut string) map[string]any {
+	out := make(map[string]any, len(resolved))
+	for path, val := range resolved {
+		out[path] = val
+		if t := types[path]; t != "" && t != "color" {
+			continue
+		}
+		s, ok := val.(string)
+		if !ok {
+			continue
+		}
+		c, err := colors.Parse(s)
+		if err != nil || colors.InGamut(c, gamut) {
+			continue
+		}
+		mapped := colors.MapToGamut(c, gamut)
+		if colors.InGamut(mapped, colors.GamutSRGB) {
+			out[path] = mapped.ToOriginalFormat()
+		} else {
+			out[path] = mapped.ToOKLCH()
+		}
+	}
+	return out
+}
@@ -0,0 +1,105 @@
+// tokenctl/pkg/tokens/gamut_test.go
+
+package tokens
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dmoose/tokenctl/pkg/colors"
+)
+
+func TestCheckGamut(t *testing.T) {
+	t.Parallel()
+	const fixture = `{
+		"color": {
+			"$type": "color",
+			"blue": {"$value": "#3b82f6"},
+			"primary": {"$value": "oklch(49.12% 0.309 275.75)"},
+			"neon": {"$value": "oklch(70% 0.35 150)"},
+			"alias": {"$value": "{color.neon}"},
+			"darker": {"$value": "darken({color.neon}, 10%)"}
+		},
+		"font": {"$type": "fontFamily", "red": {"$value": "oklch(70% 0.35 150)"}}
+	}`
+	tests := []struct {
+		gamut string
+		want  []string
+	}{
+		{colors.GamutSRGB, []string{
+			"color.neon: oklch(70% 0.35 150) is outside sRGB and Display-P3",
+			"color.primary: oklch(49.12% 0.309 275.75) is outside sRGB (within Display-P3)",
+		}},
+		{colors.GamutDisplayP3, []string{
+			"color.neon: oklch(70% 0.35 150) is outside Display-P3",
+		}},
+	}
+	for _, tt := range tests {
+		issues, err := CheckGamut(dictFromJSON(t, fixture), tt.gamut)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, issue := range issues {
+			got = append(got, issue.Error())
+		}
+		if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+			t.Errorf("%s issues:\n%s\nwant:\n%s", tt.gamut, strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+		}
+	}
+}
+
+func TestGamutIssue_Diagnostic(t *testing.T) {
+	t.Parallel()
+	issue := GamutIssue{Path: "color.neon", Value: "oklch(70% 0.35 150)", Gamut: colors.GamutSRGB, Theme: "dark", SourceFile: "colors.json", Line: 3, Column: 14}
+	if want := "color.neon [colors.json:3:14]: oklch(70% 0.35 150) is outside sRGB and Display-P3 in theme dark"; issue.Error() != want {
+		t.Errorf("Error() = %q, want %q", issue.Error(), want)
+	}
+	if d := issue.Diagnostic(); d.Rule != RuleOutOfGamut || d.Severity != SeverityWarning || d.Theme != "dark" {
+		t.Errorf("unexpected diagnostic: %+v", d)
+	}
+}
+
+func TestMapGamut(t *testing.T) {
+	t.Parallel()
+	resolved := map[string]any{
+		"color.blue":    "#3b82f6",
+		"color.primary": "oklch(49.12% 0.309 275.75)",
+		"color.neon":    "oklch(70% 0.35 150)",
+		"color.rgb":     "rgb(300, 0, 0)",
+		"font.red":      "oklch(70% 0.35 150)",
+		"size.md":       "1rem",
+	}
+	types := map[string]string{"color.blue": "color", "color.primary": "color", "color.neon": "color", "color.rgb": "color", "font.red": "fontFamily"}
+
+	out := MapGamut(resolved, types, colors.GamutSRGB)
+	for path, want := range map[string]string{
+		"color.blue": "#3b82f6",
+		"font.red":   "oklch(70% 0.35 150)",
+		"size.md":    "1rem",
+	} {
+		if out[path] != want {
+			t.Errorf("%s = %v, want %s", path, out[path], want)
+		}
+	}
+	// Mapped colors keep their format
+	for path, prefix := range map[string]string{"color.primary": "oklch(", "color.neon": "oklch(", "color.rgb": "rgb("} {
+		s := out[path].(string)
+		c, err := colors.Parse(s)
+		if err != nil || !strings.HasPrefix(s, prefix) || !colors.InGamut(c, colors.GamutSRGB) {
+			t.Errorf("%s = %v, want an in-gamut %s...)", path, s, prefix)
+		}
+	}
+	srgbNeon := out["color.neon"]
+
+	out = MapGamut(resolved, types, colors.GamutDisplayP3)
+	if out["color.primary"] != "oklch(49.12% 0.309 275.75)" {
+		t.Errorf("P3 color changed under display-p3: %v", out["color.primary"])
+	}
+	// Mapping into P3 keeps more chroma than mapping into sRGB
+	_, p3Chroma, _ := colors.MustParse(out["color.neon"].(string)).OkLch()
+	_, srgbChroma, _ := colors.MustParse(srgbNeon.(string)).OkLch()
+	if p3Chroma <= srgbChroma || p3Chroma >= 0.35 {
+		t.Errorf("color.neon = %v under display-p3, %v under srgb", out["color.neon"], srgbNeon)
+	}
+}
@@ -0,0 +1,444 @@
+// tokenctl/pkg/tokens/graph.go
+
+package tokens
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// NodeKind classifies a node in the dependency graph
+type NodeKind string
+
+const (
+	NodeToken     NodeKind = "token"
+	NodeComponent NodeKind = "component"
+	NodeTheme     NodeKind = "theme"
+	NodeMissing   NodeKind = "missing" // referenced but never defined
+)
+
+// EdgeKind describes why one node depends on another
+type EdgeKind string
+
+const (
+	EdgeReference EdgeKind = "reference" // value contains {to}
+	EdgeOverride  EdgeKind = "override"  // theme redefines token to
+	EdgeExtends   EdgeKind = "extends"   // theme $extends theme to
+)
+
+// GraphEdge points from a node to something it depends on
+type GraphEdge struct {
+	From string   `json:"from"`
+	To   string   `json:"to"`
+	Kind EdgeKind `json:"kind"`
+}
+
+// Graph is the dependency graph of a token system. Tokens are keyed by
+// path, components by "component:<class>" and themes by "theme:<name>".
+// An edge A -> B means A depends on B, so Dependents answers "what
+// changes if I edit B".
+type Graph struct {
+	nodes map[string]NodeKind
+	out   map[string][]GraphEdge
+	in    map[string][]GraphEdge
+}
+
+// ComponentNodeID returns the graph node id of a component class
+func ComponentNodeID(class string) string { return "component:" + class }
+
+// ThemeNodeID returns the graph node id of a theme
+func ThemeNodeID(name string) string { return "theme:" + name }
+
+// NewGraph builds the dependency graph of base, its components and the
+// raw (un-inherited) theme overrides. themes may be nil.
+func NewGraph(base *Dictionary, themes map[string]*Dictionary) (*Graph, error) {
+	g := &Graph{
+		nodes: make(map[string]NodeKind),
+		out:   make(map[string][]GraphEdge),
+		in:    make(map[string][]GraphEdge),
+	}
+
+	flat := make(map[string]any)
+	if err := flatten(base.Root, "", flat); err != nil {
+		return nil, err
+	}
+	for path := range flat {
+		g.nodes[path] = NodeToken
+	}
+	for path, val := range flat {
+		for _, ref := range referencesIn(val) {
+			g.addEdge(path, ref, EdgeReference)
+		}
+	}
+
+	components, err := base.ExtractComponents()
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract components: %w", err)
+	}
+	for _, comp := range components {
+		class := comp.Class
+		if class == "" {
+			class = comp.Name
+		}
+		id := ComponentNodeID(class)
+		g.nodes[id] = NodeComponent
+		for _, ref := range componentReferences(comp) {
+			g.addEdge(id, ref, EdgeReference)
+		}
+	}
+
+	for name, theme := range themes {
+		id := ThemeNodeID(name)
+		g.nodes[id] = NodeTheme
+		if parent, ok := theme.Root["$extends"].(string); ok {
+			g.nodes[ThemeNodeID(parent)] = NodeTheme
+			g.addEdge(id, ThemeNodeID(parent), EdgeExtends)
+		}
+		overrides := make(map[string]any)
+		if err := flatten(theme.Root, "", overrides); err != nil {
+			return nil, fmt.Errorf("theme %s: %w", name, err)
+		}
+		for path, val := range overrides {
+			if _, ok := g.nodes[path]; !ok {
+				g.nodes[path] = NodeToken
+			}
+			g.addEdge(id, path, EdgeOverride)
+			for _, ref := range referencesIn(val) {
+				g.addEdge(id, ref, EdgeReference)
+			}
+		}
+	}
+
+	// Anything referenced but never declared is still a node, so broken
+	// references show up in the graph rather than vanishing
+	for from := range g.out {
+		for _, e := range g.out[from] {
+			if _, ok := g.nodes[e.To]; !ok {
+				g.nodes[e.To] = NodeMissing
+			}
+		}
+	}
+
+	for id := range g.out {
+		slices.SortFunc(g.out[id], compareEdges)
+	}
+	for id := range g.in {
+		slices.SortFunc(g.in[id], compareEdges)
+	}
+	return g, nil
+}
+
+// componentReferences collects every reference in a component's
+// properties, variants, sizes, states, container and forced colors
+// overrides
+func componentReferences(comp ComponentDefinition) []string {
+	refs := referencesIn(comp.Base)
+	for _, group := range []map[string]VariantDef{comp.Variants, comp.Sizes, comp.States} {
+		for _, v := range group {
+			refs = append(refs, referencesIn(v.Properties)...)
+			for _, st := range v.States {
+				refs = append(refs, referencesIn(st.Properties)...)
+			}
+		}
+	}
+	for _, props := range comp.ContainerOverrides {
+		refs = append(refs, referencesIn(props)...)
+	}
+	refs = append(refs, referencesIn(comp.ForcedColors)...)
+	return refs
+}
+
+func (g *Graph) addEdge(from, to string, kind EdgeKind) {
+	e := GraphEdge{From: from, To: to, Kind: kind}
+	if slices.Contains(g.out[from], e) {
+		return
+	}
+	g.out[from] = append(g.out[from], e)
+	g.in[to] = append(g.in[to], e)
+}
+
+func compareEdges(a, b GraphEdge) int {
+	if c := strings.Compare(a.From, b.From); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.To, b.To); c != 0 {
+		return c
+	}
+	return strings.Compare(string(a.Kind), string(b.Kind))
+}
+
+// Has reports whether id is a node in the graph
+func (g *Graph) Has(id string) bool {
+	_, ok := g.nodes[id]
+	return ok
+}
+
+// Kind returns the kind of node id
+func (g *Graph) Kind(id string) NodeKind {
+	return g.nodes[id]
+}
+
+// Nodes returns every node id, sorted
+func (g *Graph) Nodes() []string {
+	ids := make([]string, 0, len(g.nodes))
+	for id := range g.nodes {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// Edges returns every edge, sorted by source then target
+func (g *Graph) Edges() []GraphEdge {
+	var edges []GraphEdge
+	for _, id := range g.Nodes() {
+		edges = append(edges, g.out[id]...)
+	}
+	return edges
+}
+
+// Dependencies returns the nodes id directly depends on
+func (g *Graph) Dependencies(id string) []string {
+	return uniqueEnds(g.out[id], func(e GraphEdge) string { return e.To })
+}
+
+// Dependents returns the nodes that directly depend on id
+func (g *Graph) Dependents(id string) []string {
+	return uniqueEnds(g.in[id], func(e GraphEdge) string { return e.From })
+}
+
+func uniqueEnds(edges []GraphEdge, end func(GraphEdge) string) []string {
+	var ids []string
+	for _, e := range edges {
+		ids = append(ids, end(e))
+	}
+	slices.Sort(ids)
+	return slices.Compact(ids)
+}
+
+// TopologicalOrder returns every node with dependencies before their
+// dependents. It fails when the graph has cycles.
+func (g *Graph) TopologicalOrder() ([]string, error) {
+	remaining := make(map[string]int, len(g.nodes))
+	var ready []string
+	for id := range g.nodes {
+		remaining[id] = len(g.Dependencies(id))
+		if remaining[id] == 0 {
+			ready = append(ready, id)
+		}
+	}
+	slices.Sort(ready)
+
+	order := make([]string, 0, len(g.nodes))
+	for len(ready) > 0 {
+		id := ready[0]
+		ready = ready[1:]
+		order = append(order, id)
+
+		var next []string
+		for _, dep := range g.Dependents(id) {
+			remaining[dep]--
+			if remaining[dep] == 0 {
+				next = append(next, dep)
+			}
+		}
+		ready = append(ready, next...)
+		slices.Sort(ready)
+	}
+
+	if len(order) < len(g.nodes) {
+		cycles := g.Cycles()
+		parts := make([]string, len(cycles))
+		for i, c := range cycles {
+			parts[i] = strings.Join(append(c, c[0]), " -> ")
+		}
+		return nil, fmt.Errorf("dependency cycle: %s", strings.Join(parts, "; "))
+	}
+	return order, nil
+}
+
+// Cycles returns each set of mutually dependent nodes (strongly connected
+// components with more than one node, or a node referencing itself),
+// every cycle starting at its smallest id
+func (g *Graph) Cycles() [][]string {
+	index := make(map[string]int)
+	low := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var cycles [][]string
+	next := 0
+
+	var visit func(id string)
+	visit = func(id string) {
+		index[id], low[id] = next, next
+		next++
+		stack = append(stack, id)
+		onStack[id] = true
+
+		for _, dep := range g.Dependencies(id) {
+			if _, seen := index[dep]; !seen {
+				visit(dep)
+				low[id] = min(low[id], low[dep])
+			} else if onStack[dep] {
+				low[id] = min(low[id], index[dep])
+			}
+		}
+
+		if low[id] != index[id] {
+			return
+		}
+		var scc []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			scc = append(scc, top)
+			if top == id {
+				break
+			}
+		}
+		if len(scc) > 1 || slices.Contains(g.Dependencies(id), id) {
+			slices.Sort(scc)
+			cycles = append(cycles, scc)
+		}
+	}
+
+	for _, id := range g.Nodes() {
+		if _, seen := index[id]; !seen {
+			visit(id)
+		}
+	}
+	slices.SortFunc(cycles, func(a, b []string) int { return strings.Compare(a[0], b[0]) })
+	return cycles
+}
+
+// Focus returns the subgraph around id: its dependencies and its
+// dependents, each followed up to depth hops (depth <= 0 means no limit)
+func (g *Graph) Focus(id string, depth int) (*Graph, error) {
+	if !g.Has(id) {
+		return nil, fmt.Errorf("node not found: %s", id)
+	}
+	keep := map[string]bool{id: true}
+	g.walk(id, depth, g.Dependencies, keep)
+	g.walk(id, depth, g.Dependents, keep)
+
+	sub := &Graph{
+		nodes: make(map[string]NodeKind),
+		out:   make(map[string][]GraphEdge),
+		in:    make(map[string][]GraphEdge),
+	}
+	for n := range keep {
+		sub.nodes[n] = g.nodes[n]
+	}
+	for _, e := range g.Edges() {
+		if keep[e.From] && keep[e.To] {
+			sub.out[e.From] = append(sub.out[e.From], e)
+			sub.in[e.To] = append(sub.in[e.To], e)
+		}
+	}
+	return sub, nil
+}
+
+// walk adds every node reachable from id through step within depth hops
+func (g *Graph) walk(id string, depth int, step func(string) []string, keep map[string]bool) {
+	frontier := []string{id}
+	seen := map[string]bool{id: true}
+	for hop := 0; len(frontier) > 0 && (depth <= 0 || hop < depth); hop++ {
+		var next []string
+		for _, n := range frontier {
+			for _, m := range step(n) {
+				if !seen[m] {
+					seen[m] = true
+					keep[m] = true
+					next = append(next, m)
+				}
+			}
+		}
+		frontier = next
+	}
+}
+
+// DOT renders the graph in Graphviz format
+func (g *Graph) DOT() string {
+	var sb strings.Builder
+	sb.WriteString("digraph tokens {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=ellipse];\n")
+	for _, id := range g.Nodes() {
+		switch g.nodes[id] {
+		case NodeComponent:
+			fmt.Fprintf(&sb, "  %q [shape=box];\n", id)
+		case NodeTheme:
+			fmt.Fprintf(&sb, "  %q [shape=hexagon];\n", id)
+		case NodeMissing:
+			fmt.Fprintf(&sb, "  %q [style=dashed, color=red];\n", id)
+		default:
+			fmt.Fprintf(&sb, "  %q;\n", id)
+		}
+	}
+	for _, e := range g.Edges() {
+		if e.Kind == EdgeReference {
+			fmt.Fprintf(&sb, "  %q -> %q;\n", e.From, e.To)
+			continue
+		}
+		fmt.Fprintf(&sb, "  %q -> %q [style=dashed, label=%q];\n", e.From, e.To, e.Kind)
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// Mermaid renders the graph as a Mermaid flowchart. Node ids are
+// numbered since Mermaid ids cannot contain dots; the path is the label.
+func (g *Graph) Mermaid() string {
+	var sb strings.Builder
+	sb.WriteString("flowchart LR\n")
+	ids := make(map[string]string, len(g.nodes))
+	for i, id := range g.Nodes() {
+		ids[id] = fmt.Sprintf("n%d", i)
+		switch g.nodes[id] {
+		case NodeComponent:
+			fmt.Fprintf(&sb, "  %s[\"%s\"]\n", ids[id], id)
+		case NodeTheme:
+			fmt.Fprintf(&sb, "  %s{{\"%s\"}}\n", ids[id], id)
+		case NodeMissing:
+			fmt.Fprintf(&sb, "  %s>\"%s (missing)\"]\n", ids[id], id)
+		default:
+			fmt.Fprintf(&sb, "  %s(\"%s\")\n", ids[id], id)
+		}
+	}
+	for _, e := range g.Edges() {
+		if e.Kind == EdgeReference {
+			fmt.Fprintf(&sb, "  %s --> %s\n", ids[e.From], ids[e.To])
+			continue
+		}
+		fmt.Fprintf(&sb, "  %s -. %s .-> %s\n", ids[e.From], e.Kind, ids[e.To])
+	}
+	return sb.String()
+}
+
+// MarshalJSON writes nodes, edges and cycles in a stable order
+func (g *Graph) MarshalJSON() ([]byte, error) {
+	type node struct {
+		ID   string   `json:"id"`
+		Kind NodeKind `json:"kind"`
+	}
+	nodes := make([]node, 0, len(g.nodes))
+	for _, id := range g.Nodes() {
+		nodes = append(nodes, node{ID: id, Kind: g.nodes[id]})
+	}
+	edges := g.Edges()
+	if edges == nil {
+		edges = []GraphEdge{}
+	}
+	cycles := g.Cycles()
+	if cycles == nil {
+		cycles = [][]string{}
+	}
+	return json.Marshal(struct {
+		Nodes  []node      `json:"nodes"`
+		Edges  []GraphEdge `json:"edges"`
+		Cycles [][]string  `json:"cycles"`
+	}{nodes, edges, cycles})
+}
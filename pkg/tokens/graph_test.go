@@ -0,0 +1,204 @@
+// tokenctl/pkg/tokens/graph_test.go
+
+package tokens
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGraph(t *testing.T) {
+	t.Parallel()
+	base := dictFromJSON(t, `{
+		"color": {
+			"blue": {"$value": "#3b82f6"},
+			"sky": {"$value": "#0ea5e9"},
+			"primary": {"$value": "{color.blue}"},
+			"hover": {"$value": "darken({color.primary}, 10%)"}
+		},
+		"shadow": {
+			"focus": {"$type": "shadow", "$value": {"color": "{color.primary}", "offsetX": "0", "offsetY": "0", "blur": "{size.ring}"}}
+		},
+		"components": {
+			"button": {
+				"$type": "component",
+				"$class": "btn",
+				"base": {"background": "{color.primary}"},
+				"variants": {"ghost": {"$class": "btn-ghost", "&:hover": {"color": "{color.hover}"}}}
+			}
+		}
+	}`)
+	themes := map[string]*Dictionary{
+		"dark":     dictFromJSON(t, `{"color": {"primary": {"$value": "{color.sky}"}}}`),
+		"contrast": dictFromJSON(t, `{"$extends": "dark"}`),
+	}
+	g, err := NewGraph(base, themes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("edges", func(t *testing.T) {
+		t.Parallel()
+		tests := []struct {
+			id         string
+			kind       NodeKind
+			deps       []string
+			dependents []string
+		}{
+			{"color.blue", NodeToken, nil, []string{"color.primary"}},
+			{"color.primary", NodeToken, []string{"color.blue"},
+				[]string{"color.hover", "component:btn", "shadow.focus", "theme:dark"}},
+			{"color.hover", NodeToken, []string{"color.primary"}, []string{"component:btn"}},
+			{"component:btn", NodeComponent, []string{"color.hover", "color.primary"}, nil},
+			{"theme:dark", NodeTheme, []string{"color.primary", "color.sky"}, []string{"theme:contrast"}},
+			{"theme:contrast", NodeTheme, []string{"theme:dark"}, nil},
+			{"size.ring", NodeMissing, nil, []string{"shadow.focus"}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.id, func(t *testing.T) {
+				t.Parallel()
+				if got := g.Kind(tt.id); got != tt.kind {
+					t.Errorf("Kind = %q, want %q", got, tt.kind)
+				}
+				if got := g.Dependencies(tt.id); !reflect.DeepEqual(got, tt.deps) {
+					t.Errorf("Dependencies = %v, want %v", got, tt.deps)
+				}
+				if got := g.Dependents(tt.id); !reflect.DeepEqual(got, tt.dependents) {
+					t.Errorf("Dependents = %v, want %v", got, tt.dependents)
+				}
+			})
+		}
+	})
+
+	t.Run("topological order", func(t *testing.T) {
+		t.Parallel()
+		order, err := g.TopologicalOrder()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(order) != len(g.Nodes()) {
+			t.Fatalf("order has %d nodes, want %d", len(order), len(g.Nodes()))
+		}
+		pos := make(map[string]int, len(order))
+		for i, id := range order {
+			pos[id] = i
+		}
+		for _, e := range g.Edges() {
+			if pos[e.To] > pos[e.From] {
+				t.Errorf("%s ordered before its dependency %s", e.From, e.To)
+			}
+		}
+	})
+
+	t.Run("focus", func(t *testing.T) {
+		t.Parallel()
+		tests := []struct {
+			depth int
+			want  []string
+		}{
+			{1, []string{"color.blue", "color.hover", "color.primary", "component:btn", "shadow.focus", "theme:dark"}},
+			{0, []string{"color.blue", "color.hover", "color.primary", "component:btn", "shadow.focus", "theme:contrast", "theme:dark"}},
+		}
+		for _, tt := range tests {
+			sub, err := g.Focus("color.primary", tt.depth)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := sub.Nodes(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Focus depth %d nodes = %v, want %v", tt.depth, got, tt.want)
+			}
+			for _, e := range sub.Edges() {
+				if !sub.Has(e.From) || !sub.Has(e.To) {
+					t.Errorf("edge %v leaves the subgraph", e)
+				}
+			}
+		}
+
+		if _, err := g.Focus("color.nope", 1); err == nil {
+			t.Error("expected error focusing on unknown node")
+		}
+	})
+}
+
+func TestGraph_Cycles(t *testing.T) {
+	t.Parallel()
+	d := NewDictionary()
+	d.Root = map[string]any{
+		"a":    map[string]any{"$value": "{b}"},
+		"b":    map[string]any{"$value": "calc({c} * 2)"},
+		"c":    map[string]any{"$value": "{a}"},
+		"self": map[string]any{"$value": "{self}"},
+		"ok":   map[string]any{"$value": "{a}"},
+	}
+	g, err := NewGraph(d, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{{"a", "b", "c"}, {"self"}}
+	if got := g.Cycles(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Cycles = %v, want %v", got, want)
+	}
+	if _, err := g.TopologicalOrder(); err == nil || !strings.Contains(err.Error(), "a -> b -> c -> a") {
+		t.Errorf("TopologicalOrder error = %v", err)
+	}
+}
+
+func TestGraph_Render(t *testing.T) {
+	t.Parallel()
+	d := NewDictionary()
+	d.Root = map[string]any{
+		"color": map[string]any{
+			"blue":    map[string]any{"$value": "#3b82f6"},
+			"primary": map[string]any{"$value": "{color.blue}"},
+		},
+	}
+	dark := NewDictionary()
+	dark.Root = map[string]any{"color": map[string]any{"blue": map[string]any{"$value": "#60a5fa"}}}
+	g, err := NewGraph(d, map[string]*Dictionary{"dark": dark})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dot := g.DOT()
+	for _, want := range []string{
+		`"color.primary" -> "color.blue";`,
+		`"theme:dark" [shape=hexagon];`,
+		`"theme:dark" -> "color.blue" [style=dashed, label="override"];`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT missing %q:\n%s", want, dot)
+		}
+	}
+
+	mermaid := g.Mermaid()
+	for _, want := range []string{
+		"flowchart LR",
+		`n0("color.blue")`,
+		"n1 --> n0",
+		"n2 -. override .-> n0",
+	} {
+		if !strings.Contains(mermaid, want) {
+			t.Errorf("Mermaid missing %q:\n%s", want, mermaid)
+		}
+	}
+
+	data, err := json.Marshal(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Nodes  []map[string]string `json:"nodes"`
+		Edges  []GraphEdge         `json:"edges"`
+		Cycles [][]string          `json:"cycles"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Nodes) != 3 || len(decoded.Edges) != 2 || decoded.Cycles == nil {
+		t.Errorf("unexpected JSON: %s", data)
+	}
+}
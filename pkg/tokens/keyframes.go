@@ -13,6 +13,11 @@ import (
 type KeyframeDefinition struct {
 	Name   string                       // Animation name (e.g., "skeleton-pulse")
 	Frames map[string]map[string]string // Frame selector -> properties (e.g., "0%, 100%" -> {"opacity": "1"})
+
+	// ReducedMotion is the $reducedMotion frame set used under
+	// prefers-reduced-motion: reduce. Nil when the animation declares
+	// none; empty for "none", which stops it.
+	ReducedMotion map[string]map[string]string
 }
 
 // ExtractKeyframes scans the dictionary for keyframes definitions
@@ -41,23 +46,16 @@ func ExtractKeyframes(dict *Dictionary) []KeyframeDefinition {
 
 		kf := KeyframeDefinition{
 			Name:   name,
-			Frames: make(map[string]map[string]string),
+			Frames: extractFrames(framesMap),
 		}
 
-		// Process each frame selector (e.g., "0%, 100%", "50%", "from", "to")
-		for selector, propsNode := range framesMap {
-			propsMap, ok := propsNode.(map[string]any)
-			if !ok {
-				continue
-			}
-
-			props := make(map[string]string)
-			for propName, propValue := range propsMap {
-				// Convert value to string
-				props[propName] = fmt.Sprintf("%v", propValue)
+		switch reduced := framesMap["$reducedMotion"].(type) {
+		case map[string]any:
+			kf.ReducedMotion = extractFrames(reduced)
+		case string:
+			if reduced == "none" {
+				kf.ReducedMotion = map[string]map[string]string{}
 			}
-
-			kf.Frames[selector] = props
 		}
 
 		keyframes = append(keyframes, kf)
@@ -71,7 +69,33 @@ func ExtractKeyframes(dict *Dictionary) []KeyframeDefinition {
 	return keyframes
 }
 
-// GenerateKeyframesCSS generates CSS @keyframes blocks from keyframe definitions
+// extractFrames reads frame selectors (e.g., "0%, 100%", "50%", "from",
+// "to") and their properties, skipping $-prefixed keys
+func extractFrames(framesMap map[string]any) map[string]map[string]string {
+	frames := make(map[string]map[string]string)
+	for selector, propsNode := range framesMap {
+		if strings.HasPrefix(selector, "$") {
+			continue
+		}
+		propsMap, ok := propsNode.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		props := make(map[string]string)
+		for propName, propValue := range propsMap {
+			// Convert value to string
+			props[propName] = fmt.Sprintf("%v", propValue)
+		}
+
+		frames[selector] = props
+	}
+	return frames
+}
+
+// GenerateKeyframesCSS generates CSS @keyframes blocks from keyframe
+// definitions. Animations with $reducedMotion are redefined under
+// prefers-reduced-motion: reduce, where the later definition wins.
 func GenerateKeyframesCSS(keyframes []KeyframeDefinition) string {
 	if len(keyframes) == 0 {
 		return ""
@@ -79,42 +103,60 @@ func GenerateKeyframesCSS(keyframes []KeyframeDefinition) string {
 
 	var sb strings.Builder
 
+	var reduced []KeyframeDefinition
 	for _, kf := range keyframes {
-		fmt.Fprintf(&sb, "@keyframes %s {\n", kf.Name)
+		writeKeyframes(&sb, kf.Name, kf.Frames, "")
+		sb.WriteString("\n")
+		if kf.ReducedMotion != nil {
+			reduced = append(reduced, kf)
+		}
+	}
 
-		// Sort frame selectors for deterministic output
-		selectors := make([]string, 0, len(kf.Frames))
-		for selector := range kf.Frames {
-			selectors = append(selectors, selector)
+	if len(reduced) > 0 {
+		fmt.Fprintf(&sb, "%s {\n", reducedMotionQuery)
+		for _, kf := range reduced {
+			writeKeyframes(&sb, kf.Name, kf.ReducedMotion, "  ")
 		}
-		sort.Slice(selectors, func(i, j int) bool {
-			// Sort by percentage value for natural ordering
-			// "from" < "0%" < "50%" < "100%" < "to"
-			return keyframeSelectorOrder(selectors[i]) < keyframeSelectorOrder(selectors[j])
-		})
-
-		for _, selector := range selectors {
-			props := kf.Frames[selector]
-			fmt.Fprintf(&sb, "  %s {\n", selector)
-
-			// Sort properties for deterministic output
-			propNames := make([]string, 0, len(props))
-			for name := range props {
-				propNames = append(propNames, name)
-			}
-			sort.Strings(propNames)
+		sb.WriteString("}\n\n")
+	}
 
-			for _, propName := range propNames {
-				fmt.Fprintf(&sb, "    %s: %s;\n", propName, props[propName])
-			}
+	return sb.String()
+}
+
+// writeKeyframes writes one @keyframes rule at the given indent
+func writeKeyframes(sb *strings.Builder, name string, frames map[string]map[string]string, pad string) {
+	fmt.Fprintf(sb, "%s@keyframes %s {\n", pad, name)
 
-			sb.WriteString("  }\n")
+	// Sort frame selectors for deterministic output
+	selectors := make([]string, 0, len(frames))
+	for selector := range frames {
+		selectors = append(selectors, selector)
+	}
+	sort.Slice(selectors, func(i, j int) bool {
+		// Sort by percentage value for natural ordering
+		// "from" < "0%" < "50%" < "100%" < "to"
+		return keyframeSelectorOrder(selectors[i]) < keyframeSelectorOrder(selectors[j])
+	})
+
+	for _, selector := range selectors {
+		props := frames[selector]
+		fmt.Fprintf(sb, "%s  %s {\n", pad, selector)
+
+		// Sort properties for deterministic output
+		propNames := make([]string, 0, len(props))
+		for name := range props {
+			propNames = append(propNames, name)
 		}
+		sort.Strings(propNames)
 
-		sb.WriteString("}\n\n")
+		for _, propName := range propNames {
+			fmt.Fprintf(sb, "%s    %s: %s;\n", pad, propName, props[propName])
+		}
+
+		fmt.Fprintf(sb, "%s  }\n", pad)
 	}
 
-	return sb.String()
+	fmt.Fprintf(sb, "%s}\n", pad)
 }
 
 // keyframeSelectorOrder returns a sortable value for keyframe selectors
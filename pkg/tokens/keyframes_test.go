@@ -177,3 +177,51 @@ func TestGenerateKeyframesCSS_Empty(t *testing.T) {
 		t.Error("Empty keyframes slice should produce empty string")
 	}
 }
+
+func TestExtractKeyframes_ReducedMotion(t *testing.T) {
+	t.Parallel()
+	dict := &Dictionary{
+		Root: map[string]any{
+			"keyframes": map[string]any{
+				"slide-in": map[string]any{
+					"from":           map[string]any{"transform": "translateX(-100%)"},
+					"to":             map[string]any{"transform": "translateX(0)"},
+					"$reducedMotion": map[string]any{"from": map[string]any{"opacity": "0"}, "to": map[string]any{"opacity": "1"}},
+				},
+				"spin": map[string]any{
+					"to":             map[string]any{"transform": "rotate(360deg)"},
+					"$reducedMotion": "none",
+				},
+				"pulse": map[string]any{
+					"50%": map[string]any{"opacity": "0.5"},
+				},
+			},
+		},
+	}
+
+	byName := make(map[string]KeyframeDefinition)
+	for _, kf := range ExtractKeyframes(dict) {
+		byName[kf.Name] = kf
+	}
+	if _, ok := byName["slide-in"].Frames["$reducedMotion"]; ok {
+		t.Error("$reducedMotion read as a frame selector")
+	}
+	if got := byName["slide-in"].ReducedMotion["to"]["opacity"]; got != "1" {
+		t.Errorf("slide-in reduced to opacity = %q, want 1", got)
+	}
+	if rm := byName["spin"].ReducedMotion; rm == nil || len(rm) != 0 {
+		t.Errorf("spin ReducedMotion = %v, want empty for none", rm)
+	}
+	if byName["pulse"].ReducedMotion != nil {
+		t.Error("pulse has no $reducedMotion but got one")
+	}
+
+	css := GenerateKeyframesCSS(ExtractKeyframes(dict))
+	want := "@media (prefers-reduced-motion: reduce) {\n" +
+		"  @keyframes slide-in {\n    from {\n      opacity: 0;\n    }\n    to {\n      opacity: 1;\n    }\n  }\n" +
+		"  @keyframes spin {\n  }\n" +
+		"}\n"
+	if !strings.HasSuffix(css, want+"\n") {
+		t.Errorf("CSS missing reduced motion block %q:\n%s", want, css)
+	}
+}
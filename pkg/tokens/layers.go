@@ -47,14 +47,17 @@ type LayerViolation struct {
 	RefPath    string
 	RefLayer   Layer
 	SourceFile string
+	Line       int // 1-based line of the token's $value, when known
+	Column     int
 }
 
 func (v LayerViolation) Error() string {
 	msg := fmt.Sprintf("%s [%s] cannot reference %s [%s]: layer violation",
 		v.TokenPath, v.TokenLayer, v.RefPath, v.RefLayer)
 	if v.SourceFile != "" {
+		loc := Position{File: v.SourceFile, Line: v.Line, Column: v.Column}
 		msg = fmt.Sprintf("%s [%s] [%s] cannot reference %s [%s]: layer violation",
-			v.TokenPath, v.TokenLayer, v.SourceFile, v.RefPath, v.RefLayer)
+			v.TokenPath, v.TokenLayer, loc, v.RefPath, v.RefLayer)
 	}
 	return msg
 }
@@ -125,20 +128,13 @@ func (v *LayerValidator) ValidateReferences(d *Dictionary) []LayerViolation {
 // walkAndValidate recursively validates layer references
 func (v *LayerValidator) walkAndValidate(d *Dictionary, node map[string]any, currentPath string, violations *[]LayerViolation) {
 	if IsToken(node) {
-		value, ok := node["$value"].(string)
-		if !ok {
-			return
-		}
-
 		fromLayer := v.tokenLayers[currentPath]
 		if fromLayer == "" {
 			return // No layer assigned, skip validation
 		}
 
-		// Find all references in the value
-		refs := refRegex.FindAllStringSubmatch(value, -1)
-		for _, ref := range refs {
-			refPath := ref[1]
+		// Find all references in the value, including composite sub-fields
+		for _, refPath := range referencesIn(node["$value"]) {
 			toLayer := v.tokenLayers[refPath]
 
 			if toLayer == "" {
@@ -146,16 +142,16 @@ func (v *LayerValidator) walkAndValidate(d *Dictionary, node map[string]any, cur
 			}
 
 			if !CanReference(fromLayer, toLayer) {
-				violation := LayerViolation{
+				pos := d.valuePosition(currentPath)
+				*violations = append(*violations, LayerViolation{
 					TokenPath:  currentPath,
 					TokenLayer: fromLayer,
 					RefPath:    refPath,
 					RefLayer:   toLayer,
-				}
-				if sourceFile, ok := d.SourceFiles[currentPath]; ok {
-					violation.SourceFile = sourceFile
-				}
-				*violations = append(*violations, violation)
+					SourceFile: pos.File,
+					Line:       pos.Line,
+					Column:     pos.Column,
+				})
 			}
 		}
 		return
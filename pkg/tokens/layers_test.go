@@ -534,6 +534,30 @@ func TestValidateReferences(t *testing.T) {
 			},
 			wantViolations: 0,
 		},
+		{
+			name: "Invalid Reference Inside Composite Value",
+			root: map[string]any{
+				"brand": map[string]any{
+					"$layer": "brand",
+					"shadow": map[string]any{
+						"$value": map[string]any{
+							"color":   "{semantic.shadow}",
+							"offsetX": "0",
+							"offsetY": "1px",
+						},
+					},
+				},
+				"semantic": map[string]any{
+					"$layer": "semantic",
+					"shadow": map[string]any{
+						"$value": "#0000001a",
+					},
+				},
+			},
+			wantViolations: 1,
+			wantTokenPath:  "brand.shadow",
+			wantRefPath:    "semantic.shadow",
+		},
 		{
 			name: "Reference To Token Without Layer Skipped",
 			root: map[string]any{
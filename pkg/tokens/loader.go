@@ -1,6 +1,7 @@
 package tokens
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -21,6 +22,8 @@ func ParseJSON(r io.Reader) (*Dictionary, error) {
 	return &Dictionary{
 		Root:        root,
 		SourceFiles: make(map[string]string),
+		Overrides:   make(map[string][]string),
+		Positions:   make(map[string]Position),
 	}, nil
 }
 
@@ -124,25 +127,38 @@ func (l *Loader) LoadThemes(rootPath string) (map[string]*Dictionary, error) {
 		if strings.HasSuffix(filename, ".tokens.json") {
 			ext = ".tokens.json"
 		}
-		themeName := strings.TrimSuffix(filename, ext)
+		value := strings.TrimSuffix(filename, ext)
+
+		// A subdirectory other than mode/ is a theme axis:
+		// themes/brand/acme.json is "brand/acme"
+		themeName := value
+		if rel, err := filepath.Rel(themesPath, filepath.Dir(filePath)); err == nil && rel != "." {
+			axis := strings.Split(filepath.ToSlash(rel), "/")[0]
+			if axis != ThemeAxisMode {
+				themeName = axis + "/" + value
+			}
+		}
 
 		dict, err := l.loadFile(filePath)
 		if err != nil {
 			return fmt.Errorf("failed to load theme %s: %w", filePath, err)
 		}
 
-		// Track source file for all tokens in this theme
-		l.annotateSourceFile(dict, "", filePath)
-
 		// Unwrap root key if it matches theme name
 		// Example: dark.json contains { "dark": { ... } }
-		if root, ok := dict.Root[themeName]; ok {
+		if root, ok := dict.Root[value]; ok {
 			if rootMap, ok := root.(map[string]any); ok {
-				// Replace dict root with the unwrapped content
+				// Replace dict root with the unwrapped content, and
+				// re-key source records to the unwrapped paths
 				dict.Root = rootMap
+				dict.SourceFiles = make(map[string]string)
+				dict.Positions = rebasePositions(dict.Positions, value)
 			}
 		}
 
+		// Track source file for all tokens in this theme
+		l.annotateSourceFile(dict, "", filePath)
+
 		themes[themeName] = dict
 		return nil
 	})
@@ -170,15 +186,17 @@ func (l *Loader) isTokenFile(path string) bool {
 }
 
 func (l *Loader) loadFile(path string) (*Dictionary, error) {
-	f, err := os.Open(path)
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
-	defer func() { _ = f.Close() }()
-	dict, err := ParseJSON(f)
+	dict, err := ParseJSON(bytes.NewReader(data))
 	if err != nil {
 		return nil, err
 	}
+	if dict.Positions, err = scanPositions(data, path); err != nil {
+		return nil, err
+	}
 
 	// Audit before merging: after the merge, paths from different files
 	// are indistinguishable and component nodes carry no source record.
@@ -229,8 +247,7 @@ func (d *Dictionary) Merge(other *Dictionary) error {
 	if err := deepMerge(d.Root, other.Root, ""); err != nil {
 		return err
 	}
-	// Merge source file mappings
-	maps.Copy(d.SourceFiles, other.SourceFiles)
+	d.mergeSourceFiles(other)
 	return nil
 }
 
@@ -239,16 +256,44 @@ func (d *Dictionary) MergeWithPath(other *Dictionary, warnConflicts bool) error
 	if err := deepMergeWithWarnings(d.Root, other.Root, "", warnConflicts); err != nil {
 		return err
 	}
-	// Merge source file mappings, preferring the new source file for conflicts
-	maps.Copy(d.SourceFiles, other.SourceFiles)
+	d.mergeSourceFiles(other)
 	return nil
 }
 
+// mergeSourceFiles takes other's source files and key positions,
+// preferring them on conflict, and records each replaced file in
+// Overrides so a token's earlier definitions can still be reported
+func (d *Dictionary) mergeSourceFiles(other *Dictionary) {
+	if d.SourceFiles == nil {
+		d.SourceFiles = make(map[string]string)
+	}
+	if d.Overrides == nil {
+		d.Overrides = make(map[string][]string)
+	}
+	if d.Positions == nil {
+		d.Positions = make(map[string]Position)
+	}
+	maps.Copy(d.Positions, other.Positions)
+	for path, file := range other.SourceFiles {
+		if prev, ok := d.SourceFiles[path]; ok && prev != file {
+			d.Overrides[path] = append(d.Overrides[path], prev)
+		}
+		if earlier := other.Overrides[path]; len(earlier) > 0 {
+			d.Overrides[path] = append(d.Overrides[path], earlier...)
+		}
+		d.SourceFiles[path] = file
+	}
+}
+
 // DetectDefaultTheme scans theme dictionaries for "$default": true metadata.
 // Returns the name of the first theme that declares itself as default,
-// or "light" as a fallback if no theme declares $default.
+// or "light" as a fallback if no theme declares $default. Only mode themes
+// can be the default; themes on other axes are skipped.
 func DetectDefaultTheme(themes map[string]*Dictionary) string {
 	for name, dict := range themes {
+		if strings.Contains(name, "/") {
+			continue
+		}
 		if def, ok := dict.Root["$default"]; ok {
 			if b, ok := def.(bool); ok && b {
 				return name
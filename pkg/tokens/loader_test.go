@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"io"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -453,3 +454,40 @@ func TestDeepCopy(t *testing.T) {
 		t.Error("Deep copy modified original array")
 	}
 }
+
+func TestDictionary_MergeRecordsOverrides(t *testing.T) {
+	t.Parallel()
+	newDict := func(file, value string) *Dictionary {
+		d := NewDictionary()
+		d.Root = map[string]any{"spacing": map[string]any{"base": map[string]any{"$value": value}}}
+		d.SourceFiles["spacing.base"] = file
+		return d
+	}
+
+	// Two directories, each already merged from two files
+	first := newDict("a/brand.json", "1rem")
+	if err := first.MergeWithPath(newDict("a/semantic.json", "1.25rem"), false); err != nil {
+		t.Fatal(err)
+	}
+	second := newDict("b/brand.json", "1.5rem")
+	if err := second.MergeWithPath(newDict("b/site.json", "2rem"), false); err != nil {
+		t.Fatal(err)
+	}
+	if err := first.MergeWithPath(second, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := first.SourceFiles["spacing.base"]; got != "b/site.json" {
+		t.Errorf("SourceFiles = %q, want b/site.json", got)
+	}
+	want := []string{"a/brand.json", "a/semantic.json", "b/brand.json"}
+	if got := first.Overrides["spacing.base"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("Overrides = %v, want %v", got, want)
+	}
+
+	copied := first.DeepCopy()
+	copied.Overrides["spacing.base"][0] = "changed"
+	if first.Overrides["spacing.base"][0] != "a/brand.json" {
+		t.Error("DeepCopy shares Overrides slices")
+	}
+}
@@ -0,0 +1,189 @@
+// tokenctl/pkg/tokens/position.go
+
+package tokens
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Position locates a key in a source file. Line and Column are 1-based;
+// Column counts characters, not bytes.
+type Position struct {
+	File   string
+	Line   int
+	Column int
+}
+
+// IsValid reports whether the position carries a line number
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+// String formats the position as file:line:column, the form editors and
+// CI annotators link to. Parts that are unknown are left out.
+func (p Position) String() string {
+	switch {
+	case p.IsValid() && p.File != "":
+		return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+	case p.IsValid():
+		return fmt.Sprintf("%d:%d", p.Line, p.Column)
+	default:
+		return p.File
+	}
+}
+
+// Position returns where path was defined. path may name a token, a
+// group, a component sub-block or a $-key ("size.field.$min").
+func (d *Dictionary) Position(path string) (Position, bool) {
+	pos, ok := d.Positions[path]
+	return pos, ok
+}
+
+// valuePosition returns the position of a token's $value, falling back
+// to the token key and then to the bare source file
+func (d *Dictionary) valuePosition(path string) Position {
+	if d == nil {
+		return Position{}
+	}
+	if pos, ok := d.Positions[path+".$value"]; ok {
+		return pos
+	}
+	if pos, ok := d.Positions[path]; ok {
+		return pos
+	}
+	return Position{File: d.SourceFiles[path]}
+}
+
+// scanPositions records the position of every object key in a JSON
+// document, keyed by dot path. Array elements are not keyed; objects
+// inside arrays are skipped since token paths never index into them.
+func scanPositions(data []byte, file string) (map[string]Position, error) {
+	lines := lineOffsets(data)
+	positions := make(map[string]Position)
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	var walk func(path string, keyed bool) error
+	walk = func(path string, keyed bool) error {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		delim, ok := tok.(json.Delim)
+		if !ok {
+			return nil
+		}
+		switch delim {
+		case '{':
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				key, _ := keyTok.(string)
+				child := key
+				if path != "" {
+					child = path + "." + key
+				}
+				if keyed {
+					offset := keyStart(data, int(dec.InputOffset()))
+					positions[child] = offsetPosition(data, lines, offset, file)
+				}
+				if err := walk(child, keyed); err != nil {
+					return err
+				}
+			}
+		case '[':
+			for dec.More() {
+				if err := walk(path, false); err != nil {
+					return err
+				}
+			}
+		}
+		_, err = dec.Token() // closing delimiter
+		return err
+	}
+
+	if err := walk("", true); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return positions, nil
+}
+
+// keyStart walks back from the offset just past a key's closing quote to
+// its opening quote
+func keyStart(data []byte, end int) int {
+	i := end - 2
+	for i >= 0 {
+		if data[i] == '"' && !escaped(data, i) {
+			return i
+		}
+		i--
+	}
+	return 0
+}
+
+// escaped reports whether data[i] is preceded by an odd number of
+// backslashes
+func escaped(data []byte, i int) bool {
+	n := 0
+	for j := i - 1; j >= 0 && data[j] == '\\'; j-- {
+		n++
+	}
+	return n%2 == 1
+}
+
+// lineOffsets returns the byte offset at which each line starts
+func lineOffsets(data []byte) []int {
+	offsets := []int{0}
+	for i, b := range data {
+		if b == '\n' {
+			offsets = append(offsets, i+1)
+		}
+	}
+	return offsets
+}
+
+func offsetPosition(data []byte, lines []int, offset int, file string) Position {
+	line := sort.Search(len(lines), func(i int) bool { return lines[i] > offset }) - 1
+	column := utf8.RuneCount(data[lines[line]:offset]) + 1
+	return Position{File: file, Line: line + 1, Column: column}
+}
+
+// rebasePositions strips prefix from every path, used when a theme file
+// wraps its tokens in a key named after the theme
+func rebasePositions(positions map[string]Position, prefix string) map[string]Position {
+	out := make(map[string]Position, len(positions))
+	for path, pos := range positions {
+		if rest, ok := strings.CutPrefix(path, prefix+"."); ok {
+			out[rest] = pos
+		}
+	}
+	return out
+}
+
+// ResolveError is a resolution failure attributed to the token whose own
+// value could not be resolved, which may be several references away from
+// the token being resolved
+type ResolveError struct {
+	Path     string
+	Position Position
+	Err      error
+}
+
+func (e *ResolveError) Error() string {
+	if e.Position.IsValid() {
+		return fmt.Sprintf("%s: %v", e.Position, e.Err)
+	}
+	return e.Err.Error()
+}
+
+func (e *ResolveError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,201 @@
+// tokenctl/pkg/tokens/position_test.go
+
+package tokens
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestScanPositions(t *testing.T) {
+	t.Parallel()
+	data := []byte(`{
+  "color": {
+    "primary": { "$value": "#3b82f6" },
+    "odd\"key": { "$value": "#000" }
+  },
+  "shadow": {
+    "layers": { "$value": [{ "blur": "4px" }] },
+    "ring":   { "$value": "0 0 0 2px" }
+  },
+  "naïve": { "é": { "$value": 1 } }
+}`)
+	positions, err := scanPositions(data, "tokens.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want Position
+	}{
+		{"color", Position{"tokens.json", 2, 3}},
+		{"color.primary", Position{"tokens.json", 3, 5}},
+		{"color.primary.$value", Position{"tokens.json", 3, 18}},
+		{`color.odd"key`, Position{"tokens.json", 4, 5}},
+		{"shadow.layers.$value", Position{"tokens.json", 7, 17}},
+		{"shadow.ring.$value", Position{"tokens.json", 8, 17}},
+		{"naïve.é", Position{"tokens.json", 10, 14}},
+	}
+	for _, tt := range tests {
+		if got := positions[tt.path]; got != tt.want {
+			t.Errorf("%s at %v, want %v", tt.path, got, tt.want)
+		}
+	}
+	if _, ok := positions["shadow.layers.$value.blur"]; ok {
+		t.Error("keys inside arrays should not be recorded")
+	}
+}
+
+func TestRebasePositions(t *testing.T) {
+	t.Parallel()
+	positions := map[string]Position{
+		"dark":               {Line: 1},
+		"dark.color":         {Line: 2},
+		"dark.color.primary": {Line: 3},
+		"darker.color":       {Line: 9},
+	}
+	want := map[string]Position{
+		"color":         {Line: 2},
+		"color.primary": {Line: 3},
+	}
+	if got := rebasePositions(positions, "dark"); !reflect.DeepEqual(got, want) {
+		t.Errorf("rebasePositions = %v, want %v", got, want)
+	}
+}
+
+func TestPosition_String(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		pos  Position
+		want string
+	}{
+		{Position{"a.json", 4, 7}, "a.json:4:7"},
+		{Position{"", 4, 7}, "4:7"},
+		{Position{"a.json", 0, 0}, "a.json"},
+		{Position{}, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.pos.String(); got != tt.want {
+			t.Errorf("%#v.String() = %q, want %q", tt.pos, got, tt.want)
+		}
+	}
+}
+
+func TestLoader_RecordsPositions(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "themes"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	base := `{
+  "color": {
+    "primary": { "$value": "#3b82f6" },
+    "accent": { "$value": "{color.missing}" }
+  },
+  "spacing": {
+    "md": { "$value": "1rem", "$tpye": "dimension" }
+  }
+}`
+	theme := `{
+  "dark": {
+    "color": {
+      "primary": { "$value": "#60a5fa" }
+    }
+  }
+}`
+	basePath := filepath.Join(tmpDir, "tokens.json")
+	themePath := filepath.Join(tmpDir, "themes", "dark.json")
+	if err := os.WriteFile(basePath, []byte(base), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(themePath, []byte(theme), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loader := NewLoader()
+	dict, err := loader.LoadBase(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pos, ok := dict.Position("color.primary"); !ok || pos != (Position{basePath, 3, 5}) {
+		t.Errorf("color.primary at %v, want %s:3:5", pos, basePath)
+	}
+
+	themes, err := loader.LoadThemes(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pos, _ := themes["dark"].Position("color.primary.$value"); pos != (Position{themePath, 4, 20}) {
+		t.Errorf("dark color.primary.$value at %v, want %s:4:20", pos, themePath)
+	}
+
+	t.Run("validation errors", func(t *testing.T) {
+		t.Parallel()
+		errs, err := Validate(dict)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var found bool
+		for _, e := range errs {
+			if e.Path == "color.accent" {
+				found = true
+				if e.Line != 4 || e.Column != 17 {
+					t.Errorf("color.accent error at %d:%d, want 4:17", e.Line, e.Column)
+				}
+				if !strings.Contains(e.Error(), basePath+":4:17") {
+					t.Errorf("error %q lacks position", e.Error())
+				}
+			}
+		}
+		if !found {
+			t.Errorf("no error for color.accent in %v", errs)
+		}
+	})
+
+	t.Run("unknown keys", func(t *testing.T) {
+		t.Parallel()
+		findings := AuditUnknownKeys(dict)
+		if len(findings) != 1 {
+			t.Fatalf("findings = %v", findings)
+		}
+		if f := findings[0]; f.Line != 7 || f.Column != 31 {
+			t.Errorf("finding at %d:%d, want 7:31", f.Line, f.Column)
+		}
+	})
+}
+
+func TestResolveError_LocatesFailingToken(t *testing.T) {
+	t.Parallel()
+	d := NewDictionary()
+	d.Root = map[string]any{
+		"a": map[string]any{"$value": "{b}"},
+		"b": map[string]any{"$value": "calc({c} * 2)"},
+		"c": map[string]any{"$value": "{missing}"},
+	}
+	d.Positions = map[string]Position{
+		"a.$value": {"t.json", 1, 1},
+		"b.$value": {"t.json", 2, 1},
+		"c.$value": {"t.json", 3, 1},
+	}
+	r, err := NewResolver(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = r.ResolveAll()
+	var rerr *ResolveError
+	if !errors.As(err, &rerr) {
+		t.Fatalf("expected *ResolveError, got %v", err)
+	}
+	if rerr.Path != "c" || rerr.Position.Line != 3 {
+		t.Errorf("error attributed to %s at %v, want c at line 3", rerr.Path, rerr.Position)
+	}
+	if !strings.Contains(err.Error(), "t.json:3:1") {
+		t.Errorf("error %q lacks position", err)
+	}
+}
@@ -0,0 +1,238 @@
+// tokenctl/pkg/tokens/reducedmotion.go
+
+package tokens
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// reducedMotionQuery is the condition $reducedMotion overrides apply under
+const reducedMotionQuery = "@media (prefers-reduced-motion: reduce)"
+
+// ReducedMotionToken is a duration token with a $reducedMotion override
+type ReducedMotionToken struct {
+	Path  string
+	Value string // override, references written as var()
+}
+
+// ExtractReducedMotionTokens finds every token declaring $reducedMotion,
+// sorted by path. An override on a token that is not a duration is
+// reported by Validate and skipped here.
+func ExtractReducedMotionTokens(d *Dictionary) []ReducedMotionToken {
+	var results []ReducedMotionToken
+	types := ExtractTypes(d)
+	flat := make(map[string]any)
+	if err := flatten(d.Root, "", flat); err != nil {
+		return nil
+	}
+	for path := range flat {
+		token := lookupToken(d.Root, path)
+		value, ok := token["$reducedMotion"].(string)
+		if !ok || types[path] != "duration" {
+			continue
+		}
+		results = append(results, ReducedMotionToken{Path: path, Value: refsToVars(value)})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Path < results[j].Path
+	})
+	return results
+}
+
+// refsToVars writes each {ref} in s as var(--ref)
+func refsToVars(s string) string {
+	return refRegex.ReplaceAllStringFunc(s, func(match string) string {
+		return fmt.Sprintf("var(--%s)", strings.ReplaceAll(match[1:len(match)-1], ".", "-"))
+	})
+}
+
+// GenerateReducedMotionCSS creates the media block that swaps duration
+// tokens for their $reducedMotion values. Like responsive overrides it is
+// written outside any layer, so it wins over the token and theme layers.
+func GenerateReducedMotionCSS(reduced []ReducedMotionToken) string {
+	if len(reduced) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s {\n", reducedMotionQuery)
+	sb.WriteString("  :root {\n")
+	for _, rt := range reduced {
+		fmt.Fprintf(&sb, "    --%s: %s;\n", strings.ReplaceAll(rt.Path, ".", "-"), rt.Value)
+	}
+	sb.WriteString("  }\n")
+	sb.WriteString("}\n\n")
+	return sb.String()
+}
+
+// validateReducedMotion checks a $reducedMotion declaration. On a token
+// it must be a duration or a reference, and the token must be a duration;
+// on a keyframe definition it is an alternate frame set or "none".
+func validateReducedMotion(dict *Dictionary, path string, node map[string]any, tokenType string) []ValidationError {
+	raw, ok := node["$reducedMotion"]
+	if !ok {
+		return nil
+	}
+
+	fail := func(msg string) []ValidationError {
+		return []ValidationError{newValidationError(dict, path, RuleInvalidValue, "invalid $reducedMotion: "+msg)}
+	}
+
+	if IsToken(node) {
+		if tokenType != "duration" {
+			return fail("only duration tokens can declare a reduced-motion value")
+		}
+		s, ok := raw.(string)
+		if !ok || (!refRegex.MatchString(s) && !durationPattern.MatchString(strings.TrimSpace(s))) {
+			return fail(fmt.Sprintf("expected a duration such as 0ms or a reference, got %v", raw))
+		}
+		return nil
+	}
+
+	if parentPath(path) != "keyframes" {
+		return fail("only duration tokens and keyframe definitions can declare it")
+	}
+	if raw == "none" {
+		return nil
+	}
+	frames, ok := raw.(map[string]any)
+	if !ok {
+		return fail(fmt.Sprintf(`expected a frame set or "none", got %v`, raw))
+	}
+	for selector, props := range frames {
+		if _, ok := props.(map[string]any); !ok {
+			return fail(fmt.Sprintf("frame %q must be an object of properties", selector))
+		}
+	}
+	return nil
+}
+
+// durationPattern matches a CSS time such as 150ms, 0.2s or 0
+var durationPattern = regexp.MustCompile(`^(0|\d*\.?\d+(ms|s))$`)
+
+// ReducedMotionIssue is a component that animates or transitions without
+// referencing any token or keyframe that declares $reducedMotion, so its
+// motion plays in full for users who asked for less
+type ReducedMotionIssue struct {
+	Component  string // component path
+	Class      string
+	Properties []string // motion properties found, sorted
+	SourceFile string
+	Line       int
+	Column     int
+}
+
+func (r ReducedMotionIssue) Error() string {
+	if r.SourceFile != "" {
+		loc := Position{File: r.SourceFile, Line: r.Line, Column: r.Column}
+		return fmt.Sprintf("%s [%s]: %s", r.Component, loc, r.message())
+	}
+	return fmt.Sprintf("%s: %s", r.Component, r.message())
+}
+
+func (r ReducedMotionIssue) message() string {
+	return fmt.Sprintf(".%s sets %s without referencing a token or keyframe with $reducedMotion; its motion ignores prefers-reduced-motion", r.Class, strings.Join(r.Properties, ", "))
+}
+
+// motionProperties are the component properties that put something in
+// motion
+var motionProperties = map[string]bool{
+	"animation":           true,
+	"animation-name":      true,
+	"animation-duration":  true,
+	"transition":          true,
+	"transition-duration": true,
+}
+
+// CheckReducedMotion reports components whose animation or transition
+// properties reference no reduced-motion-aware token or keyframe. A
+// property counts as aware when it references a duration token with
+// $reducedMotion, as {path} or var(--path), or names a keyframe
+// definition with $reducedMotion. Properties set to none are ignored.
+// Issues are sorted by component path.
+func CheckReducedMotion(d *Dictionary) ([]ReducedMotionIssue, error) {
+	components, err := d.ExtractComponents()
+	if err != nil {
+		return nil, err
+	}
+
+	aware := make(map[string]bool)
+	for _, rt := range ExtractReducedMotionTokens(d) {
+		aware[rt.Path] = true
+	}
+	calmKeyframes := make(map[string]bool)
+	for _, kf := range ExtractKeyframes(d) {
+		if kf.ReducedMotion != nil {
+			calmKeyframes[kf.Name] = true
+		}
+	}
+
+	var issues []ReducedMotionIssue
+	for name, comp := range components {
+		found := make(map[string]bool)
+		for _, block := range componentPropertyBlocks(comp) {
+			for prop, v := range block {
+				if !motionProperties[prop] {
+					continue
+				}
+				if m, ok := v.(map[string]any); ok {
+					v = m["$value"]
+				}
+				s, _ := v.(string)
+				if s == "" || strings.TrimSpace(s) == "none" || motionAware(s, aware, calmKeyframes) {
+					continue
+				}
+				found[prop] = true
+			}
+		}
+		if len(found) == 0 {
+			continue
+		}
+
+		props := make([]string, 0, len(found))
+		for prop := range found {
+			props = append(props, prop)
+		}
+		sort.Strings(props)
+		pos := d.valuePosition(name)
+		issues = append(issues, ReducedMotionIssue{
+			Component: name, Class: comp.Class, Properties: props,
+			SourceFile: pos.File, Line: pos.Line, Column: pos.Column,
+		})
+	}
+
+	sort.Slice(issues, func(i, j int) bool {
+		return issues[i].Component < issues[j].Component
+	})
+	return issues, nil
+}
+
+// cssVarPattern matches the custom property a var() reads
+var cssVarPattern = regexp.MustCompile(`var\(\s*--([\w-]+)`)
+
+// motionAware reports whether a motion value references an aware token
+// or names an aware keyframe
+func motionAware(value string, aware, keyframes map[string]bool) bool {
+	for _, ref := range referencesIn(value) {
+		if aware[ref] {
+			return true
+		}
+	}
+	for _, match := range cssVarPattern.FindAllStringSubmatch(value, -1) {
+		for path := range aware {
+			if strings.ReplaceAll(path, ".", "-") == match[1] {
+				return true
+			}
+		}
+	}
+	for _, word := range strings.FieldsFunc(value, func(r rune) bool { return r == ' ' || r == ',' }) {
+		if keyframes[word] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+// tokenctl/pkg/tokens/reducedmotion_test.go
+
+package tokens
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExtractReducedMotionTokens(t *testing.T) {
+	t.Parallel()
+	got := ExtractReducedMotionTokens(dictFromJSON(t, `{"duration": {
+		"$type": "duration",
+		"instant": {"$value": "0ms"},
+		"fast": {"$value": "150ms", "$reducedMotion": "0ms"},
+		"slow": {"$value": "400ms", "$reducedMotion": "{duration.instant}"},
+		"plain": {"$value": "250ms"}
+	}}`))
+	want := []ReducedMotionToken{
+		{Path: "duration.fast", Value: "0ms"},
+		{Path: "duration.slow", Value: "var(--duration-instant)"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	css := GenerateReducedMotionCSS(got)
+	wantCSS := "@media (prefers-reduced-motion: reduce) {\n  :root {\n    --duration-fast: 0ms;\n    --duration-slow: var(--duration-instant);\n  }\n}\n\n"
+	if css != wantCSS {
+		t.Errorf("GenerateReducedMotionCSS:\n%s\nwant:\n%s", css, wantCSS)
+	}
+	if GenerateReducedMotionCSS(nil) != "" {
+		t.Error("expected no CSS without reduced motion tokens")
+	}
+}
+
+func TestValidator_ReducedMotion(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		root    map[string]any
+		wantErr string
+	}{
+		{"duration", map[string]any{"d": map[string]any{"$type": "duration", "$value": "150ms", "$reducedMotion": "0ms"}}, ""},
+		{"reference", map[string]any{
+			"zero": map[string]any{"$type": "duration", "$value": "0s"},
+			"d":    map[string]any{"$type": "duration", "$value": "150ms", "$reducedMotion": "{zero}"},
+		}, ""},
+		{"not a duration value", map[string]any{"d": map[string]any{"$type": "duration", "$value": "150ms", "$reducedMotion": "fast"}}, "expected a duration"},
+		{"not a duration token", map[string]any{"d": map[string]any{"$type": "dimension", "$value": "4px", "$reducedMotion": "0px"}}, "only duration tokens"},
+		{"keyframes frame set", map[string]any{"keyframes": map[string]any{"fade": map[string]any{
+			"to":             map[string]any{"opacity": "1"},
+			"$reducedMotion": map[string]any{"to": map[string]any{"opacity": "1"}},
+		}}}, ""},
+		{"keyframes bad frame", map[string]any{"keyframes": map[string]any{"fade": map[string]any{
+			"$reducedMotion": map[string]any{"to": "1"},
+		}}}, "must be an object"},
+		{"group", map[string]any{"motion": map[string]any{"$reducedMotion": "0ms"}}, "only duration tokens and keyframe definitions"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			d := NewDictionary()
+			d.Root = tt.root
+			errs, err := Validate(d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var msgs []string
+			for _, e := range errs {
+				if strings.Contains(e.Message, "$reducedMotion") {
+					msgs = append(msgs, e.Message)
+				}
+			}
+			got := strings.Join(msgs, "\n")
+			if tt.wantErr == "" && got != "" {
+				t.Errorf("unexpected errors: %s", got)
+			}
+			if tt.wantErr != "" && !strings.Contains(got, tt.wantErr) {
+				t.Errorf("errors %q, want one containing %q", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckReducedMotion(t *testing.T) {
+	t.Parallel()
+	const fixture = `{
+		"duration": {
+			"$type": "duration",
+			"fast": {"$value": "150ms", "$reducedMotion": "0ms"},
+			"slow": {"$value": "400ms", "$reducedMotion": "0ms"},
+			"plain": {"$value": "250ms"}
+		},
+		"keyframes": {
+			"fade": {"from": {"opacity": "0"}, "to": {"opacity": "1"}, "$reducedMotion": "none"},
+			"spin": {"to": {"transform": "rotate(360deg)"}}
+		}
+	}`
+	tests := []struct {
+		name string
+		base map[string]any
+		want string
+	}{
+		{"aware reference", map[string]any{"transition": "opacity {duration.fast} ease"}, ""},
+		{"aware var()", map[string]any{"transition": "opacity var(--duration-slow, 400ms)"}, ""},
+		{"aware keyframe", map[string]any{"animation": "fade 1s ease-out"}, ""},
+		{"none", map[string]any{"animation": "none"}, ""},
+		{"token without override", map[string]any{"transition": "opacity {duration.plain}"}, "transition"},
+		{"keyframe without override", map[string]any{"animation": "spin 1s linear infinite"}, "animation"},
+		{"literal in a state", map[string]any{
+			"transition": "opacity {duration.fast}",
+			"&:hover":    map[string]any{"animation-duration": "2s"},
+		}, "animation-duration"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			d := dictFromJSON(t, fixture)
+			d.Root["spinner"] = map[string]any{"$type": "component", "$class": "spinner", "base": tt.base}
+
+			issues, err := CheckReducedMotion(d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got string
+			if len(issues) > 0 {
+				got = strings.Join(issues[0].Properties, ", ")
+			}
+			if got != tt.want {
+				t.Errorf("flagged %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReducedMotionIssue_Diagnostic(t *testing.T) {
+	t.Parallel()
+	issue := ReducedMotionIssue{Component: "spinner", Class: "spinner", Properties: []string{"animation"}, SourceFile: "spinner.json", Line: 2, Column: 3}
+	if !strings.HasPrefix(issue.Error(), "spinner [spinner.json:2:3]: .spinner sets animation without") {
+		t.Errorf("Error() = %q", issue.Error())
+	}
+	if d := issue.Diagnostic(); d.Rule != RuleReducedMotion || d.Severity != SeverityWarning {
+		t.Errorf("unexpected diagnostic: %+v", d)
+	}
+}
@@ -0,0 +1,75 @@
+// tokenctl/pkg/tokens/references.go
+
+package tokens
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Reference output modes for CSS generation
+const (
+	ReferencesInline   = "inline"   // every token carries its resolved value
+	ReferencesPreserve = "preserve" // aliases emit var(--target)
+)
+
+// PreserveReferences returns a copy of resolved in which references are
+// kept as CSS variable chains. A pure alias ("{color.primary}") becomes
+// var(--color-primary), and plain text such as "1px solid {color.border}"
+// interpolates var() in place of each reference. Expressions (calc(),
+// mix(), ...) and composite objects stay inlined since their result is
+// computed at build time.
+//
+// Diffing two preserved maps is alias-aware: when a theme only changes
+// color.primary, its aliases compare equal and are not re-emitted.
+func PreserveReferences(d *Dictionary, resolved map[string]any) (map[string]any, error) {
+	raw := make(map[string]any)
+	if err := flatten(d.Root, "", raw); err != nil {
+		return nil, err
+	}
+	types := ExtractTypes(d)
+
+	out := make(map[string]any, len(resolved))
+	for path, val := range resolved {
+		out[path] = val
+
+		s, ok := raw[path].(string)
+		if !ok || !strings.Contains(s, "{") || IsExpression(s) {
+			continue
+		}
+		preserved, ok := preserveString(s, resolved, types)
+		if ok {
+			out[path] = preserved
+		}
+	}
+	return out, nil
+}
+
+// preserveString replaces each {ref} in s with var(--ref). It reports
+// false when a target has no custom property of its own, in which case
+// the caller keeps the inlined value.
+func preserveString(s string, resolved map[string]any, types map[string]string) (string, bool) {
+	ok := true
+	out := refRegex.ReplaceAllStringFunc(s, func(match string) string {
+		target := match[1 : len(match)-1]
+		if !hasCSSValue(target, resolved, types) {
+			ok = false
+			return match
+		}
+		return fmt.Sprintf("var(--%s)", strings.ReplaceAll(target, ".", "-"))
+	})
+	return out, ok
+}
+
+// hasCSSValue reports whether the token at path is emitted as a custom
+// property: it must exist, and object values must be a known composite
+func hasCSSValue(path string, resolved map[string]any, types map[string]string) bool {
+	val, ok := resolved[path]
+	if !ok {
+		return false
+	}
+	if obj, isMap := val.(map[string]any); isMap {
+		_, ok = FormatCompositeCSS(types[path], obj)
+	}
+	return ok
+}
@@ -0,0 +1,106 @@
+// tokenctl/pkg/tokens/references_test.go
+
+package tokens
+
+import "testing"
+
+func TestPreserveReferences(t *testing.T) {
+	t.Parallel()
+	dict := NewDictionary()
+	dict.Root = map[string]any{
+		"color": map[string]any{
+			"blue":    map[string]any{"$value": "#3b82f6"},
+			"primary": map[string]any{"$value": "{color.blue}"},
+			"hover":   map[string]any{"$value": "darken({color.primary}, 10%)"},
+		},
+		"button": map[string]any{
+			"bg":     map[string]any{"$value": "{color.primary}"},
+			"border": map[string]any{"$value": "1px solid {color.primary}"},
+		},
+		"meta": map[string]any{
+			"$type": "object",
+			"info":  map[string]any{"$value": map[string]any{"owner": "design"}},
+		},
+		"alias": map[string]any{
+			"info": map[string]any{"$value": "{meta.info}"},
+		},
+	}
+
+	r, err := NewResolver(dict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolved, err := r.ResolveAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := PreserveReferences(dict, resolved)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]any{
+		"color.blue":    "#3b82f6",
+		"color.primary": "var(--color-blue)",
+		"color.hover":   resolved["color.hover"],
+		"button.bg":     "var(--color-primary)",
+		"button.border": "1px solid var(--color-primary)",
+	}
+	for path, w := range want {
+		if got[path] != w {
+			t.Errorf("%s = %v, want %v", path, got[path], w)
+		}
+	}
+
+	// An alias to a value with no custom property stays inlined
+	if _, ok := got["alias.info"].(map[string]any); !ok {
+		t.Errorf("alias.info = %v, want the inlined object", got["alias.info"])
+	}
+	if resolved["button.bg"] != "#3b82f6" {
+		t.Error("PreserveReferences mutated its input")
+	}
+}
+
+func TestPreserveReferences_ThemeDiff(t *testing.T) {
+	t.Parallel()
+	base := NewDictionary()
+	base.Root = map[string]any{
+		"color": map[string]any{
+			"primary": map[string]any{"$value": "#3b82f6"},
+			"muted":   map[string]any{"$value": "mix({color.primary}, #ffffff, 50%)"},
+		},
+		"button": map[string]any{
+			"bg": map[string]any{"$value": "{color.primary}"},
+		},
+	}
+	theme := base.DeepCopy()
+	theme.Root["color"].(map[string]any)["primary"] = map[string]any{"$value": "#ef4444"}
+
+	preserved := func(d *Dictionary) map[string]any {
+		r, err := NewResolver(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resolved, err := r.ResolveAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		out, err := PreserveReferences(d, resolved)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+
+	diff := Diff(preserved(theme), preserved(base))
+	if _, ok := diff["color.primary"]; !ok {
+		t.Error("changed target missing from diff")
+	}
+	if _, ok := diff["color.muted"]; !ok {
+		t.Error("computed value depending on the target missing from diff")
+	}
+	if _, ok := diff["button.bg"]; ok {
+		t.Errorf("alias re-emitted in diff: %v", diff["button.bg"])
+	}
+}
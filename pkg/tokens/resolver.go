@@ -1,6 +1,7 @@
 package tokens
 
 import (
+	"errors"
 	"fmt"
 	"regexp"
 	"slices"
@@ -9,12 +10,21 @@ import (
 
 // Resolver handles the resolution of token references ({path.to.token})
 type Resolver struct {
-	flatTokens map[string]any
-	cache      map[string]any
-	stack      []string // Cycle detection stack
-	exprEval   *ExpressionEvaluator
+	flatTokens   map[string]any
+	cache        map[string]any
+	stack        []string // Cycle detection stack
+	exprEval     *ExpressionEvaluator
+	rootFontSize float64           // px per rem; zero means DefaultRootFontSize
+	source       *Dictionary       // for locating errors
+	types        map[string]string // for writing composite values into strings
 }
 
+// Resolution failures, for callers that classify errors with errors.Is
+var (
+	ErrReferenceNotFound = errors.New("reference not found")
+	ErrCircularReference = errors.New("circular dependency detected")
+)
+
 // refRegex matches {path.to.token}
 var refRegex = regexp.MustCompile(`\{([^}]+)\}`)
 
@@ -24,22 +34,39 @@ func NewResolver(d *Dictionary) (*Resolver, error) {
 	if err := flatten(d.Root, "", flat); err != nil {
 		return nil, err
 	}
+	rootFontSize, err := d.RootFontSize()
+	if err != nil {
+		return nil, err
+	}
 	r := &Resolver{
-		flatTokens: flat,
-		cache:      make(map[string]any),
-		stack:      []string{},
+		flatTokens:   flat,
+		cache:        make(map[string]any),
+		stack:        []string{},
+		rootFontSize: rootFontSize,
+		source:       d,
+		types:        ExtractTypes(d),
 	}
 	// Create expression evaluator with reference to this resolver
 	r.exprEval = NewExpressionEvaluator(r)
 	return r, nil
 }
 
+// RootFontSize returns the px size of 1rem used for unit conversion
+func (r *Resolver) RootFontSize() float64 {
+	if r.rootFontSize <= 0 {
+		return DefaultRootFontSize
+	}
+	return r.rootFontSize
+}
+
 // ResolveAll resolves all tokens in the dictionary
 func (r *Resolver) ResolveAll() (map[string]any, error) {
 	resolved := make(map[string]any)
-	for path, val := range r.flatTokens {
+	for path := range r.flatTokens {
 		r.stack = []string{} // Reset stack for each root token
-		res, err := r.ResolveValue(path, val)
+		// Go through resolveReference so tokens already resolved as a
+		// dependency of an earlier token come from the cache
+		res, err := r.resolveReference(path)
 		if err != nil {
 			return nil, fmt.Errorf("failed to resolve %s: %w", path, err)
 		}
@@ -50,8 +77,15 @@ func (r *Resolver) ResolveAll() (map[string]any, error) {
 
 // ResolveValue resolves a value that might contain references or expressions
 func (r *Resolver) ResolveValue(path string, value any) (any, error) {
-	// If it's not a string, it can't contain a reference (in this spec version)
-	// Unless it's a composite value which we don't fully support resolving *inside* yet
+	// Composite values (shadow, typography, ...) carry references in
+	// their sub-fields rather than in the value itself
+	switch v := value.(type) {
+	case map[string]any:
+		return r.resolveCompositeObject(path, v)
+	case []any:
+		return r.resolveCompositeList(path, v)
+	}
+
 	valStr, ok := value.(string)
 	if !ok {
 		return value, nil
@@ -69,7 +103,7 @@ func (r *Resolver) ResolveValue(path string, value any) (any, error) {
 
 	// Cycle detection
 	if slices.Contains(r.stack, path) {
-		return nil, fmt.Errorf("circular dependency detected: %s -> %s", strings.Join(r.stack, " -> "), path)
+		return nil, fmt.Errorf("%w: %s -> %s", ErrCircularReference, strings.Join(r.stack, " -> "), path)
 	}
 	r.stack = append(r.stack, path)
 	defer func() {
@@ -85,7 +119,7 @@ func (r *Resolver) ResolveValue(path string, value any) (any, error) {
 	// 2. String interpolation: "1px solid {color.brand}" -> returns string with replaced value
 
 	// Check exact match first (preserves type)
-	if strings.HasPrefix(valStr, "{") && strings.HasSuffix(valStr, "}") && strings.Count(valStr, "{") == 1 {
+	if isWholeReference(valStr) {
 		refPath := valStr[1 : len(valStr)-1]
 		return r.resolveReference(refPath)
 	}
@@ -102,18 +136,115 @@ func (r *Resolver) ResolveValue(path string, value any) (any, error) {
 			return nil, err
 		}
 
-		// Convert resolved value to string for interpolation
-		resolvedStr = strings.Replace(resolvedStr, fullMatch, fmt.Sprintf("%v", resolvedVal), 1)
+		text, err := r.interpolate(refPath, resolvedVal)
+		if err != nil {
+			return nil, err
+		}
+		resolvedStr = strings.Replace(resolvedStr, fullMatch, text, 1)
 	}
 
 	return resolvedStr, nil
 }
 
+// interpolate returns the text a resolved reference contributes to a
+// string value: a composite as its CSS, a list of names joined as a font
+// family list, anything else as it is
+func (r *Resolver) interpolate(refPath string, val any) (string, error) {
+	switch v := val.(type) {
+	case map[string]any, []any:
+		if css, ok := FormatCompositeCSS(r.types[refPath], v); ok {
+			return css, nil
+		}
+		if list, ok := v.([]any); ok && !slices.ContainsFunc(list, isComposite) {
+			return formatFontFamily(list), nil
+		}
+		return "", fmt.Errorf("{%s} is a composite value with no CSS form to write into a string", refPath)
+	}
+	return fmt.Sprintf("%v", val), nil
+}
+
+// isComposite reports whether v is an object or list value
+func isComposite(v any) bool {
+	switch v.(type) {
+	case map[string]any, []any:
+		return true
+	}
+	return false
+}
+
+// resolveCompositeObject resolves each sub-field of a composite value
+// ({"color": "{color.shadow}", "offsetX": "{size.sm}", ...}) and returns
+// a new map, leaving the dictionary untouched.
+func (r *Resolver) resolveCompositeObject(path string, obj map[string]any) (any, error) {
+	out := make(map[string]any, len(obj))
+	for key, sub := range obj {
+		res, err := r.ResolveValue(path, sub)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		out[key] = res
+	}
+	return out, nil
+}
+
+// resolveCompositeList resolves each entry of an array value: shadow
+// layers, gradient stops, fontFamily lists. An entry that is a bare
+// reference to another list token is spliced in place, so
+// ["{font.brand}", "sans-serif"] yields a flat family list.
+func (r *Resolver) resolveCompositeList(path string, list []any) (any, error) {
+	out := make([]any, 0, len(list))
+	for i, sub := range list {
+		res, err := r.ResolveValue(path, sub)
+		if err != nil {
+			return nil, fmt.Errorf("[%d]: %w", i, err)
+		}
+		if nested, ok := res.([]any); ok && isWholeReference(sub) {
+			out = append(out, nested...)
+			continue
+		}
+		out = append(out, res)
+	}
+	return out, nil
+}
+
+// isWholeReference reports whether v is a string consisting of exactly
+// one {reference}
+func isWholeReference(v any) bool {
+	s, ok := v.(string)
+	return ok && strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") && strings.Count(s, "{") == 1
+}
+
+// referencesIn returns every reference path found in a token value,
+// descending into composite objects and arrays
+func referencesIn(value any) []string {
+	var refs []string
+	switch v := value.(type) {
+	case string:
+		for _, match := range refRegex.FindAllStringSubmatch(v, -1) {
+			refs = append(refs, match[1])
+		}
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			refs = append(refs, referencesIn(v[k])...)
+		}
+	case []any:
+		for _, item := range v {
+			refs = append(refs, referencesIn(item)...)
+		}
+	}
+	return refs
+}
+
 // resolveExpression evaluates an expression value
 func (r *Resolver) resolveExpression(path string, expr string) (any, error) {
 	// Cycle detection
 	if slices.Contains(r.stack, path) {
-		return nil, fmt.Errorf("circular dependency detected: %s -> %s", strings.Join(r.stack, " -> "), path)
+		return nil, fmt.Errorf("%w: %s -> %s", ErrCircularReference, strings.Join(r.stack, " -> "), path)
 	}
 	r.stack = append(r.stack, path)
 	defer func() {
@@ -139,13 +270,18 @@ func (r *Resolver) resolveReference(path string) (any, error) {
 	// Lookup token
 	val, ok := r.flatTokens[path]
 	if !ok {
-		return nil, fmt.Errorf("reference not found: %s", path)
+		return nil, fmt.Errorf("%w: %s", ErrReferenceNotFound, path)
 	}
 
-	// Recursively resolve
+	// Recursively resolve. The first token whose own value fails claims
+	// the error, so its position survives wrapping by the referrers.
 	resolved, err := r.ResolveValue(path, val)
 	if err != nil {
-		return nil, err
+		var located *ResolveError
+		if errors.As(err, &located) {
+			return nil, err
+		}
+		return nil, &ResolveError{Path: path, Position: r.source.valuePosition(path), Err: err}
 	}
 
 	// Cache result
@@ -1,6 +1,7 @@
 package tokens
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -98,3 +99,159 @@ func TestResolveValue(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveValue_Composite(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		tokens    map[string]any
+		input     any
+		expected  any
+		expectErr bool
+	}{
+		{
+			name: "Object Sub-Fields",
+			tokens: map[string]any{
+				"color.primary": "#3b82f6",
+				"size.sm":       "2px",
+			},
+			input:    map[string]any{"color": "{color.primary}", "width": "{size.sm}", "style": "solid"},
+			expected: map[string]any{"color": "#3b82f6", "width": "2px", "style": "solid"},
+		},
+		{
+			name: "Nested Object In Array",
+			tokens: map[string]any{
+				"color.shadow": "#000",
+			},
+			input: []any{
+				map[string]any{"color": "{color.shadow}", "offsetX": "0", "offsetY": "1px"},
+			},
+			expected: []any{
+				map[string]any{"color": "#000", "offsetX": "0", "offsetY": "1px"},
+			},
+		},
+		{
+			name: "List Reference Is Spliced",
+			tokens: map[string]any{
+				"font.brand": []any{"Inter", "Helvetica"},
+			},
+			input:    []any{"{font.brand}", "sans-serif"},
+			expected: []any{"Inter", "Helvetica", "sans-serif"},
+		},
+		{
+			name: "Composite Reference Preserves Shape",
+			tokens: map[string]any{
+				"border.base": map[string]any{"color": "{color.line}", "width": "1px", "style": "solid"},
+				"color.line":  "#ccc",
+			},
+			input:    "{border.base}",
+			expected: map[string]any{"color": "#ccc", "width": "1px", "style": "solid"},
+		},
+		{
+			name: "Interpolated Composite Is Written As CSS",
+			tokens: map[string]any{
+				"shadow.md": map[string]any{"offsetX": "0", "offsetY": "4px", "blur": "6px", "spread": "0", "color": "#0000001a"},
+			},
+			input:    "0 0 0 1px red, {shadow.md}",
+			expected: "0 0 0 1px red, 0 4px 6px 0 #0000001a",
+		},
+		{
+			name: "Interpolated Font List Is Joined",
+			tokens: map[string]any{
+				"font.brand": []any{"Inter", "Helvetica Neue"},
+			},
+			input:    "{font.brand}, sans-serif",
+			expected: `Inter, "Helvetica Neue", sans-serif`,
+		},
+		{
+			name: "Interpolated Composite Without CSS Form",
+			tokens: map[string]any{
+				"meta.info": map[string]any{"owner": "design"},
+			},
+			input:     "x {meta.info}",
+			expectErr: true,
+		},
+		{
+			name: "Cycle Through Composite",
+			tokens: map[string]any{
+				"shadow.a": map[string]any{"color": "{shadow.b}"},
+				"shadow.b": map[string]any{"color": "{shadow.a}"},
+			},
+			input:     "{shadow.a}",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r := &Resolver{
+				flatTokens: tt.tokens,
+				cache:      make(map[string]any),
+				stack:      []string{},
+			}
+
+			val, err := r.ResolveValue("root", tt.input)
+
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("Expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+				return
+			}
+
+			if !reflect.DeepEqual(val, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, val)
+			}
+		})
+	}
+}
+
+func TestResolveAll_Composite(t *testing.T) {
+	t.Parallel()
+	dict := NewDictionary()
+	dict.Root = map[string]any{
+		"font": map[string]any{
+			"brand": map[string]any{"$value": []any{"Inter"}},
+		},
+		"typography": map[string]any{
+			"body": map[string]any{
+				"$type": "typography",
+				"$value": map[string]any{
+					"fontFamily": []any{"{font.brand}", "sans-serif"},
+					"fontSize":   "1rem",
+				},
+			},
+		},
+		"border": map[string]any{
+			"$type": "border",
+			"base":  map[string]any{"$value": map[string]any{"color": "#ccc", "width": "1px", "style": "solid"}},
+		},
+		"outline": map[string]any{
+			"focus": map[string]any{"$value": "{border.base}, 0 0 0 2px blue"},
+		},
+	}
+
+	r, err := NewResolver(dict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolved, err := r.ResolveAll()
+	if err != nil {
+		t.Fatalf("ResolveAll failed: %v", err)
+	}
+
+	want := map[string]any{"fontFamily": []any{"Inter", "sans-serif"}, "fontSize": "1rem"}
+	if !reflect.DeepEqual(resolved["typography.body"], want) {
+		t.Errorf("typography.body = %v, want %v", resolved["typography.body"], want)
+	}
+	// Interpolated as CSS for its $type
+	if got := resolved["outline.focus"]; got != "1px solid #ccc, 0 0 0 2px blue" {
+		t.Errorf("outline.focus = %v, want the border written as CSS", got)
+	}
+}
@@ -26,6 +26,20 @@ import (
 //	size.field-md: {size.field}  (1.0 = no change, just reference)
 //	size.field-lg: calc({size.field} * 1.2)
 //	size.field-xl: calc({size.field} * 1.4)
+//
+// A factor may also be a [min, max] pair, which makes the step fluid: it
+// grows from base*min at the smallest viewport to base*max at the largest.
+// An optional "$fluid" entry sets the viewport range for every pair:
+//
+//	"$scale": {
+//	  "sm": [0.8, 0.875],
+//	  "xl": [1.25, 1.563],
+//	  "$fluid": { "minViewport": "320px", "maxViewport": "1440px" }
+//	}
+//
+// Expands to:
+//
+//	size.field-sm: fluid({size.field} * 0.8, {size.field} * 0.875, 320px, 1440px)
 func ExpandScales(d *Dictionary) error {
 	return expandScalesRecursive(d, d.Root, "")
 }
@@ -82,10 +96,14 @@ func expandScaleToken(d *Dictionary, parent map[string]any, baseKey, basePath st
 	baseType, _ := baseToken["$type"].(string)
 	baseDesc, _ := baseToken["$description"].(string)
 
+	viewports, err := parseFluidViewports(basePath, scale["$fluid"])
+	if err != nil {
+		return err
+	}
+
 	for scaleName, factorVal := range scale {
-		factor, ok := toFloat64(factorVal)
-		if !ok {
-			return fmt.Errorf("%s.$scale.%s: factor must be a number", basePath, scaleName)
+		if strings.HasPrefix(scaleName, "$") {
+			continue
 		}
 
 		// Create the new token name
@@ -94,12 +112,24 @@ func expandScaleToken(d *Dictionary, parent map[string]any, baseKey, basePath st
 
 		// Create the scaled token
 		var newValue string
-		if factor == 1.0 {
-			// For factor 1.0, just reference the base token
-			newValue = "{" + basePath + "}"
+		if pair, ok := factorVal.([]any); ok {
+			minFactor, maxFactor, err := fluidFactors(pair)
+			if err != nil {
+				return fmt.Errorf("%s.$scale.%s: %w", basePath, scaleName, err)
+			}
+			newValue = fmt.Sprintf("fluid({%s} * %g, {%s} * %g%s)", basePath, minFactor, basePath, maxFactor, viewports)
 		} else {
-			// Use calc() expression for other factors
-			newValue = fmt.Sprintf("calc({%s} * %g)", basePath, factor)
+			factor, ok := toFloat64(factorVal)
+			if !ok {
+				return fmt.Errorf("%s.$scale.%s: factor must be a number or a [min, max] pair", basePath, scaleName)
+			}
+			if factor == 1.0 {
+				// For factor 1.0, just reference the base token
+				newValue = "{" + basePath + "}"
+			} else {
+				// Use calc() expression for other factors
+				newValue = fmt.Sprintf("calc({%s} * %g)", basePath, factor)
+			}
 		}
 
 		newToken := map[string]any{
@@ -125,6 +155,10 @@ func expandScaleToken(d *Dictionary, parent map[string]any, baseKey, basePath st
 		if sourceFile, ok := d.SourceFiles[basePath]; ok {
 			d.SourceFiles[newPath] = sourceFile
 		}
+		// A generated step points at the $scale entry that produced it
+		if pos, ok := d.Positions[basePath+".$scale."+scaleName]; ok {
+			d.Positions[newPath] = pos
+		}
 	}
 
 	// Remove $scale from the base token (it's been processed)
@@ -133,6 +167,42 @@ func expandScaleToken(d *Dictionary, parent map[string]any, baseKey, basePath st
 	return nil
 }
 
+// parseFluidViewports reads the optional $fluid entry of a $scale and
+// returns the viewport arguments to append to fluid() calls
+func parseFluidViewports(basePath string, raw any) (string, error) {
+	if raw == nil {
+		return "", nil
+	}
+	cfg, ok := raw.(map[string]any)
+	if !ok {
+		return "", fmt.Errorf("%s.$scale.$fluid must be an object", basePath)
+	}
+	minVw, _ := cfg["minViewport"].(string)
+	maxVw, _ := cfg["maxViewport"].(string)
+	if minVw == "" || maxVw == "" {
+		return "", fmt.Errorf("%s.$scale.$fluid requires minViewport and maxViewport", basePath)
+	}
+	for _, v := range []string{minVw, maxVw} {
+		if _, err := ParseDimension(v); err != nil {
+			return "", fmt.Errorf("%s.$scale.$fluid: %w", basePath, err)
+		}
+	}
+	return fmt.Sprintf(", %s, %s", minVw, maxVw), nil
+}
+
+// fluidFactors reads a [min, max] factor pair
+func fluidFactors(pair []any) (float64, float64, error) {
+	if len(pair) != 2 {
+		return 0, 0, fmt.Errorf("fluid factor must be a [min, max] pair")
+	}
+	minFactor, ok1 := toFloat64(pair[0])
+	maxFactor, ok2 := toFloat64(pair[1])
+	if !ok1 || !ok2 {
+		return 0, 0, fmt.Errorf("fluid factors must be numbers")
+	}
+	return minFactor, maxFactor, nil
+}
+
 // toFloat64 converts various numeric types to float64
 func toFloat64(v any) (float64, bool) {
 	switch n := v.(type) {
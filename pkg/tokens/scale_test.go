@@ -439,3 +439,79 @@ func getTokenValue(root map[string]any, path string) string {
 
 	return ""
 }
+
+func TestExpandScales_Fluid(t *testing.T) {
+	t.Parallel()
+	dict := &Dictionary{
+		Root: map[string]any{
+			"font": map[string]any{
+				"size": map[string]any{
+					"$value": "1rem",
+					"$scale": map[string]any{
+						"sm": 0.875,
+						"lg": []any{1.125, 1.25},
+						"$fluid": map[string]any{
+							"minViewport": "320px",
+							"maxViewport": "1440px",
+						},
+					},
+				},
+			},
+		},
+		SourceFiles: make(map[string]string),
+	}
+
+	if err := ExpandScales(dict); err != nil {
+		t.Fatalf("ExpandScales() error: %v", err)
+	}
+
+	if got := getTokenValue(dict.Root, "font.size-lg"); got != "fluid({font.size} * 1.125, {font.size} * 1.25, 320px, 1440px)" {
+		t.Errorf("font.size-lg = %q", got)
+	}
+	if tokenExists(dict.Root, "font.size-$fluid") {
+		t.Error("$fluid must not become a scale step")
+	}
+
+	r, err := NewResolver(dict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolved, err := r.ResolveAll()
+	if err != nil {
+		t.Fatalf("ResolveAll() error: %v", err)
+	}
+	if got := resolved["font.size-lg"]; got != "clamp(1.125rem, 1.0893rem + 0.1786vw, 1.25rem)" {
+		t.Errorf("resolved font.size-lg = %v", got)
+	}
+	if got := resolved["font.size-sm"]; got != "0.875rem" {
+		t.Errorf("resolved font.size-sm = %v", got)
+	}
+}
+
+func TestExpandScales_FluidErrors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		scale map[string]any
+	}{
+		{"pair with one entry", map[string]any{"lg": []any{1.2}}},
+		{"pair with strings", map[string]any{"lg": []any{"a", "b"}}},
+		{"fluid without viewports", map[string]any{"lg": []any{1.0, 1.2}, "$fluid": map[string]any{}}},
+		{"fluid not an object", map[string]any{"lg": []any{1.0, 1.2}, "$fluid": "320px"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dict := &Dictionary{
+				Root: map[string]any{
+					"size": map[string]any{"$value": "1rem", "$scale": tt.scale},
+				},
+				SourceFiles: make(map[string]string),
+			}
+			if err := ExpandScales(dict); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package tokens
 
 import (
 	"fmt"
+	"strings"
 )
 
 // Inherit creates a new dictionary by merging base and theme
@@ -23,7 +24,9 @@ func Inherit(base *Dictionary, theme *Dictionary) (*Dictionary, error) {
 }
 
 // ResolveThemeInheritance resolves the full inheritance chain for all themes
-// Returns a map of theme names to their fully resolved dictionaries
+// Returns a map of theme names to their fully resolved dictionaries. When
+// themes span more than one axis, the map also holds every combination
+// of them (see ThemeParts).
 func ResolveThemeInheritance(base *Dictionary, themes map[string]*Dictionary) (map[string]*Dictionary, error) {
 	resolved := make(map[string]*Dictionary)
 	resolving := make(map[string]bool) // Track currently resolving themes for cycle detection
@@ -91,5 +94,85 @@ func ResolveThemeInheritance(base *Dictionary, themes map[string]*Dictionary) (m
 		}
 	}
 
+	// Compose one theme per axis into every combination
+	if err := composeThemeAxes(base, themes, resolved); err != nil {
+		return nil, err
+	}
+
 	return resolved, nil
 }
+
+// Color schemes a theme can declare with $colorScheme
+const (
+	ColorSchemeLight = "light"
+	ColorSchemeDark  = "dark"
+)
+
+// ColorScheme returns the theme's $colorScheme, "light" or "dark", or ""
+// when it declares none. A theme that $extends another inherits its
+// scheme along with its tokens.
+func (d *Dictionary) ColorScheme() (string, error) {
+	v, ok := d.Root["$colorScheme"]
+	if !ok {
+		return "", nil
+	}
+	switch v {
+	case ColorSchemeLight, ColorSchemeDark:
+		return v.(string), nil
+	}
+	return "", fmt.Errorf("$colorScheme must be %q or %q, got %v", ColorSchemeLight, ColorSchemeDark, v)
+}
+
+// ThemeSelectors returns the selectors a theme's $selector gives its rule,
+// a single selector or a list of them, or nil when it declares none. The
+// selectors are the theme file's own: build reads them from the file
+// rather than the resolved theme, so they do not pass down $extends.
+func (d *Dictionary) ThemeSelectors() ([]string, error) {
+	v, ok := d.Root["$selector"]
+	if !ok {
+		return nil, nil
+	}
+	var selectors []string
+	switch v := v.(type) {
+	case string:
+		selectors = []string{v}
+	case []any:
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("$selector list entries must be strings, got %v", item)
+			}
+			selectors = append(selectors, s)
+		}
+	default:
+		return nil, fmt.Errorf("$selector must be a selector or a list of selectors, got %v", v)
+	}
+	for i, s := range selectors {
+		selectors[i] = strings.TrimSpace(s)
+		if selectors[i] == "" {
+			return nil, fmt.Errorf("$selector must not be empty")
+		}
+	}
+	if len(selectors) == 0 {
+		return nil, fmt.Errorf("$selector must not be empty")
+	}
+	return selectors, nil
+}
+
+// ThemeMedia returns the media query a theme's $media puts its rule
+// under, such as "(prefers-contrast: more)", or "" when it declares none.
+// Like $selector it belongs to the theme file alone.
+func (d *Dictionary) ThemeMedia() (string, error) {
+	v, ok := d.Root["$media"]
+	if !ok {
+		return "", nil
+	}
+	s, ok := v.(string)
+	if !ok || strings.TrimSpace(s) == "" {
+		return "", fmt.Errorf("$media must be a media query such as (prefers-contrast: more), got %v", v)
+	}
+	if s = strings.TrimSpace(s); strings.HasPrefix(s, "@") {
+		return "", fmt.Errorf("$media takes the query alone, without @media: %s", s)
+	}
+	return s, nil
+}
@@ -463,3 +463,108 @@ func TestDiff(t *testing.T) {
 		})
 	}
 }
+
+func TestDictionary_ColorScheme(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		value   any
+		want    string
+		wantErr bool
+	}{
+		{"unset", nil, "", false},
+		{"light", "light", ColorSchemeLight, false},
+		{"dark", "dark", ColorSchemeDark, false},
+		{"unknown scheme", "dim", "", true},
+		{"not a string", true, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			d := NewDictionary()
+			if tt.value != nil {
+				d.Root["$colorScheme"] = tt.value
+			}
+			got, err := d.ColorScheme()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ColorScheme() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ColorScheme() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// A theme extending a dark theme is dark too
+	base := NewDictionary()
+	themes := map[string]*Dictionary{
+		"dark":    {Root: map[string]any{"$colorScheme": "dark"}},
+		"dark-hc": {Root: map[string]any{"$extends": "dark"}},
+	}
+	resolved, err := ResolveThemeInheritance(base, themes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := resolved["dark-hc"].ColorScheme(); got != ColorSchemeDark {
+		t.Errorf("dark-hc ColorScheme() = %q, want dark", got)
+	}
+}
+
+func TestDictionary_ThemeSelectorsAndMedia(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		root      map[string]any
+		selectors []string
+		media     string
+		wantErr   string // validation error path
+	}{
+		{"unset", map[string]any{}, nil, "", ""},
+		{"one selector", map[string]any{"$selector": " .theme-dark "}, []string{".theme-dark"}, "", ""},
+		{"selector list", map[string]any{"$selector": []any{".dark", ":host([theme=dark])"}}, []string{".dark", ":host([theme=dark])"}, "", ""},
+		{"media", map[string]any{"$media": "(prefers-contrast: more)"}, nil, "(prefers-contrast: more)", ""},
+		{"empty selector", map[string]any{"$selector": ""}, nil, "", "$selector"},
+		{"empty list", map[string]any{"$selector": []any{}}, nil, "", "$selector"},
+		{"non-string entry", map[string]any{"$selector": []any{".dark", 1}}, nil, "", "$selector"},
+		{"media with at-rule", map[string]any{"$media": "@media print"}, nil, "", "$media"},
+		{"media not a string", map[string]any{"$media": true}, nil, "", "$media"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			d := NewDictionary()
+			d.Root = tt.root
+			selectors, selErr := d.ThemeSelectors()
+			media, mediaErr := d.ThemeMedia()
+			errs, err := Validate(d)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.wantErr != "" {
+				if selErr == nil && mediaErr == nil {
+					t.Error("expected an error")
+				}
+				if len(errs) != 1 || errs[0].Path != tt.wantErr {
+					t.Errorf("Validate() = %v, want one error at %s", errs, tt.wantErr)
+				}
+				return
+			}
+			if selErr != nil || mediaErr != nil || len(errs) != 0 {
+				t.Fatalf("unexpected errors: %v, %v, %v", selErr, mediaErr, errs)
+			}
+			if !reflect.DeepEqual(selectors, tt.selectors) {
+				t.Errorf("ThemeSelectors() = %v, want %v", selectors, tt.selectors)
+			}
+			if media != tt.media {
+				t.Errorf("ThemeMedia() = %q, want %q", media, tt.media)
+			}
+		})
+	}
+}
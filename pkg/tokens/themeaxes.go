@@ -0,0 +1,137 @@
+// tokenctl/pkg/tokens/themeaxes.go
+
+package tokens
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ThemeAxisMode is the axis of the themes selected with data-theme: files
+// directly in the themes directory or in themes/mode/. Every other
+// subdirectory of themes/ is an axis of its own, selected with
+// data-<axis>, and its files are named "axis/value".
+const ThemeAxisMode = "mode"
+
+// ThemePart is one axis value a theme is made of
+type ThemePart struct {
+	Axis  string
+	Value string
+}
+
+// ThemeParts splits a theme name into its axis values. "dark" is dark on
+// the mode axis, "brand/acme" is acme on the brand axis, and a combination
+// such as "dark+brand/acme" has one part per axis, mode first.
+func ThemeParts(name string) []ThemePart {
+	var parts []ThemePart
+	for _, part := range strings.Split(name, "+") {
+		axis, value, ok := strings.Cut(part, "/")
+		if !ok {
+			axis, value = ThemeAxisMode, part
+		}
+		parts = append(parts, ThemePart{Axis: axis, Value: value})
+	}
+	return parts
+}
+
+// Name returns the name of the single-axis theme a part refers to
+func (p ThemePart) Name() string {
+	if p.Axis == ThemeAxisMode {
+		return p.Value
+	}
+	return p.Axis + "/" + p.Value
+}
+
+// ThemeAttribute returns the data attribute that selects a value on axis
+func ThemeAttribute(axis string) string {
+	if axis == ThemeAxisMode {
+		return "data-theme"
+	}
+	return "data-" + axis
+}
+
+// composeThemeAxes adds a theme for every combination of values taken
+// from two or more axes to resolved, named by joining the values with
+// "+" in composition order: the mode theme first, then the other axes
+// alphabetically, each file overriding the ones before it. Without a
+// mode value a combination starts from the base, which is only
+// needed when no mode theme is the default.
+func composeThemeAxes(base *Dictionary, themes, resolved map[string]*Dictionary) error {
+	axes := make(map[string][]string) // axis -> theme names
+	for name := range themes {
+		axis := ThemeParts(name)[0].Axis
+		axes[axis] = append(axes[axis], name)
+	}
+	if len(axes) < 2 {
+		return nil
+	}
+
+	axisNames := make([]string, 0, len(axes))
+	for axis, names := range axes {
+		sort.Strings(names)
+		if axis != ThemeAxisMode {
+			axisNames = append(axisNames, axis)
+		}
+	}
+	sort.Strings(axisNames)
+
+	// "" leaves an axis unset
+	var choices [][]string
+	if modes, ok := axes[ThemeAxisMode]; ok {
+		if _, ok := themes[DetectDefaultTheme(themes)]; ok {
+			choices = append(choices, modes)
+		} else {
+			choices = append(choices, append([]string{""}, modes...))
+		}
+	}
+	for _, axis := range axisNames {
+		choices = append(choices, append([]string{""}, axes[axis]...))
+	}
+
+	var compose func(picked []string, rest [][]string) error
+	compose = func(picked []string, rest [][]string) error {
+		if len(rest) > 0 {
+			for _, name := range rest[0] {
+				next := picked
+				if name != "" {
+					next = append(append([]string(nil), picked...), name)
+				}
+				if err := compose(next, rest[1:]); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		if len(picked) < 2 {
+			return nil
+		}
+
+		result := base
+		for _, name := range picked {
+			for _, layer := range themeChain(themes, name) {
+				merged, err := Inherit(result, layer)
+				if err != nil {
+					return fmt.Errorf("failed to compose theme '%s': %w", strings.Join(picked, "+"), err)
+				}
+				result = merged
+			}
+		}
+		resolved[strings.Join(picked, "+")] = result
+		return nil
+	}
+	return compose(nil, choices)
+}
+
+// themeChain returns a theme file and the files it $extends, the
+// farthest ancestor first. ResolveThemeInheritance has already rejected
+// missing parents and cycles.
+func themeChain(themes map[string]*Dictionary, name string) []*Dictionary {
+	var chain []*Dictionary
+	for name != "" {
+		theme := themes[name]
+		chain = append([]*Dictionary{theme}, chain...)
+		name, _ = theme.Root["$extends"].(string)
+	}
+	return chain
+}
@@ -0,0 +1,177 @@
+// tokenctl/pkg/tokens/themeaxes_test.go
+
+package tokens
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestThemeParts(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		want []ThemePart
+	}{
+		{"dark", []ThemePart{{ThemeAxisMode, "dark"}}},
+		{"brand/acme", []ThemePart{{"brand", "acme"}}},
+		{"dark+brand/acme+density/compact", []ThemePart{{ThemeAxisMode, "dark"}, {"brand", "acme"}, {"density", "compact"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := ThemeParts(tt.name); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ThemeParts(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoader_LoadThemes_Axes(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"themes/light.json":           `{"$default": true}`,
+		"themes/mode/dark.json":       `{"dark": {"color": {"primary": {"$value": "#000"}}}}`,
+		"themes/brand/acme.json":      `{"acme": {"color": {"primary": {"$value": "#e11d48"}}}}`,
+		"themes/density/compact.json": `{"spacing": {"md": {"$value": "0.5rem"}}}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	themes, err := NewLoader().LoadThemes(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadThemes failed: %v", err)
+	}
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	want := []string{"brand/acme", "dark", "density/compact", "light"}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("themes = %v, want %v", names, want)
+	}
+	if _, ok := themes["brand/acme"].Root["color"]; !ok {
+		t.Errorf("axis theme not unwrapped by its value: %v", themes["brand/acme"].Root)
+	}
+	if got := DetectDefaultTheme(themes); got != "light" {
+		t.Errorf("DetectDefaultTheme = %q, want light", got)
+	}
+}
+
+func TestResolveThemeInheritance_Axes(t *testing.T) {
+	t.Parallel()
+	theme := func(root map[string]any) *Dictionary {
+		d := NewDictionary()
+		d.Root = root
+		return d
+	}
+	color := func(path, value string) map[string]any {
+		return map[string]any{"color": map[string]any{path: map[string]any{"$value": value}}}
+	}
+
+	base := theme(map[string]any{"color": map[string]any{
+		"$type":   "color",
+		"primary": map[string]any{"$value": "#3b82f6"},
+		"text":    map[string]any{"$value": "#111111"},
+	}})
+
+	tests := []struct {
+		name   string
+		themes map[string]*Dictionary
+		want   map[string]string // theme -> color.primary/color.text
+	}{
+		{
+			name: "default mode is always picked",
+			themes: map[string]*Dictionary{
+				"light":           theme(map[string]any{"$default": true}),
+				"dark":            theme(color("text", "#eeeeee")),
+				"brand/acme":      theme(color("primary", "#e11d48")),
+				"density/compact": theme(map[string]any{}),
+			},
+			want: map[string]string{
+				"light+brand/acme":                 "#e11d48/#111111",
+				"dark+brand/acme":                  "#e11d48/#eeeeee",
+				"dark+density/compact":             "#3b82f6/#eeeeee",
+				"light+density/compact":            "#3b82f6/#111111",
+				"light+brand/acme+density/compact": "#e11d48/#111111",
+				"dark+brand/acme+density/compact":  "#e11d48/#eeeeee",
+			},
+		},
+		{
+			name: "later axes override earlier ones",
+			themes: map[string]*Dictionary{
+				"dark":       theme(color("primary", "#60a5fa")),
+				"brand/acme": theme(color("primary", "#e11d48")),
+				"brand/zeta": theme(map[string]any{"$extends": "brand/acme"}),
+			},
+			want: map[string]string{
+				"dark+brand/acme": "#e11d48/#111111",
+				"dark+brand/zeta": "#e11d48/#111111",
+			},
+		},
+		{
+			name: "without a default mode theme axes combine on the base",
+			themes: map[string]*Dictionary{
+				"dark":            theme(color("text", "#eeeeee")),
+				"brand/acme":      theme(color("primary", "#e11d48")),
+				"density/compact": theme(map[string]any{}),
+			},
+			want: map[string]string{
+				"brand/acme+density/compact":      "#e11d48/#111111",
+				"dark+brand/acme":                 "#e11d48/#eeeeee",
+				"dark+brand/acme+density/compact": "#e11d48/#eeeeee",
+				"dark+density/compact":            "#3b82f6/#eeeeee",
+			},
+		},
+		{
+			name: "one axis composes nothing",
+			themes: map[string]*Dictionary{
+				"light": theme(map[string]any{}),
+				"dark":  theme(color("text", "#eeeeee")),
+			},
+			want: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			resolved, err := ResolveThemeInheritance(base, tt.themes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := len(resolved) - len(tt.themes); got != len(tt.want) {
+				t.Errorf("got %d combinations, want %d: %v", got, len(tt.want), resolved)
+			}
+			for name, want := range tt.want {
+				dict, ok := resolved[name]
+				if !ok {
+					t.Errorf("missing combination %s", name)
+					continue
+				}
+				resolver, err := NewResolver(dict)
+				if err != nil {
+					t.Fatal(err)
+				}
+				values, err := resolver.ResolveAll()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := values["color.primary"].(string) + "/" + values["color.text"].(string); got != want {
+					t.Errorf("%s: primary/text = %s, want %s", name, got, want)
+				}
+			}
+		})
+	}
+}
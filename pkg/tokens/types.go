@@ -11,7 +11,9 @@ import "maps"
 // and helper methods to traverse it.
 type Dictionary struct {
 	Root        map[string]any
-	SourceFiles map[string]string // Maps token path to source file
+	SourceFiles map[string]string   // Maps token path to source file
+	Overrides   map[string][]string // Maps token path to earlier source files it replaced, oldest first
+	Positions   map[string]Position // Maps the dot path of every key to where it was defined
 }
 
 // NewDictionary creates an empty dictionary
@@ -19,6 +21,8 @@ func NewDictionary() *Dictionary {
 	return &Dictionary{
 		Root:        make(map[string]any),
 		SourceFiles: make(map[string]string),
+		Overrides:   make(map[string][]string),
+		Positions:   make(map[string]Position),
 	}
 }
 
@@ -32,9 +36,17 @@ func IsToken(node map[string]any) bool {
 func (d *Dictionary) DeepCopy() *Dictionary {
 	copiedSourceFiles := make(map[string]string, len(d.SourceFiles))
 	maps.Copy(copiedSourceFiles, d.SourceFiles)
+	copiedOverrides := make(map[string][]string, len(d.Overrides))
+	for path, files := range d.Overrides {
+		copiedOverrides[path] = append([]string(nil), files...)
+	}
+	copiedPositions := make(map[string]Position, len(d.Positions))
+	maps.Copy(copiedPositions, d.Positions)
 	return &Dictionary{
 		Root:        deepCopyMap(d.Root),
 		SourceFiles: copiedSourceFiles,
+		Overrides:   copiedOverrides,
+		Positions:   copiedPositions,
 	}
 }
 
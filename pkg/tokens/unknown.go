@@ -42,13 +42,16 @@ type Finding struct {
 	Path       string // dot path to the offending key
 	Key        string // the key itself
 	SourceFile string // file the key was read from, when known
+	Line       int    // 1-based line of the key, when known
+	Column     int    // 1-based column of the key, when known
 	Hint       string // what the author probably meant
 }
 
 func (f Finding) String() string {
 	loc := f.Path
 	if f.SourceFile != "" {
-		loc = fmt.Sprintf("%s (%s)", f.Path, f.SourceFile)
+		pos := Position{File: f.SourceFile, Line: f.Line, Column: f.Column}
+		loc = fmt.Sprintf("%s (%s)", f.Path, pos)
 	}
 	if f.Hint != "" {
 		return fmt.Sprintf("%s: %s — %s", f.Kind, loc, f.Hint)
@@ -69,31 +72,46 @@ var componentSubBlocks = map[string]bool{
 // Adding a feature that reads a new $key means adding it here, otherwise
 // the audit will call the feature's own input unknown.
 var knownMetadataKeys = map[string]bool{
-	"$avoid":        true,
-	"$breakpoints":  true,
-	"$class":        true,
-	"$container":    true,
-	"$contains":     true,
-	"$customizable": true,
-	"$default":      true,
-	"$deprecated":   true,
-	"$desc":         true,
-	"$description":  true,
-	"$extends":      true,
-	"$extensions":   true,
-	"$layer":        true,
-	"$max":          true,
-	"$meta":         true,
-	"$min":          true,
-	"$property":     true,
-	"$requires":     true,
-	"$responsive":   true,
-	"$scale":        true,
-	"$schema":       true,
-	"$type":         true,
-	"$usage":        true,
-	"$value":        true,
-	"$version":      true,
+	"$avoid":         true,
+	"$backdrop":      true,
+	"$breakpoints":   true,
+	"$class":         true,
+	"$colorFormat":   true,
+	"$colorScheme":   true,
+	"$container":     true,
+	"$contains":      true,
+	"$contrastWith":  true,
+	"$customizable":  true,
+	"$default":       true,
+	"$deprecated":    true,
+	"$desc":          true,
+	"$description":   true,
+	"$extends":       true,
+	"$extensions":    true,
+	"$forcedColors":  true,
+	"$layer":         true,
+	"$max":           true,
+	"$meta":          true,
+	"$min":           true,
+	"$minContrast":   true,
+	"$property":      true,
+	"$reducedMotion": true,
+	"$requires":      true,
+	"$responsive":    true,
+	"$rootFontSize":  true,
+	"$scale":         true,
+	"$schema":        true,
+	"$type":          true,
+	"$usage":         true,
+	"$value":         true,
+	"$version":       true,
+}
+
+// themeRootKeys are read only at the root of a theme file, which may sit
+// one level down when the file wraps its content in the theme's name
+var themeRootKeys = map[string]bool{
+	"$media":    true,
+	"$selector": true,
 }
 
 // IsCommentKey reports whether a key is an in-file comment rather than
@@ -117,6 +135,14 @@ func AuditUnknownKeys(dict *Dictionary) []Finding {
 	}
 	var findings []Finding
 	auditNode(dict.Root, "", dict, &findings)
+	for i := range findings {
+		if pos, ok := dict.Positions[findings[i].Path]; ok {
+			findings[i].Line, findings[i].Column = pos.Line, pos.Column
+			if pos.File != "" {
+				findings[i].SourceFile = pos.File
+			}
+		}
+	}
 	sort.Slice(findings, func(i, j int) bool {
 		if findings[i].Path != findings[j].Path {
 			return findings[i].Path < findings[j].Path
@@ -152,7 +178,7 @@ func auditNode(node map[string]any, path string, dict *Dictionary, out *[]Findin
 		}
 
 		if strings.HasPrefix(key, "$") {
-			if !knownMetadataKeys[key] {
+			if !knownMetadataKeys[key] && !(themeRootKeys[key] && !strings.Contains(path, ".")) {
 				*out = append(*out, Finding{
 					Kind:       FindingUnknownMetadataKey,
 					Path:       child,
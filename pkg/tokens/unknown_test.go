@@ -240,3 +240,18 @@ func TestAuditUnknownKeys_NilDictionary(t *testing.T) {
 		t.Errorf("nil dictionary should produce no findings, got %v", findings)
 	}
 }
+
+func TestAuditUnknownKeys_ThemeRootKeys(t *testing.T) {
+	t.Parallel()
+
+	d := dictFromJSON(t, `{
+      "$selector": ".theme-dark",
+      "dark": { "$media": "(prefers-contrast: more)" },
+      "color": { "primary": { "$value": "#000", "$selector": ".x" } }
+    }`)
+
+	findings := AuditUnknownKeys(d)
+	if len(findings) != 1 || findings[0].Path != "color.primary.$selector" {
+		t.Errorf("want only the token's $selector flagged, got %v", findings)
+	}
+}
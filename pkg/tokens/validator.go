@@ -3,6 +3,7 @@
 package tokens
 
 import (
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
@@ -14,21 +15,40 @@ import (
 type ValidationError struct {
 	Path       string
 	Message    string
+	Rule       string // Diagnostic rule ID, e.g. RuleInvalidValue
 	SourceFile string // Optional: file where the token was defined
+	Line       int    // Optional: 1-based line of the offending key
+	Column     int    // Optional: 1-based column of the offending key
 }
 
 func (v ValidationError) Error() string {
 	if v.SourceFile != "" {
-		return fmt.Sprintf("%s [%s]: %s", v.Path, v.SourceFile, v.Message)
+		loc := Position{File: v.SourceFile, Line: v.Line, Column: v.Column}
+		return fmt.Sprintf("%s [%s]: %s", v.Path, loc, v.Message)
 	}
 	return fmt.Sprintf("%s: %s", v.Path, v.Message)
 }
 
+// newValidationError builds an error for path located at its $value, or
+// at the key itself when the value has no recorded position
+func newValidationError(dict *Dictionary, path, rule, message string) ValidationError {
+	pos := dict.valuePosition(path)
+	return ValidationError{
+		Path:       path,
+		Message:    message,
+		Rule:       rule,
+		SourceFile: pos.File,
+		Line:       pos.Line,
+		Column:     pos.Column,
+	}
+}
+
 // Validate checks the dictionary for:
 // 1. Broken references (using Resolver)
 // 2. Schema compliance (basic checks)
-// 3. Type-specific validation (color, dimension, number, effect)
+// 3. Type-specific validation (color, dimension, number, effect, composites)
 // 4. Constraint validation ($min/$max)
+// 5. $forcedColors entries
 func Validate(d *Dictionary) ([]ValidationError, error) {
 	var errs []ValidationError
 
@@ -51,14 +71,7 @@ func Validate(d *Dictionary) ([]ValidationError, error) {
 		val := r.flatTokens[path]
 		_, err := r.ResolveValue(path, val)
 		if err != nil {
-			verr := ValidationError{
-				Path:    path,
-				Message: err.Error(),
-			}
-			if sourceFile, ok := d.SourceFiles[path]; ok {
-				verr.SourceFile = sourceFile
-			}
-			errs = append(errs, verr)
+			errs = append(errs, newValidationError(d, path, resolveRule(err), err.Error()))
 		}
 	}
 
@@ -68,6 +81,20 @@ func Validate(d *Dictionary) ([]ValidationError, error) {
 	// 3. Type-specific and constraint validation
 	errs = append(errs, validateTypes(d, d.Root, "")...)
 
+	// 4. $forcedColors must map tokens to system colors
+	if _, err := d.ForcedColors(); err != nil {
+		msg := strings.TrimPrefix(err.Error(), "$forcedColors: ")
+		errs = append(errs, newValidationError(d, "$forcedColors", RuleInvalidValue, msg))
+	}
+
+	// 5. A theme's $selector and $media must be usable in a rule
+	if _, err := d.ThemeSelectors(); err != nil {
+		errs = append(errs, newValidationError(d, "$selector", RuleInvalidValue, strings.TrimPrefix(err.Error(), "$selector ")))
+	}
+	if _, err := d.ThemeMedia(); err != nil {
+		errs = append(errs, newValidationError(d, "$media", RuleInvalidValue, strings.TrimPrefix(err.Error(), "$media ")))
+	}
+
 	return errs, nil
 }
 
@@ -89,14 +116,7 @@ func validateSchema(dict *Dictionary, node map[string]any, currentPath string) [
 			if currentPath != "" {
 				childPath = currentPath + "." + key
 			}
-			verr := ValidationError{
-				Path:    childPath,
-				Message: fmt.Sprintf("expected object, got %T", val),
-			}
-			if sourceFile, ok := dict.SourceFiles[childPath]; ok {
-				verr.SourceFile = sourceFile
-			}
-			errs = append(errs, verr)
+			errs = append(errs, newValidationError(dict, childPath, RuleInvalidStructure, fmt.Sprintf("expected object, got %T", val)))
 			continue
 		}
 
@@ -126,6 +146,13 @@ func validateTypesWithInheritance(dict *Dictionary, node map[string]any, current
 	if t, ok := node["$type"].(string); ok {
 		currentType = t
 	}
+	if raw, ok := node["$colorFormat"]; ok {
+		name, _ := raw.(string)
+		if _, err := colors.ParseFormat(name); err != nil {
+			errs = append(errs, newValidationError(dict, currentPath, RuleInvalidValue, fmt.Sprintf("invalid $colorFormat: %s", err.Error())))
+		}
+	}
+	errs = append(errs, validateReducedMotion(dict, currentPath, node, currentType)...)
 
 	if IsToken(node) {
 		// Get token type (from token itself or inherited)
@@ -136,67 +163,39 @@ func validateTypesWithInheritance(dict *Dictionary, node map[string]any, current
 		switch tokenType {
 		case "color":
 			if err := validateColorFormat(value); err != nil {
-				verr := ValidationError{
-					Path:    currentPath,
-					Message: fmt.Sprintf("invalid color: %s", err.Error()),
-				}
-				if sourceFile, ok := dict.SourceFiles[currentPath]; ok {
-					verr.SourceFile = sourceFile
-				}
-				errs = append(errs, verr)
+				errs = append(errs, newValidationError(dict, currentPath, RuleInvalidValue, fmt.Sprintf("invalid color: %s", err.Error())))
 			}
 
 		case "dimension":
 			if err := validateDimension(value); err != nil {
-				verr := ValidationError{
-					Path:    currentPath,
-					Message: fmt.Sprintf("invalid dimension: %s", err.Error()),
-				}
-				if sourceFile, ok := dict.SourceFiles[currentPath]; ok {
-					verr.SourceFile = sourceFile
-				}
-				errs = append(errs, verr)
+				errs = append(errs, newValidationError(dict, currentPath, RuleInvalidValue, fmt.Sprintf("invalid dimension: %s", err.Error())))
 			}
 
 		case "number":
 			if err := validateNumber(value); err != nil {
-				verr := ValidationError{
-					Path:    currentPath,
-					Message: fmt.Sprintf("invalid number: %s", err.Error()),
-				}
-				if sourceFile, ok := dict.SourceFiles[currentPath]; ok {
-					verr.SourceFile = sourceFile
-				}
-				errs = append(errs, verr)
+				errs = append(errs, newValidationError(dict, currentPath, RuleInvalidValue, fmt.Sprintf("invalid number: %s", err.Error())))
 			}
 
 		case "fontFamily":
 			if err := validateFontFamily(value); err != nil {
-				verr := ValidationError{
-					Path:    currentPath,
-					Message: fmt.Sprintf("invalid fontFamily: %s", err.Error()),
-				}
-				if sourceFile, ok := dict.SourceFiles[currentPath]; ok {
-					verr.SourceFile = sourceFile
-				}
-				errs = append(errs, verr)
+				errs = append(errs, newValidationError(dict, currentPath, RuleInvalidValue, fmt.Sprintf("invalid fontFamily: %s", err.Error())))
 			}
 
 		case "effect":
 			if err := validateEffect(value); err != nil {
-				verr := ValidationError{
-					Path:    currentPath,
-					Message: fmt.Sprintf("invalid effect: %s", err.Error()),
-				}
-				if sourceFile, ok := dict.SourceFiles[currentPath]; ok {
-					verr.SourceFile = sourceFile
-				}
-				errs = append(errs, verr)
+				errs = append(errs, newValidationError(dict, currentPath, RuleInvalidValue, fmt.Sprintf("invalid effect: %s", err.Error())))
+			}
+
+		case TypeShadow, TypeBorder, TypeTypography, TypeTransition,
+			TypeGradient, TypeCubicBezier, TypeStrokeStyle:
+			if err := validateComposite(tokenType, value); err != nil {
+				errs = append(errs, newValidationError(dict, currentPath, RuleInvalidValue, fmt.Sprintf("invalid %s: %s", tokenType, err.Error())))
 			}
 		}
 
 		// Constraint validation ($min/$max)
 		errs = append(errs, validateConstraints(dict, currentPath, node, value)...)
+		errs = append(errs, validateContrastDeclaration(dict, currentPath, node)...)
 
 		return errs
 	}
@@ -237,37 +236,25 @@ func validateConstraints(dict *Dictionary, path string, token map[string]any, va
 
 	constraint, err := ParseConstraints(token)
 	if err != nil {
-		verr := ValidationError{
-			Path:    path,
-			Message: fmt.Sprintf("constraint error: %s", err.Error()),
-		}
-		if sourceFile, ok := dict.SourceFiles[path]; ok {
-			verr.SourceFile = sourceFile
-		}
-		errs = append(errs, verr)
+		errs = append(errs, newValidationError(dict, path, RuleConstraintViolation, fmt.Sprintf("constraint error: %s", err.Error())))
 		return errs
 	}
 
 	if constraint == nil {
 		return errs
 	}
+	// An invalid $rootFontSize is reported by the resolver
+	constraint.RootFontSize, _ = dict.RootFontSize()
 
-	// Skip constraint checking for reference values (they'll be checked after resolution)
+	// Skip constraint checking for reference and expression values (they'll be checked after resolution)
 	if strVal, ok := value.(string); ok {
-		if strings.Contains(strVal, "{") && strings.Contains(strVal, "}") {
+		if strings.Contains(strVal, "{") && strings.Contains(strVal, "}") || IsExpression(strVal) {
 			return errs
 		}
 	}
 
 	if err := constraint.CheckValue(value); err != nil {
-		verr := ValidationError{
-			Path:    path,
-			Message: fmt.Sprintf("constraint violation: %s", err.Error()),
-		}
-		if sourceFile, ok := dict.SourceFiles[path]; ok {
-			verr.SourceFile = sourceFile
-		}
-		errs = append(errs, verr)
+		errs = append(errs, newValidationError(dict, path, RuleConstraintViolation, fmt.Sprintf("constraint violation: %s", err.Error())))
 	}
 
 	return errs
@@ -286,15 +273,15 @@ func validateColorFormat(value any) error {
 	}
 
 	// Skip validation for expression values (evaluated during resolution)
-	if strings.HasPrefix(strVal, "contrast(") ||
-		strings.HasPrefix(strVal, "darken(") ||
-		strings.HasPrefix(strVal, "lighten(") ||
-		strings.HasPrefix(strVal, "shade(") {
+	if IsExpression(strVal) {
 		return nil
 	}
 
-	_, err := colors.Parse(strVal)
-	return err
+	// Relative colors from var() are computed by the browser
+	if _, err := colors.Parse(strVal); err != nil && !errors.Is(err, colors.ErrUnresolvedOrigin) {
+		return err
+	}
+	return nil
 }
 
 // validateDimension ensures a dimension value has valid format and units
@@ -316,8 +303,8 @@ func validateDimension(value any) error {
 		return nil
 	}
 
-	// Skip validation for calc() expressions
-	if strings.HasPrefix(strVal, "calc(") || strings.HasPrefix(strVal, "scale(") {
+	// Skip validation for calc(), scale(), fluid() expressions
+	if IsExpression(strVal) {
 		return nil
 	}
 
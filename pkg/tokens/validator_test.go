@@ -435,6 +435,11 @@ func TestValidator_TypeValidation_Color(t *testing.T) {
 		{"valid hsl", "hsl(180, 50%, 50%)", false},
 		{"valid oklch", "oklch(50% 0.2 180)", false},
 		{"valid named", "red", false},
+		{"valid lab", "lab(54.29% 80.8 69.89)", false},
+		{"valid display-p3", "color(display-p3 1 0 0 / 50%)", false},
+		{"valid relative color", "oklch(from #3b82f6 calc(l - 0.1) c h)", false},
+		{"relative color from var", "oklch(from var(--brand) calc(l - 0.1) c h)", false},
+		{"invalid relative color", "oklch(from var(--brand) l c x)", true},
 		{"invalid color", "not-a-color", true},
 		{"reference skipped", "{color.base}", false},
 		{"contrast expression skipped", "contrast({color.base})", false},
@@ -475,6 +480,47 @@ func TestValidator_TypeValidation_Color(t *testing.T) {
 	}
 }
 
+func TestValidator_ColorFormat(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		value        any
+		expectErrors bool
+	}{
+		{"valid format", "oklch", false},
+		{"display-p3 alias", "display-p3", false},
+		{"unknown format", "cmyk", true},
+		{"not a string", 3, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dict := &Dictionary{
+				Root: map[string]any{
+					"color": map[string]any{
+						"$colorFormat": tt.value,
+						"base":         map[string]any{"$value": "#3b82f6", "$type": "color"},
+					},
+				},
+			}
+
+			errors, err := Validate(dict)
+			if err != nil {
+				t.Fatalf("Validation failed to run: %v", err)
+			}
+
+			hasErrors := len(errors) > 0
+			if tt.expectErrors && !hasErrors {
+				t.Error("Expected validation errors, got none")
+			}
+			if !tt.expectErrors && hasErrors {
+				t.Errorf("Expected no validation errors, got: %v", errors)
+			}
+		})
+	}
+}
+
 func TestValidator_TypeValidation_Dimension(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -714,6 +760,16 @@ func TestValidator_ConstraintValidation(t *testing.T) {
 			expectErrors: true,
 			errContains:  "greater than maximum",
 		},
+		{
+			name: "quarter-millimeters in range",
+			token: map[string]any{
+				"$value": "10q",
+				"$type":  "dimension",
+				"$min":   "4q",
+				"$max":   "40q",
+			},
+			expectErrors: false,
+		},
 		{
 			name: "number in range",
 			token: map[string]any{